@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/igorcrevar/cardano-go-indexer/core"
+)
+
+// Broker is in-memory core.BrokerPublisher. It is useful for tests and as a stand-in for a real broker
+type Broker struct {
+	lock     sync.Mutex
+	messages map[string][]*core.BrokerMessage
+	err      error
+}
+
+var _ core.BrokerPublisher = (*Broker)(nil)
+
+func NewBroker() *Broker {
+	return &Broker{
+		messages: map[string][]*core.BrokerMessage{},
+	}
+}
+
+func (mb *Broker) Publish(ctx context.Context, messages []*core.BrokerMessage) error {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+
+	if mb.err != nil {
+		return mb.err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for _, msg := range messages {
+		mb.messages[msg.Topic] = append(mb.messages[msg.Topic], msg)
+	}
+
+	return nil
+}
+
+// SetError makes every next publish fail with the error (nil restores publishing)
+func (mb *Broker) SetError(err error) {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+
+	mb.err = err
+}
+
+// Messages returns all published messages of the topic in order
+func (mb *Broker) Messages(topic string) []*core.BrokerMessage {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+
+	return append([]*core.BrokerMessage(nil), mb.messages[topic]...)
+}
+
+func (mb *Broker) Close() error {
+	return nil
+}
@@ -0,0 +1,109 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/igorcrevar/cardano-go-indexer/core"
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	dialTimeoutDefault   = 10 * time.Second
+	reconnectWaitDefault = 2 * time.Second
+	clientName           = "cardano-go-indexer"
+)
+
+type PublisherConfig struct {
+	// NATS server url (comma separated list for a cluster), e.g. nats://127.0.0.1:4222
+	URL string `json:"url"`
+	// optional authentication token
+	Token string `json:"token"`
+	// timeout of connecting to the server
+	DialTimeout time.Duration `json:"dialTimeout"`
+	// delay between reconnect attempts. Publisher reconnects until it is closed
+	ReconnectWait time.Duration `json:"reconnectWait"`
+}
+
+// Publisher publishes messages to NATS JetStream. Every message is acknowledged by the stream
+// bound to its subject. Message key is sent as the message id, so the stream deduplicates redelivered messages
+type Publisher struct {
+	conn   *natsgo.Conn
+	js     jetstream.JetStream
+	logger hclog.Logger
+}
+
+var _ core.BrokerPublisher = (*Publisher)(nil)
+
+// NewPublisher connects to the NATS server. Lost connection is reestablished in the background
+// and messages published in the meantime are sent after reconnect
+func NewPublisher(config PublisherConfig, logger hclog.Logger) (*Publisher, error) {
+	dialTimeout := config.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = dialTimeoutDefault
+	}
+
+	reconnectWait := config.ReconnectWait
+	if reconnectWait <= 0 {
+		reconnectWait = reconnectWaitDefault
+	}
+
+	options := []natsgo.Option{
+		natsgo.Name(clientName),
+		natsgo.Timeout(dialTimeout),
+		natsgo.MaxReconnects(-1),
+		natsgo.ReconnectWait(reconnectWait),
+		natsgo.DisconnectErrHandler(func(_ *natsgo.Conn, err error) {
+			logger.Warn("Disconnected from nats server", "err", err)
+		}),
+		natsgo.ReconnectHandler(func(conn *natsgo.Conn) {
+			logger.Info("Reconnected to nats server", "url", conn.ConnectedUrl())
+		}),
+	}
+
+	if config.Token != "" {
+		options = append(options, natsgo.Token(config.Token))
+	}
+
+	conn, err := natsgo.Connect(config.URL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to nats server: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+
+		return nil, fmt.Errorf("could not create jetstream context: %w", err)
+	}
+
+	return &Publisher{
+		conn:   conn,
+		js:     js,
+		logger: logger,
+	}, nil
+}
+
+// Publish publishes messages in order and returns nil only after the stream acknowledged all of them
+func (p *Publisher) Publish(ctx context.Context, messages []*core.BrokerMessage) error {
+	for _, msg := range messages {
+		_, err := p.js.PublishMsg(ctx, &natsgo.Msg{
+			Subject: msg.Topic,
+			Data:    msg.Value,
+		}, jetstream.WithMsgID(msg.Key))
+		if err != nil {
+			return fmt.Errorf("could not publish message %s to %s: %w", msg.Key, msg.Topic, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Publisher) Close() error {
+	p.conn.Close()
+
+	return nil
+}
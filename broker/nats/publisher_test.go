@@ -0,0 +1,180 @@
+package nats
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/igorcrevar/cardano-go-indexer/core"
+	"github.com/stretchr/testify/require"
+)
+
+// natsServerStub speaks enough of the NATS protocol to answer every published message with JetStream PubAck
+type natsServerStub struct {
+	listener   net.Listener
+	messagesCh chan *core.BrokerMessage
+	seq        atomic.Uint64
+	// next published message is answered with JetStream error
+	failNext atomic.Bool
+	// connection is closed instead of answering the next published message
+	disconnectNext atomic.Bool
+}
+
+func startNatsServerStub(t *testing.T) *natsServerStub {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	stub := &natsServerStub{
+		listener:   listener,
+		messagesCh: make(chan *core.BrokerMessage, 100),
+	}
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			stub.serve(conn)
+		}
+	}()
+
+	return stub
+}
+
+func (s *natsServerStub) URL() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *natsServerStub) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	// sid of the subscription by subject prefix (reply inbox is a wildcard subscription)
+	subscriptions := map[string]string{}
+
+	_, _ = fmt.Fprintf(conn, "INFO {\"server_id\":\"stub\",\"version\":\"2.10.0\",\"proto\":1,"+
+		"\"headers\":true,\"jetstream\":true,\"max_payload\":1048576}\r\n")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+
+		switch parts[0] {
+		case "PING":
+			_, _ = conn.Write([]byte("PONG\r\n"))
+		case "SUB":
+			subscriptions[strings.TrimSuffix(parts[1], "*")] = parts[len(parts)-1]
+		case "HPUB":
+			var headerLen, totalLen int
+
+			_, _ = fmt.Sscanf(parts[3]+" "+parts[4], "%d %d", &headerLen, &totalLen)
+
+			data := make([]byte, totalLen+2)
+			if _, err := io.ReadFull(reader, data); err != nil {
+				return
+			}
+
+			if s.disconnectNext.CompareAndSwap(true, false) {
+				return
+			}
+
+			key := strings.TrimSpace(strings.Split(string(data[:headerLen]), "Nats-Msg-Id:")[1])
+			ack := fmt.Sprintf(`{"stream":"CARDANO","seq":%d}`, s.seq.Add(1))
+
+			if s.failNext.CompareAndSwap(true, false) {
+				ack = `{"error":{"code":503,"err_code":10077,"description":"stream is offline"}}`
+			} else {
+				s.messagesCh <- &core.BrokerMessage{Topic: parts[1], Key: key, Value: data[headerLen:totalLen]}
+			}
+
+			reply := parts[2]
+
+			for prefix, sid := range subscriptions {
+				if strings.HasPrefix(reply, prefix) {
+					_, _ = fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", reply, sid, len(ack), ack)
+				}
+			}
+		}
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	t.Parallel()
+
+	stub := startNatsServerStub(t)
+
+	publisher, err := NewPublisher(PublisherConfig{
+		URL:           stub.URL(),
+		ReconnectWait: 10 * time.Millisecond,
+	}, hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	defer publisher.Close()
+
+	messages := []*core.BrokerMessage{
+		{Topic: "cardano.txs", Key: "aa", Value: []byte(`{"type":"txConfirmed"}`)},
+		{Topic: "cardano.blocks", Key: "bb", Value: []byte(`{"type":"blockConfirmed"}`)},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, publisher.Publish(ctx, messages))
+	require.Equal(t, messages[0], <-stub.messagesCh)
+	require.Equal(t, messages[1], <-stub.messagesCh)
+
+	// message is not acknowledged
+	stub.failNext.Store(true)
+
+	err = publisher.Publish(ctx, messages[:1])
+	require.ErrorContains(t, err, "could not publish message aa to cardano.txs")
+	require.ErrorContains(t, err, "stream is offline")
+
+	// connection is lost before ack, so publish fails and message is published again after reconnect
+	stub.disconnectNext.Store(true)
+
+	shortCtx, shortCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer shortCancel()
+
+	require.Error(t, publisher.Publish(shortCtx, messages[:1]))
+	require.NoError(t, publisher.Publish(ctx, messages[:1]))
+	require.Equal(t, messages[0], <-stub.messagesCh)
+}
+
+func TestNewPublisher_Error(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	_, err = NewPublisher(PublisherConfig{
+		URL:         "nats://" + address,
+		DialTimeout: 100 * time.Millisecond,
+	}, hclog.NewNullLogger())
+	require.ErrorContains(t, err, "could not connect to nats server")
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/blinklabs-io/gouroboros/ledger"
@@ -13,10 +14,11 @@ import (
 )
 
 const (
-	AddressCheckNone    = 0               // No flags
-	AddressCheckInputs  = 1 << (iota - 1) // 1 << 0 = 0x00...0001 = 1
-	AddressCheckOutputs                   // 1 << 1 = 0x00...0010 = 2
-	AddressCheckAll     = AddressCheckInputs | AddressCheckOutputs
+	AddressCheckNone            = 0               // No flags
+	AddressCheckInputs          = 1 << (iota - 1) // 1 << 0 = 0x00...0001 = 1
+	AddressCheckOutputs                           // 1 << 1 = 0x00...0010 = 2
+	AddressCheckReferenceInputs                   // 1 << 2 = 0x00...0100 = 4, utxo referenced (not spent) by tx
+	AddressCheckAll             = AddressCheckInputs | AddressCheckOutputs
 )
 
 type BlockIndexerConfig struct {
@@ -28,6 +30,39 @@ type BlockIndexerConfig struct {
 	AddressCheck            int      `json:"addressCheck"`
 	SoftDeleteUtxo          bool     `json:"softDeleteUtxo"`
 	KeepAllTxsHashesInBlock bool     `json:"keepAllTxsHashesInBlock"`
+	// txs with certificates or withdrawals of these stake credentials (hex hash) are also of interest
+	StakeCredentialsOfInterest []string `json:"stakeCredentialsOfInterest"`
+	// txs with certificates of these pools (bech32 pool id) are also of interest
+	PoolsOfInterest []string `json:"poolsOfInterest"`
+	// txs which mint or burn assets of these policies (hex policy id) are also of interest.
+	// Mint events are indexed only for txs of interest, so a policy must be listed here (or all txs must be
+	// of interest) for its supply history to be complete
+	PoliciesOfInterest []string `json:"policiesOfInterest"`
+	// txs with metadata under any of these labels are also of interest
+	MetadataLabelsOfInterest []uint64 `json:"metadataLabelsOfInterest"`
+	// txs with votes of these dreps, their certificates or vote delegations to them (hex credential hash)
+	// are also of interest
+	DRepsOfInterest []string `json:"drepsOfInterest"`
+	// all txs with governance proposals or votes are also of interest
+	KeepAllGovernanceTxs bool `json:"keepAllGovernanceTxs"`
+	// vkey witnesses, redeemers, scripts and datums are stored with every tx of interest.
+	// Witness set which can not be decoded is logged and tx is stored without it
+	KeepTxWitnesses bool `json:"keepTxWitnesses"`
+	// datums of inputs and outputs with datum hash are resolved from the tx witness set and stored.
+	// Datums stay unresolved if witness set can not be decoded
+	ResolveDatumHashes bool `json:"resolveDatumHashes"`
+	// if set, blocks and txs get timestamps (see GetSlotTimeConfig for network presets)
+	SlotTime *SlotTimeConfig `json:"slotTime"`
+	// if set, confirmed block handler is called in a separate routine and failed handler is retried.
+	// Block and its txs are moved to the dead-letter store after the last failed attempt, instead of returning
+	// error to the syncer (which triggers reconnect). Close makes the remaining attempts of queued blocks right away
+	HandlerRetry *RetryConfig `json:"handlerRetry"`
+	// txs of interest of unconfirmed blocks are processed in memory (see GetTentativeBlocks).
+	// Block txs are then retrieved when block header arrives instead of when block is confirmed
+	KeepTentativeTxs bool `json:"keepTentativeTxs"`
+	// if greater than one and syncer supports block ranges, up to this many unconfirmed blocks are retrieved
+	// with one request when the first of them is confirmed. It speeds up catch-up (see also ConfirmationBlockCount)
+	BlockFetchBatchSize uint `json:"blockFetchBatchSize"`
 }
 
 type NewConfirmedBlockHandler func(*CardanoBlock, []*Tx) error
@@ -40,6 +75,20 @@ type BlockIndexer struct {
 	unconfirmedBlocks     infraCommon.CircularQueue[ledger.BlockHeader]
 	confirmedBlockHandler NewConfirmedBlockHandler
 	addressesOfInterest   map[string]bool
+	stakeCredsOfInterest  map[string]bool
+	poolsOfInterest       map[string]bool
+	policiesOfInterest    map[string]bool
+	labelsOfInterest      map[uint64]bool
+	drepsOfInterest       map[string]bool
+	slotTimeConverter     *SlotTimeConverter
+
+	// unconfirmed blocks processed in memory by block hash (only if KeepTentativeTxs is set)
+	tentativeBlocks          map[string]*tentativeBlock
+	tentativeRollBackHandler TentativeRollBackHandler
+	// txs of unconfirmed blocks retrieved ahead of confirmation by block hash
+	prefetchedTxs map[string][]ledger.Transaction
+	// calls handler with retries (only if HandlerRetry is set)
+	handlerRetrier *handlerRetrier
 
 	db BlockIndexerDB
 
@@ -52,8 +101,9 @@ var _ BlockSyncerHandler = (*BlockIndexer)(nil)
 func NewBlockIndexer(
 	config *BlockIndexerConfig, confirmedBlockHandler NewConfirmedBlockHandler, db BlockIndexerDB, logger hclog.Logger,
 ) *BlockIndexer {
-	if config.AddressCheck&AddressCheckAll == 0 {
-		panic("block indexer must at least check outputs or inputs") //nolint:gocritic
+	if config.AddressCheck&(AddressCheckAll|AddressCheckReferenceInputs) == 0 {
+		panic("block indexer must check at least one of AddressCheckInputs, AddressCheckOutputs " + //nolint:gocritic
+			"or AddressCheckReferenceInputs")
 	}
 
 	addressesOfInterest := make(map[string]bool, len(config.AddressesOfInterest))
@@ -61,6 +111,49 @@ func NewBlockIndexer(
 		addressesOfInterest[x] = true
 	}
 
+	stakeCredsOfInterest := make(map[string]bool, len(config.StakeCredentialsOfInterest))
+	for _, x := range config.StakeCredentialsOfInterest {
+		stakeCredsOfInterest[strings.ToLower(strings.TrimPrefix(x, "0x"))] = true
+	}
+
+	poolsOfInterest := make(map[string]bool, len(config.PoolsOfInterest))
+	for _, x := range config.PoolsOfInterest {
+		poolsOfInterest[x] = true
+	}
+
+	labelsOfInterest := make(map[uint64]bool, len(config.MetadataLabelsOfInterest))
+	for _, x := range config.MetadataLabelsOfInterest {
+		labelsOfInterest[x] = true
+	}
+
+	drepsOfInterest := make(map[string]bool, len(config.DRepsOfInterest))
+	for _, x := range config.DRepsOfInterest {
+		drepsOfInterest[strings.ToLower(strings.TrimPrefix(x, "0x"))] = true
+	}
+
+	policiesOfInterest := make(map[string]bool, len(config.PoliciesOfInterest))
+	for _, x := range config.PoliciesOfInterest {
+		policiesOfInterest[strings.ToLower(strings.TrimPrefix(x, "0x"))] = true
+	}
+
+	var slotTimeConverter *SlotTimeConverter
+
+	if config.SlotTime != nil {
+		converter, err := NewSlotTimeConverter(config.SlotTime)
+		if err != nil {
+			panic(fmt.Sprintf("invalid slot time config: %v", err)) //nolint:gocritic
+		}
+
+		slotTimeConverter = converter
+	}
+
+	var retrier *handlerRetrier
+
+	if config.HandlerRetry != nil {
+		retrier = newHandlerRetrier(*config.HandlerRetry, confirmedBlockHandler, db, logger)
+		retrier.Start()
+	}
+
 	return &BlockIndexer{
 		config:                config,
 		latestBlockPoint:      nil,
@@ -68,6 +161,15 @@ func NewBlockIndexer(
 		unconfirmedBlocks:     infraCommon.NewCircularQueue[ledger.BlockHeader](int(config.ConfirmationBlockCount)), //nolint
 		db:                    db,
 		addressesOfInterest:   addressesOfInterest,
+		stakeCredsOfInterest:  stakeCredsOfInterest,
+		poolsOfInterest:       poolsOfInterest,
+		policiesOfInterest:    policiesOfInterest,
+		labelsOfInterest:      labelsOfInterest,
+		drepsOfInterest:       drepsOfInterest,
+		slotTimeConverter:     slotTimeConverter,
+		tentativeBlocks:       map[string]*tentativeBlock{},
+		prefetchedTxs:         map[string][]ledger.Transaction{},
+		handlerRetrier:        retrier,
 		logger:                logger,
 	}
 }
@@ -87,12 +189,16 @@ func (bi *BlockIndexer) RollBackwardFunc(point common.Point) error {
 			"hash", pointHash, "slot", point.Slot, "indx", indx)
 
 		bi.unconfirmedBlocks.SetCount(indx + 1)
+		bi.discardTentativeBlocks()
+		bi.discardPrefetchedTxs()
 
 		return nil
 	}
 
 	if bi.latestBlockPoint.BlockSlot == point.Slot && bi.latestBlockPoint.BlockHash.String() == pointHash {
 		bi.unconfirmedBlocks.SetCount(0)
+		bi.discardTentativeBlocks()
+		bi.discardPrefetchedTxs()
 
 		bi.logger.Info("Roll backward to confirmed block", "hash", pointHash, "slot", point.Slot)
 
@@ -109,27 +215,61 @@ func (bi *BlockIndexer) RollBackwardFunc(point common.Point) error {
 }
 
 func (bi *BlockIndexer) RollForwardFunc(blockHeader ledger.BlockHeader, txsRetriever BlockTxsRetriever) error {
+	confirmedBlock, confirmedTxs, err := bi.rollForward(blockHeader, txsRetriever)
+	if err != nil || confirmedBlock == nil {
+		return err
+	}
+
+	// block is already saved, so handler is called without holding the lock
+	return bi.notifyConfirmedBlock(confirmedBlock, confirmedTxs)
+}
+
+// rollForward saves the first unconfirmed block if it is confirmed by the new block and returns it
+func (bi *BlockIndexer) rollForward(
+	blockHeader ledger.BlockHeader, txsRetriever BlockTxsRetriever,
+) (*CardanoBlock, []*Tx, error) {
 	bi.mutex.Lock()
 	defer bi.mutex.Unlock()
 
+	var newTentativeBlock *tentativeBlock
+
+	if bi.config.KeepTentativeTxs {
+		tb, err := bi.newTentativeBlock(blockHeader, txsRetriever)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		newTentativeBlock = tb
+	}
+
 	if !bi.unconfirmedBlocks.IsFull() {
 		// If there are not enough children blocks to promote the first one to the confirmed state,
 		// a new block header is added, and the function returns
 		_ = bi.unconfirmedBlocks.Push(blockHeader)
+		bi.addTentativeBlock(blockHeader, newTentativeBlock)
 
-		return nil
+		return nil, nil, nil
 	}
 
 	firstBlockHeader := bi.unconfirmedBlocks.Peek()
 
-	txs, err := txsRetriever.GetBlockTransactions(firstBlockHeader)
-	if err != nil {
-		return err
+	var txs []ledger.Transaction
+
+	if tentativeTxs, exists := bi.getTentativeBlockTxs(firstBlockHeader); exists {
+		txs = tentativeTxs // already retrieved when block header arrived
+	} else if !IsEpochBoundaryBlock(firstBlockHeader) {
+		// epoch boundary blocks do not have transactions so there is no need to retrieve them
+		blockTxs, err := bi.getConfirmedBlockTxs(firstBlockHeader, txsRetriever)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		txs = blockTxs
 	}
 
 	confirmedBlock, confirmedTxs, latestBlockPoint, err := bi.processConfirmedBlock(firstBlockHeader, txs)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// update latest block point in memory if we have confirmed block
@@ -138,7 +278,31 @@ func (bi *BlockIndexer) RollForwardFunc(blockHeader ledger.BlockHeader, txsRetri
 	bi.unconfirmedBlocks.Pop()
 	_ = bi.unconfirmedBlocks.Push(blockHeader)
 
-	return bi.confirmedBlockHandler(confirmedBlock, confirmedTxs)
+	delete(bi.tentativeBlocks, firstBlockHeader.Hash())
+	bi.addTentativeBlock(blockHeader, newTentativeBlock)
+
+	return confirmedBlock, confirmedTxs, nil
+}
+
+// notifyConfirmedBlock calls handler for already saved confirmed block. If handler retry is configured,
+// block is only queued for the retrier, so the syncer does not wait for the retries
+func (bi *BlockIndexer) notifyConfirmedBlock(confirmedBlock *CardanoBlock, confirmedTxs []*Tx) error {
+	if bi.handlerRetrier == nil {
+		return bi.confirmedBlockHandler(confirmedBlock, confirmedTxs)
+	}
+
+	bi.handlerRetrier.Notify(confirmedBlock, confirmedTxs)
+
+	return nil
+}
+
+// Close handles blocks queued for the handler retrier. Remaining attempts are made without waiting for the backoff
+func (bi *BlockIndexer) Close() error {
+	if bi.handlerRetrier == nil {
+		return nil
+	}
+
+	return bi.handlerRetrier.Close()
 }
 
 func (bi *BlockIndexer) Reset() (BlockPoint, error) {
@@ -163,6 +327,8 @@ func (bi *BlockIndexer) Reset() (BlockPoint, error) {
 
 	bi.latestBlockPoint = latestPoint
 	bi.unconfirmedBlocks.SetCount(0) // clear all unconfirmed from the memory
+	bi.discardTentativeBlocks()
+	bi.discardPrefetchedTxs()
 
 	return *latestPoint, nil
 }
@@ -186,13 +352,18 @@ func (bi *BlockIndexer) processConfirmedBlock(
 	}
 
 	if bi.config.KeepAllTxOutputsInDB {
-		txOutputsToSave = bi.getTxOutputs(confirmedBlockHeader.SlotNumber(), allBlockTransactions, nil)
+		txOutputsToSave, err = bi.getTxOutputs(confirmedBlockHeader.SlotNumber(), allBlockTransactions, nil)
 		txOutputsToRemove = bi.getTxInputs(allBlockTransactions)
 	} else {
-		txOutputsToSave = bi.getTxOutputs(confirmedBlockHeader.SlotNumber(), txsOfInterest, bi.addressesOfInterest)
+		txOutputsToSave, err = bi.getTxOutputs(
+			confirmedBlockHeader.SlotNumber(), txsOfInterest, bi.addressesOfInterest)
 		txOutputsToRemove = bi.getTxInputs(txsOfInterest)
 	}
 
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// add confirmed block to db and create full block only if there are some transactions of interest
 	if len(txsOfInterest) > 0 {
 		confirmedTxs = make([]*Tx, len(txsOfInterest))
@@ -204,6 +375,19 @@ func (bi *BlockIndexer) processConfirmedBlock(
 		}
 
 		dbTx.AddConfirmedTxs(confirmedTxs) // add confirmed txs in db
+
+		if mintEvents := NewMintEvents(confirmedTxs); len(mintEvents) > 0 {
+			dbTx.AddMintEvents(mintEvents) // index mints and burns by policy id
+		}
+
+		proposals, votes := NewGovEvents(confirmedTxs)
+		if len(proposals) > 0 {
+			dbTx.AddGovProposals(proposals) // index proposals by governance action id
+		}
+
+		if len(votes) > 0 {
+			dbTx.AddGovVotes(votes) // index votes by voter
+		}
 	}
 
 	if bi.config.KeepAllTxsHashesInBlock {
@@ -213,13 +397,17 @@ func (bi *BlockIndexer) processConfirmedBlock(
 	}
 
 	confirmedBlock := NewCardanoBlock(confirmedBlockHeader, txsHashes)
+	confirmedBlock.Timestamp = bi.getSlotTimestamp(confirmedBlockHeader.SlotNumber())
 	latestBlockPoint := &BlockPoint{
 		BlockSlot:   confirmedBlockHeader.SlotNumber(),
 		BlockHash:   NewHashFromHexString(confirmedBlockHeader.Hash()),
-		BlockNumber: confirmedBlockHeader.BlockNumber(),
+		BlockNumber: confirmedBlock.Number,
+	}
+	// save confirmed block (without tx details) in db. EBB is not saved because it has the same slot (key)
+	// as the first block of the epoch
+	if !confirmedBlock.EpochBoundary {
+		dbTx.AddConfirmedBlock(confirmedBlock)
 	}
-	// save confirmed block (without tx details) in db
-	dbTx.AddConfirmedBlock(confirmedBlock)
 	// update latest block point in db tx
 	dbTx.SetLatestBlockPoint(latestBlockPoint)
 	// add all needed outputs, remove used ones in db tx
@@ -234,14 +422,19 @@ func (bi *BlockIndexer) processConfirmedBlock(
 }
 
 func (bi *BlockIndexer) filterTxsOfInterest(txs []ledger.Transaction) (result []ledger.Transaction, err error) {
-	if len(bi.addressesOfInterest) == 0 {
+	if len(bi.addressesOfInterest) == 0 && len(bi.stakeCredsOfInterest) == 0 &&
+		len(bi.poolsOfInterest) == 0 && len(bi.policiesOfInterest) == 0 && len(bi.labelsOfInterest) == 0 &&
+		len(bi.drepsOfInterest) == 0 && !bi.config.KeepAllGovernanceTxs {
 		return txs, nil
 	}
 
 	for _, tx := range txs {
 		if bi.config.AddressCheck&AddressCheckOutputs != 0 && bi.isTxOutputOfInterest(tx) {
 			result = append(result, tx)
-		} else if bi.config.AddressCheck&AddressCheckInputs != 0 {
+		} else if bi.isTxStakingOfInterest(tx) || bi.isTxMintOfInterest(tx) ||
+			bi.isTxMetadataOfInterest(tx) || bi.isTxGovernanceOfInterest(tx) {
+			result = append(result, tx)
+		} else if len(bi.addressesOfInterest) > 0 {
 			txIsGood, err := bi.isTxInputOfInterest(tx)
 			if err != nil {
 				return nil, err
@@ -255,7 +448,9 @@ func (bi *BlockIndexer) filterTxsOfInterest(txs []ledger.Transaction) (result []
 }
 
 func (bi *BlockIndexer) isTxOutputOfInterest(tx ledger.Transaction) bool {
-	for _, out := range tx.Outputs() {
+	outputs, _ := getProducedOutputs(tx)
+
+	for _, out := range outputs {
 		addr := LedgerAddressToString(out.Address())
 		if bi.addressesOfInterest[addr] {
 			return true
@@ -265,9 +460,110 @@ func (bi *BlockIndexer) isTxOutputOfInterest(tx ledger.Transaction) bool {
 	return false
 }
 
+func (bi *BlockIndexer) isTxStakingOfInterest(tx ledger.Transaction) bool {
+	if len(bi.stakeCredsOfInterest) == 0 && len(bi.poolsOfInterest) == 0 {
+		return false
+	}
+
+	for _, cert := range NewCertificates(tx.Certificates()) {
+		if cert.StakeCredential != nil && bi.stakeCredsOfInterest[cert.StakeCredential.Hash] {
+			return true
+		}
+
+		if cert.PoolID != "" && bi.poolsOfInterest[cert.PoolID] {
+			return true
+		}
+	}
+
+	for _, withdrawal := range NewWithdrawals(tx.Withdrawals()) {
+		if bi.stakeCredsOfInterest[withdrawal.StakeCredential.Hash] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (bi *BlockIndexer) isTxGovernanceOfInterest(tx ledger.Transaction) bool {
+	if bi.config.KeepAllGovernanceTxs && (len(tx.VotingProcedures()) > 0 || len(tx.ProposalProcedures()) > 0) {
+		return true
+	}
+
+	if len(bi.drepsOfInterest) == 0 {
+		return false
+	}
+
+	for _, vote := range NewVotes(tx.VotingProcedures()) {
+		if (vote.Voter.Type == VoterTypeDRepKeyHash || vote.Voter.Type == VoterTypeDRepScriptHash) &&
+			bi.drepsOfInterest[vote.Voter.Hash] {
+			return true
+		}
+	}
+
+	for _, cert := range NewCertificates(tx.Certificates()) {
+		if cert.DRep != nil && bi.drepsOfInterest[cert.DRep.Hash] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (bi *BlockIndexer) isTxMintOfInterest(tx ledger.Transaction) bool {
+	if len(bi.policiesOfInterest) == 0 {
+		return false
+	}
+
+	if mint := tx.AssetMint(); mint != nil {
+		for _, policyID := range mint.Policies() {
+			if bi.policiesOfInterest[policyID.String()] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (bi *BlockIndexer) isTxMetadataOfInterest(tx ledger.Transaction) bool {
+	if len(bi.labelsOfInterest) == 0 {
+		return false
+	}
+
+	metadata := getTxMetadata(tx)
+	if metadata == nil {
+		return false
+	}
+
+	labels, err := GetMetadataLabels(metadata.Cbor())
+	if err != nil {
+		bi.logger.Warn("Could not decode metadata", "hash", tx.Hash(), "err", err)
+
+		return false
+	}
+
+	for _, label := range labels {
+		if bi.labelsOfInterest[label] {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (bi *BlockIndexer) isTxInputOfInterest(tx ledger.Transaction) (bool, error) {
-	for _, inp := range tx.Inputs() {
-		txOutput, err := bi.db.GetTxOutput(TxInput{
+	var inputs []ledger.TransactionInput
+
+	if bi.config.AddressCheck&AddressCheckInputs != 0 {
+		inputs = getConsumedInputs(tx)
+	}
+
+	if bi.config.AddressCheck&AddressCheckReferenceInputs != 0 {
+		inputs = append(inputs, tx.ReferenceInputs()...)
+	}
+
+	for _, inp := range inputs {
+		txOutput, err := bi.getTxOutput(TxInput{
 			Hash:  Hash(inp.Id()),
 			Index: inp.Index(),
 		})
@@ -283,30 +579,50 @@ func (bi *BlockIndexer) isTxInputOfInterest(tx ledger.Transaction) (bool, error)
 
 func (bi *BlockIndexer) getTxOutputs(
 	slot uint64, txs []ledger.Transaction, addressesOfInterest map[string]bool,
-) (res []*TxInputOutput) {
+) (res []*TxInputOutput, err error) {
 	for _, tx := range txs {
-		for ind, txOut := range tx.Outputs() {
+		var witnessSet *TxWitnessSet
+
+		outputs, firstIndex := getProducedOutputs(tx)
+
+		for ind, txOut := range outputs {
 			addr := LedgerAddressToString(txOut.Address())
 			if len(addressesOfInterest) > 0 && !bi.addressesOfInterest[addr] {
 				continue
 			}
 
+			output := createTxOutput(slot, addr, txOut)
+
+			// stored output keeps resolved datum, so it is available when the output is spent
+			if bi.config.ResolveDatumHashes && output.hasUnresolvedDatum() {
+				if witnessSet == nil {
+					if witnessSet, err = getTxWitnessSet(tx); err != nil {
+						// output is stored with unresolved datum rather than stopping the syncer
+						bi.logger.Warn("Could not decode witness set", "hash", tx.Hash(), "err", err)
+
+						witnessSet = &TxWitnessSet{}
+					}
+				}
+
+				resolveTxOutputDatum(&output, witnessSet)
+			}
+
 			res = append(res, &TxInputOutput{
 				Input: TxInput{
 					Hash:  NewHashFromHexString(tx.Hash()),
-					Index: uint32(ind),
+					Index: firstIndex + uint32(ind), //nolint:gosec
 				},
-				Output: createTxOutput(slot, addr, txOut),
+				Output: output,
 			})
 		}
 	}
 
-	return res
+	return res, nil
 }
 
 func (bi *BlockIndexer) getTxInputs(txs []ledger.Transaction) (res []*TxInput) {
 	for _, tx := range txs {
-		for _, inp := range tx.Inputs() {
+		for _, inp := range getConsumedInputs(tx) {
 			res = append(res, &TxInput{
 				Hash:  Hash(inp.Id()),
 				Index: inp.Index(),
@@ -327,33 +643,44 @@ func (bi *BlockIndexer) createTx(
 		BlockSlot: ledgerBlockHeader.SlotNumber(),
 		BlockHash: NewHashFromHexString(ledgerBlockHeader.Hash()),
 		Valid:     ledgerTx.IsValid(),
+		Timestamp: bi.getSlotTimestamp(ledgerBlockHeader.SlotNumber()),
 	}
 
-	if inputs := ledgerTx.Inputs(); len(inputs) > 0 {
-		tx.Inputs = make([]*TxInputOutput, len(inputs))
+	inputs, err := bi.resolveTxInputs(ledgerTx.Inputs())
+	if err != nil {
+		return nil, err
+	}
 
-		for j, inp := range inputs {
-			txInput := TxInput{
-				Hash:  Hash(inp.Id()),
-				Index: inp.Index(),
-			}
+	collateralInputs, err := bi.resolveTxInputs(ledgerTx.Collateral())
+	if err != nil {
+		return nil, err
+	}
 
-			output, err := bi.db.GetTxOutput(txInput)
-			if err != nil {
-				return nil, err
-			}
+	referenceInputs, err := bi.resolveTxInputs(ledgerTx.ReferenceInputs())
+	if err != nil {
+		return nil, err
+	}
 
-			tx.Inputs[j] = &TxInputOutput{
-				Input:  txInput,
-				Output: output,
-			}
-		}
+	tx.Inputs = inputs
+	tx.CollateralInputs = collateralInputs
+	tx.ReferenceInputs = referenceInputs
+	tx.TotalCollateral = ledgerTx.TotalCollateral()
+
+	if out := ledgerTx.CollateralReturn(); out != nil {
+		txOutput := createTxOutput(ledgerBlockHeader.SlotNumber(), LedgerAddressToString(out.Address()), out)
+		tx.CollateralReturn = &txOutput
 	}
 
-	if metadata := ledgerTx.Metadata(); metadata != nil {
+	if metadata := getTxMetadata(ledgerTx); metadata != nil {
 		tx.Metadata = metadata.Cbor()
 	}
 
+	tx.Certificates = NewCertificates(ledgerTx.Certificates())
+	tx.Withdrawals = NewWithdrawals(ledgerTx.Withdrawals())
+	tx.Mint = NewMintAmounts(ledgerTx.AssetMint())
+	tx.Votes = NewVotes(ledgerTx.VotingProcedures())
+	tx.Proposals = NewProposals(tx.Hash, ledgerTx.ProposalProcedures())
+
 	if outputs := ledgerTx.Outputs(); len(outputs) > 0 {
 		tx.Outputs = make([]*TxOutput, len(outputs))
 		for j, out := range outputs {
@@ -363,9 +690,124 @@ func (bi *BlockIndexer) createTx(
 		}
 	}
 
+	if bi.config.KeepTxWitnesses || bi.config.ResolveDatumHashes {
+		if witnessSetCbor := getTxWitnessSetCbor(ledgerTx); len(witnessSetCbor) > 0 {
+			witnessSet, err := NewTxWitnessSet(witnessSetCbor)
+			if err != nil {
+				// tx is indexed without witnesses and resolved datums rather than stopping the syncer
+				bi.logger.Warn("Could not decode witness set", "hash", tx.Hash, "err", err)
+
+				return tx, nil
+			}
+
+			if bi.config.ResolveDatumHashes {
+				resolveTxDatums(tx, witnessSet)
+			}
+
+			if bi.config.KeepTxWitnesses {
+				tx.WitnessSet = witnessSet
+			}
+		}
+	}
+
 	return tx, nil
 }
 
+// getSlotTimestamp returns unix time of the slot or 0 if slot time is not configured
+func (bi *BlockIndexer) getSlotTimestamp(slot uint64) int64 {
+	if bi.slotTimeConverter == nil {
+		return 0
+	}
+
+	return bi.slotTimeConverter.SlotToTime(slot).Unix()
+}
+
+// resolveTxDatums sets datum of every input and output which has only datum hash
+// if the datum is provided in the witness set of the tx
+func resolveTxDatums(tx *Tx, witnessSet *TxWitnessSet) {
+	if len(witnessSet.Datums) == 0 {
+		return
+	}
+
+	for _, inp := range tx.Inputs {
+		resolveTxOutputDatum(&inp.Output, witnessSet)
+	}
+
+	for _, out := range tx.Outputs {
+		resolveTxOutputDatum(out, witnessSet)
+	}
+}
+
+// resolveTxOutputDatum sets datum of the output which has only datum hash if the datum is in the witness set
+func resolveTxOutputDatum(out *TxOutput, witnessSet *TxWitnessSet) {
+	if !out.hasUnresolvedDatum() {
+		return
+	}
+
+	if datum := witnessSet.GetDatum(out.DatumHash); datum != nil {
+		out.Datum = datum.Data
+	}
+}
+
+func (bi *BlockIndexer) resolveTxInputs(inputs []ledger.TransactionInput) ([]*TxInputOutput, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	result := make([]*TxInputOutput, len(inputs))
+
+	for j, inp := range inputs {
+		txInput := TxInput{
+			Hash:  Hash(inp.Id()),
+			Index: inp.Index(),
+		}
+
+		output, err := bi.getTxOutput(txInput)
+		if err != nil {
+			return nil, err
+		}
+
+		result[j] = &TxInputOutput{
+			Input:  txInput,
+			Output: output,
+		}
+	}
+
+	return result, nil
+}
+
+// getTxOutput returns output spent by the input. Outputs of unconfirmed blocks are only in the tentative blocks
+func (bi *BlockIndexer) getTxOutput(txInput TxInput) (TxOutput, error) {
+	if output, exists := bi.getTentativeTxOutput(txInput); exists {
+		return output, nil
+	}
+
+	return bi.db.GetTxOutput(txInput)
+}
+
+// getConsumedInputs returns inputs spent by the tx. Phase-2 invalid tx spends only its collateral inputs
+func getConsumedInputs(tx ledger.Transaction) []ledger.TransactionInput {
+	if !tx.IsValid() {
+		return tx.Collateral()
+	}
+
+	return tx.Inputs()
+}
+
+// getProducedOutputs returns outputs created by the tx and the index of the first one.
+// Phase-2 invalid tx creates only the collateral return output which index is equal to the number of regular outputs
+func getProducedOutputs(tx ledger.Transaction) ([]ledger.TransactionOutput, uint32) {
+	if !tx.IsValid() {
+		if out := tx.CollateralReturn(); out != nil {
+			return []ledger.TransactionOutput{out}, uint32(len(tx.Outputs())) //nolint:gosec
+		}
+
+		return nil, 0
+	}
+
+	return tx.Outputs(), 0
+}
+
 func getTxHashes(txs []ledger.Transaction) []Hash {
 	if len(txs) == 0 {
 		return nil
@@ -414,6 +856,9 @@ func createTxOutput(
 		datumHash = Hash(tmp.Bytes())
 	}
 
+	// script ref of confirmed output is already validated by the ledger so decoding could not fail
+	scriptRef, _ := getTxOutputScriptRef(txOut)
+
 	return TxOutput{
 		Slot:      slot,
 		Address:   addr,
@@ -421,5 +866,6 @@ func createTxOutput(
 		Tokens:    tokens,
 		Datum:     datum,
 		DatumHash: datumHash,
+		ScriptRef: scriptRef,
 	}
 }
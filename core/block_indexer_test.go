@@ -1,9 +1,15 @@
 package core
 
 import (
+	"encoding/hex"
+	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/blinklabs-io/gouroboros/cbor"
 	"github.com/blinklabs-io/gouroboros/ledger"
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
 	"github.com/blinklabs-io/gouroboros/protocol/common"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
@@ -824,3 +830,422 @@ func TestBlockIndexer_RollForwardFunc(t *testing.T) {
 		dbMock.AssertExpectations(t)
 	}
 }
+
+func TestBlockIndexer_filterTxsOfInterestStaking(t *testing.T) {
+	t.Parallel()
+
+	credentials := [][]byte{
+		{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28},
+		{28, 27, 26, 25, 24, 23, 22, 21, 20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+	}
+	poolKeyHash := ledgerCommon.PoolKeyHash(ledgerCommon.NewBlake2b224(credentials[1]))
+
+	rewardAddr, err := ledgerCommon.NewAddressFromParts(
+		ledgerCommon.AddressTypeNoneKey, ledgerCommon.AddressNetworkMainnet, credentials[0], nil)
+	require.NoError(t, err)
+
+	allTransactions := []ledger.Transaction{
+		&LedgerTransactionMock{ // delegation of other credential to pool of interest
+			CertificatesVal: []ledgerCommon.Certificate{
+				&ledgerCommon.StakeDelegationCertificate{
+					StakeCredential: &ledgerCommon.StakeCredential{Credential: credentials[1]},
+					PoolKeyHash:     poolKeyHash,
+				},
+			},
+		},
+		&LedgerTransactionMock{ // not of interest
+			OutputsVal: []ledger.TransactionOutput{
+				NewLedgerTransactionOutputMock(t, addresses[0], uint64(100)),
+			},
+		},
+		&LedgerTransactionMock{ // withdrawal of credential of interest
+			WithdrawalsVal: map[*ledgerCommon.Address]uint64{
+				&rewardAddr: 100,
+			},
+		},
+		&LedgerTransactionMock{ // registration of credential of interest
+			CertificatesVal: []ledgerCommon.Certificate{
+				&ledgerCommon.StakeRegistrationCertificate{
+					StakeRegistration: ledgerCommon.StakeCredential{Credential: credentials[0]},
+				},
+			},
+		},
+	}
+
+	t.Run("stake credential", func(t *testing.T) {
+		blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+			AddressCheck:               AddressCheckAll,
+			StakeCredentialsOfInterest: []string{"0x" + hex.EncodeToString(credentials[0])},
+		}, nil, &DatabaseMock{}, hclog.NewNullLogger())
+
+		txs, err := blockIndexer.filterTxsOfInterest(allTransactions)
+
+		require.NoError(t, err)
+		require.Equal(t, []ledger.Transaction{allTransactions[2], allTransactions[3]}, txs)
+	})
+
+	t.Run("pool", func(t *testing.T) {
+		blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+			AddressCheck:    AddressCheckOutputs,
+			PoolsOfInterest: []string{ledgerCommon.PoolId(poolKeyHash).String()},
+		}, nil, &DatabaseMock{}, hclog.NewNullLogger())
+
+		txs, err := blockIndexer.filterTxsOfInterest(allTransactions)
+
+		require.NoError(t, err)
+		require.Equal(t, []ledger.Transaction{allTransactions[0]}, txs)
+	})
+}
+
+func TestBlockIndexer_filterTxsOfInterestMint(t *testing.T) {
+	t.Parallel()
+
+	policyID := ledgerCommon.Blake2b224{1, 2, 3}
+	mint := ledgerCommon.NewMultiAsset[ledgerCommon.MultiAssetTypeMint](
+		map[ledgerCommon.Blake2b224]map[cbor.ByteString]int64{
+			policyID: {cbor.NewByteString([]byte("a")): -1},
+		})
+	allTransactions := []ledger.Transaction{
+		&LedgerTransactionMock{},
+		&LedgerTransactionMock{AssetMintVal: &mint},
+	}
+
+	blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+		AddressCheck:       AddressCheckAll,
+		PoliciesOfInterest: []string{"0x" + policyID.String()},
+	}, nil, &DatabaseMock{}, hclog.NewNullLogger())
+
+	txs, err := blockIndexer.filterTxsOfInterest(allTransactions)
+
+	require.NoError(t, err)
+	require.Equal(t, []ledger.Transaction{allTransactions[1]}, txs)
+}
+
+func TestBlockIndexer_filterTxsOfInterestMetadata(t *testing.T) {
+	t.Parallel()
+
+	newMetadata := func(label uint64) *cbor.LazyValue {
+		bytes, err := cbor.Encode(map[uint64]interface{}{label: "msg"})
+		require.NoError(t, err)
+
+		value := &cbor.LazyValue{}
+		require.NoError(t, value.UnmarshalCBOR(bytes))
+
+		return value
+	}
+
+	allTransactions := []ledger.Transaction{
+		&LedgerTransactionMock{},
+		&LedgerTransactionMock{MetadataVal: newMetadata(1)},
+		&LedgerTransactionMock{MetadataVal: newMetadata(MetadataLabelCIP20)},
+	}
+
+	blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+		AddressCheck:             AddressCheckAll,
+		MetadataLabelsOfInterest: []uint64{MetadataLabelCIP20},
+	}, nil, &DatabaseMock{}, hclog.NewNullLogger())
+
+	txs, err := blockIndexer.filterTxsOfInterest(allTransactions)
+
+	require.NoError(t, err)
+	require.Equal(t, []ledger.Transaction{allTransactions[2]}, txs)
+}
+
+func TestBlockIndexer_filterTxsOfInterestGovernance(t *testing.T) {
+	t.Parallel()
+
+	drepHash := [28]byte{1, 2, 3}
+	actionID := &ledgerCommon.GovActionId{TransactionId: [32]byte{1}}
+	newVotingProcedures := func(voterType uint8) ledgerCommon.VotingProcedures {
+		return ledgerCommon.VotingProcedures{
+			{Type: voterType, Hash: drepHash}: {actionID: {Vote: ledgerCommon.GovVoteYes}},
+		}
+	}
+
+	allTransactions := []ledger.Transaction{
+		&LedgerTransactionMock{},
+		&LedgerTransactionMock{VotingProceduresVal: newVotingProcedures(ledgerCommon.VoterTypeDRepKeyHash)},
+		&LedgerTransactionMock{VotingProceduresVal: newVotingProcedures(ledgerCommon.VoterTypeStakingPoolKeyHash)},
+		&LedgerTransactionMock{CertificatesVal: []ledgerCommon.Certificate{
+			&ledgerCommon.UpdateDrepCertificate{
+				DrepCredential: ledgerCommon.StakeCredential{Credential: drepHash[:]},
+			},
+		}},
+		&LedgerTransactionMock{ProposalProceduresVal: []ledgerCommon.ProposalProcedure{{}}},
+	}
+
+	blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+		AddressCheck:    AddressCheckAll,
+		DRepsOfInterest: []string{"0x" + hex.EncodeToString(drepHash[:])},
+	}, nil, &DatabaseMock{}, hclog.NewNullLogger())
+
+	txs, err := blockIndexer.filterTxsOfInterest(allTransactions)
+
+	require.NoError(t, err)
+	require.Equal(t, []ledger.Transaction{allTransactions[1], allTransactions[3]}, txs)
+
+	blockIndexer = NewBlockIndexer(&BlockIndexerConfig{
+		AddressCheck:         AddressCheckAll,
+		KeepAllGovernanceTxs: true,
+	}, nil, &DatabaseMock{}, hclog.NewNullLogger())
+
+	txs, err = blockIndexer.filterTxsOfInterest(allTransactions)
+
+	require.NoError(t, err)
+	require.Equal(t, []ledger.Transaction{allTransactions[1], allTransactions[2], allTransactions[4]}, txs)
+}
+
+func TestBlockIndexer_processConfirmedBlockInvalidTx(t *testing.T) {
+	t.Parallel()
+
+	const (
+		blockNumber = uint64(50)
+		blockSlot   = uint64(100)
+	)
+
+	hashTx := Hash{1, 2, 3, 4, 5, 6, 7, 17}
+	blockHash := Hash{100, 200, 100}
+	txInputs := [2]ledger.TransactionInput{
+		NewLedgerTransactionInputMock(t, []byte{1}, uint32(0)),
+		NewLedgerTransactionInputMock(t, []byte{1, 2}, uint32(1)),
+	}
+	collateralReturn := NewLedgerTransactionOutputMock(t, addresses[1], uint64(80))
+	config := &BlockIndexerConfig{
+		AddressCheck:        AddressCheckAll,
+		AddressesOfInterest: []string{addresses[1]},
+	}
+	dbMock := &DatabaseMock{
+		Writter: &DBTransactionWriterMock{},
+	}
+
+	allTransactions := []ledger.Transaction{
+		&LedgerTransactionMock{
+			HashVal:   bytes2HashString(hashTx[:]),
+			IsInvalid: true,
+			InputsVal: []ledger.TransactionInput{txInputs[0]},
+			OutputsVal: []ledger.TransactionOutput{
+				NewLedgerTransactionOutputMock(t, addresses[1], uint64(100)),
+				NewLedgerTransactionOutputMock(t, addresses[0], uint64(100)),
+			},
+			CollateralVal:       []ledger.TransactionInput{txInputs[1]},
+			CollateralReturnVal: collateralReturn,
+			TotalCollateralVal:  20,
+		},
+	}
+
+	dbMock.On("OpenTx").Once()
+	dbMock.On("GetTxOutput", mock.Anything).Return(TxOutput{Address: addresses[1], Amount: 100}, error(nil))
+
+	dbMock.Writter.On("AddConfirmedBlock", mock.Anything).Once()
+	dbMock.Writter.On("Execute").Return(error(nil)).Once()
+	dbMock.Writter.On("SetLatestBlockPoint", mock.Anything).Once()
+	dbMock.Writter.On("AddConfirmedTxs", mock.Anything).Once()
+	// only collateral input is spent
+	dbMock.Writter.On("RemoveTxOutputs", []*TxInput{
+		{
+			Hash:  Hash(txInputs[1].Id()),
+			Index: txInputs[1].Index(),
+		},
+	}, false).Once()
+	// only collateral return is created, its index follows regular outputs
+	dbMock.Writter.On("AddTxOutputs", []*TxInputOutput{
+		{
+			Input: TxInput{
+				Hash:  hashTx,
+				Index: 2,
+			},
+			Output: TxOutput{
+				Address: addresses[1],
+				Amount:  80,
+				Slot:    blockSlot,
+			},
+		},
+	}).Once()
+
+	blockIndexer := NewBlockIndexer(config, nil, dbMock, hclog.NewNullLogger())
+
+	_, txs, _, err := blockIndexer.processConfirmedBlock(&LedgerBlockHeaderMock{
+		SlotNumberVal:  blockSlot,
+		HashVal:        bytes2HashString(blockHash[:]),
+		BlockNumberVal: blockNumber,
+	}, allTransactions)
+
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	require.False(t, txs[0].Valid)
+	require.Len(t, txs[0].Inputs, 1)
+	require.Len(t, txs[0].Outputs, 2)
+	require.Equal(t, []*TxInputOutput{
+		{
+			Input: TxInput{
+				Hash:  Hash(txInputs[1].Id()),
+				Index: txInputs[1].Index(),
+			},
+			Output: TxOutput{Address: addresses[1], Amount: 100},
+		},
+	}, txs[0].CollateralInputs)
+	require.Equal(t, &TxOutput{Address: addresses[1], Amount: 80, Slot: blockSlot}, txs[0].CollateralReturn)
+	require.Equal(t, uint64(20), txs[0].TotalCollateral)
+	dbMock.AssertExpectations(t)
+	dbMock.Writter.AssertExpectations(t)
+}
+
+func TestBlockIndexer_filterTxsOfInterestReferenceInputs(t *testing.T) {
+	t.Parallel()
+
+	refInput := NewLedgerTransactionInputMock(t, []byte{1, 2}, uint32(1))
+	allTransactions := []ledger.Transaction{
+		&LedgerTransactionMock{},
+		&LedgerTransactionMock{
+			ReferenceInputsVal: []ledger.TransactionInput{refInput},
+		},
+	}
+	dbMock := &DatabaseMock{}
+
+	dbMock.On("GetTxOutput", TxInput{
+		Hash:  Hash(refInput.Id()),
+		Index: refInput.Index(),
+	}).Return(TxOutput{Address: addresses[1]}, error(nil)).Once()
+
+	blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+		AddressCheck:        AddressCheckReferenceInputs,
+		AddressesOfInterest: []string{addresses[1]},
+	}, nil, dbMock, hclog.NewNullLogger())
+
+	txs, err := blockIndexer.filterTxsOfInterest(allTransactions)
+
+	require.NoError(t, err)
+	require.Equal(t, []ledger.Transaction{allTransactions[1]}, txs)
+	dbMock.AssertExpectations(t)
+}
+
+func TestBlockIndexer_notifyConfirmedBlock(t *testing.T) {
+	t.Parallel()
+
+	handlerErr := errors.New("handler failed")
+	confirmedBlock := &CardanoBlock{Slot: 10, Hash: Hash{1}}
+	confirmedTxs := []*Tx{{BlockSlot: 10, Hash: Hash{2}}}
+	retryConfig := &RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	t.Run("no retry config", func(t *testing.T) {
+		blockIndexer := NewBlockIndexer(&BlockIndexerConfig{AddressCheck: AddressCheckAll},
+			func(cb *CardanoBlock, txs []*Tx) error {
+				return handlerErr
+			}, &DatabaseMock{}, hclog.NewNullLogger())
+
+		require.ErrorIs(t, blockIndexer.notifyConfirmedBlock(confirmedBlock, confirmedTxs), handlerErr)
+	})
+
+	t.Run("handler succeeds after retry", func(t *testing.T) {
+		handledCh := make(chan int, 2)
+		dbMock := &DatabaseMock{}
+		blockIndexer := NewBlockIndexer(&BlockIndexerConfig{AddressCheck: AddressCheckAll, HandlerRetry: retryConfig},
+			func() NewConfirmedBlockHandler {
+				calls := 0
+
+				return func(cb *CardanoBlock, txs []*Tx) error {
+					if calls++; calls < 2 {
+						return handlerErr
+					}
+
+					handledCh <- calls
+
+					return nil
+				}
+			}(), dbMock, hclog.NewNullLogger())
+
+		require.NoError(t, blockIndexer.notifyConfirmedBlock(confirmedBlock, confirmedTxs))
+		require.Equal(t, 2, <-handledCh)
+		require.NoError(t, blockIndexer.Close())
+		dbMock.AssertExpectations(t)
+	})
+
+	t.Run("failed blocks are moved to dead-letter store", func(t *testing.T) {
+		var calls atomic.Int32
+
+		savedCh := make(chan []*DeadLetterTx, 2)
+		dbMock := &DatabaseMock{
+			Writter: &DBTransactionWriterMock{},
+		}
+		blockIndexer := NewBlockIndexer(&BlockIndexerConfig{AddressCheck: AddressCheckAll, HandlerRetry: retryConfig},
+			func(cb *CardanoBlock, txs []*Tx) error {
+				calls.Add(1)
+
+				return handlerErr
+			}, dbMock, hclog.NewNullLogger())
+
+		dbMock.On("OpenTx").Twice()
+		dbMock.Writter.On("AddDeadLetterTxs", mock.Anything).Run(func(args mock.Arguments) {
+			savedCh <- args.Get(0).([]*DeadLetterTx)
+		}).Twice()
+		dbMock.Writter.On("Execute").Return(error(nil)).Twice()
+
+		require.NoError(t, blockIndexer.notifyConfirmedBlock(confirmedBlock, confirmedTxs))
+		// block without txs is recorded as well
+		require.NoError(t, blockIndexer.notifyConfirmedBlock(confirmedBlock, nil))
+
+		deadLetterTxs := <-savedCh
+		require.Len(t, deadLetterTxs, 1)
+		require.Equal(t, confirmedBlock, deadLetterTxs[0].Block)
+		require.Equal(t, confirmedTxs[0], deadLetterTxs[0].Tx)
+		require.Equal(t, 3, deadLetterTxs[0].Attempts)
+		require.Equal(t, handlerErr.Error(), deadLetterTxs[0].Error)
+
+		deadLetterTxs = <-savedCh
+		require.Len(t, deadLetterTxs, 1)
+		require.Equal(t, confirmedBlock, deadLetterTxs[0].Block)
+		require.Nil(t, deadLetterTxs[0].Tx)
+		require.Equal(t, 3, deadLetterTxs[0].Attempts)
+
+		require.NoError(t, blockIndexer.Close())
+		require.Equal(t, int32(6), calls.Load())
+		dbMock.AssertExpectations(t)
+		dbMock.Writter.AssertExpectations(t)
+	})
+
+	t.Run("pending blocks are handled on close", func(t *testing.T) {
+		var handledSlots []uint64
+
+		startedCh := make(chan struct{})
+		dbMock := &DatabaseMock{
+			Writter: &DBTransactionWriterMock{},
+		}
+		blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+			AddressCheck: AddressCheckAll,
+			HandlerRetry: &RetryConfig{MaxAttempts: 3, InitialBackoff: time.Hour},
+		}, func(cb *CardanoBlock, txs []*Tx) error {
+			if cb.Slot == 10 {
+				select {
+				case startedCh <- struct{}{}:
+				default:
+				}
+
+				return handlerErr
+			}
+
+			handledSlots = append(handledSlots, cb.Slot)
+
+			return nil
+		}, dbMock, hclog.NewNullLogger())
+
+		// only the block which fails every attempt is dead-lettered
+		dbMock.On("OpenTx").Once()
+		dbMock.Writter.On("AddDeadLetterTxs", mock.MatchedBy(func(txs []*DeadLetterTx) bool {
+			return len(txs) == 1 && txs[0].Block.Slot == 10 && txs[0].Attempts == 3 &&
+				txs[0].Error == handlerErr.Error()
+		})).Once()
+		dbMock.Writter.On("Execute").Return(error(nil)).Once()
+
+		require.NoError(t, blockIndexer.notifyConfirmedBlock(confirmedBlock, confirmedTxs))
+		<-startedCh // first block waits an hour for the next attempt
+		require.NoError(t, blockIndexer.notifyConfirmedBlock(&CardanoBlock{Slot: 11, Hash: Hash{2}}, nil))
+		require.NoError(t, blockIndexer.Close())
+		require.NoError(t, blockIndexer.Close())
+		// block confirmed after close is handled right away
+		require.NoError(t, blockIndexer.notifyConfirmedBlock(&CardanoBlock{Slot: 12, Hash: Hash{3}}, nil))
+
+		require.Equal(t, []uint64{11, 12}, handledSlots)
+		dbMock.AssertExpectations(t)
+		dbMock.Writter.AssertExpectations(t)
+	})
+}
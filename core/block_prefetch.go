@@ -0,0 +1,62 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+)
+
+// getConfirmedBlockTxs returns txs of the first unconfirmed block which is being confirmed.
+// If retriever supports block ranges and BlockFetchBatchSize is set, txs of the following unconfirmed blocks
+// are retrieved with the same request and kept until these blocks are confirmed or rolled back
+func (bi *BlockIndexer) getConfirmedBlockTxs(
+	header ledger.BlockHeader, txsRetriever BlockTxsRetriever,
+) ([]ledger.Transaction, error) {
+	if txs, exists := bi.prefetchedTxs[header.Hash()]; exists {
+		delete(bi.prefetchedTxs, header.Hash())
+
+		return txs, nil
+	}
+
+	rangeRetriever, ok := txsRetriever.(BlockRangeTxsRetriever)
+	if !ok || bi.config.BlockFetchBatchSize <= 1 {
+		return txsRetriever.GetBlockTransactions(header)
+	}
+
+	headers := bi.unconfirmedBlocks.ToList()
+	headers = headers[:min(len(headers), int(bi.config.BlockFetchBatchSize))] //nolint:gosec
+
+	blocksTxs, err := rangeRetriever.GetBlocksTransactions(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(blocksTxs) != len(headers) {
+		return nil, fmt.Errorf("retrieved txs of %d blocks instead of %d", len(blocksTxs), len(headers))
+	}
+
+	for i, x := range headers[1:] {
+		bi.prefetchedTxs[x.Hash()] = blocksTxs[i+1]
+	}
+
+	return blocksTxs[0], nil
+}
+
+// discardPrefetchedTxs removes prefetched txs of blocks which are no longer in the unconfirmed queue
+func (bi *BlockIndexer) discardPrefetchedTxs() {
+	if len(bi.prefetchedTxs) == 0 {
+		return
+	}
+
+	unconfirmed := map[string]bool{}
+
+	for _, header := range bi.unconfirmedBlocks.ToList() {
+		unconfirmed[header.Hash()] = true
+	}
+
+	for hash := range bi.prefetchedTxs {
+		if !unconfirmed[hash] {
+			delete(bi.prefetchedTxs, hash)
+		}
+	}
+}
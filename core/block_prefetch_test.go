@@ -0,0 +1,116 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type BlockRangeTxsRetrieverMock struct {
+	BlockTxsRetrieverMock
+	RetrieveRangeFn func(blockHeaders []ledger.BlockHeader) ([][]ledger.Transaction, error)
+}
+
+func (bt *BlockRangeTxsRetrieverMock) GetBlocksTransactions(
+	blockHeaders []ledger.BlockHeader,
+) ([][]ledger.Transaction, error) {
+	return bt.RetrieveRangeFn(blockHeaders)
+}
+
+func TestBlockIndexer_PrefetchBlocks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ranges    [][]uint64
+		confirmed []uint64
+	)
+
+	getTxsMock := &BlockRangeTxsRetrieverMock{
+		BlockTxsRetrieverMock: BlockTxsRetrieverMock{
+			RetrieveFn: func(blockHeader ledger.BlockHeader) ([]ledger.Transaction, error) {
+				require.Fail(t, "block should be retrieved within range")
+
+				return nil, nil
+			},
+		},
+		RetrieveRangeFn: func(blockHeaders []ledger.BlockHeader) ([][]ledger.Transaction, error) {
+			slots := make([]uint64, len(blockHeaders))
+			for i, x := range blockHeaders {
+				slots[i] = x.SlotNumber()
+			}
+
+			ranges = append(ranges, slots)
+
+			return make([][]ledger.Transaction, len(blockHeaders)), nil
+		},
+	}
+	blockHeaders := make([]*LedgerBlockHeaderMock, 7)
+	for i := range blockHeaders {
+		blockHeaders[i] = &LedgerBlockHeaderMock{
+			SlotNumberVal: uint64(i + 1), HashVal: bytes2HashString([]byte{byte(i + 1)}),
+		}
+	}
+
+	dbMock := &DatabaseMock{
+		Writter: &DBTransactionWriterMock{},
+	}
+	blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+		AddressCheck:           AddressCheckOutputs,
+		AddressesOfInterest:    []string{addresses[1]},
+		ConfirmationBlockCount: 2,
+		BlockFetchBatchSize:    2,
+	}, func(cb *CardanoBlock, txs []*Tx) error {
+		confirmed = append(confirmed, cb.Slot)
+
+		return nil
+	}, dbMock, hclog.NewNullLogger())
+
+	dbMock.On("GetLatestBlockPoint").Return((*BlockPoint)(nil), error(nil)).Once()
+	dbMock.On("OpenTx").Times(4)
+	dbMock.Writter.On("Execute").Return(error(nil)).Times(4)
+	dbMock.Writter.On("AddTxOutputs", mock.Anything).Times(4)
+	dbMock.Writter.On("RemoveTxOutputs", mock.Anything, false).Times(4)
+	dbMock.Writter.On("AddConfirmedBlock", mock.Anything).Times(4)
+	dbMock.Writter.On("SetLatestBlockPoint", mock.Anything).Times(4)
+
+	_, err := blockIndexer.Reset()
+	require.NoError(t, err)
+
+	rollBackTo := func(header *LedgerBlockHeaderMock) {
+		hash := NewHashFromHexString(header.HashVal)
+
+		require.NoError(t, blockIndexer.RollBackwardFunc(common.Point{Slot: header.SlotNumberVal, Hash: hash[:]}))
+	}
+
+	// the second block is confirmed with txs retrieved together with the first one
+	for _, x := range blockHeaders[:4] {
+		require.NoError(t, blockIndexer.RollForwardFunc(x, getTxsMock))
+	}
+
+	require.Equal(t, [][]uint64{{1, 2}}, ranges)
+
+	// the fourth block is rolled back and the fifth one is fetched together with the third one
+	rollBackTo(blockHeaders[2])
+
+	require.NoError(t, blockIndexer.RollForwardFunc(blockHeaders[4], getTxsMock))
+	require.NoError(t, blockIndexer.RollForwardFunc(blockHeaders[5], getTxsMock))
+
+	// prefetched fifth block is rolled back, so it is fetched again
+	rollBackTo(blockHeaders[2])
+
+	for _, x := range blockHeaders[4:] {
+		require.NoError(t, blockIndexer.RollForwardFunc(x, getTxsMock))
+	}
+
+	require.Equal(t, [][]uint64{{1, 2}, {3, 5}, {5, 6}}, ranges)
+	require.Equal(t, []uint64{1, 2, 3, 5}, confirmed)
+	require.Len(t, blockIndexer.prefetchedTxs, 1)
+	require.Contains(t, blockIndexer.prefetchedTxs, blockHeaders[5].HashVal)
+
+	dbMock.AssertExpectations(t)
+	dbMock.Writter.AssertExpectations(t)
+}
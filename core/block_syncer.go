@@ -3,12 +3,15 @@ package core
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
 
 	ouroboros "github.com/blinklabs-io/gouroboros"
 	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/protocol"
+	"github.com/blinklabs-io/gouroboros/protocol/blockfetch"
 	"github.com/blinklabs-io/gouroboros/protocol/chainsync"
 	"github.com/blinklabs-io/gouroboros/protocol/common"
 	"github.com/hashicorp/go-hclog"
@@ -23,12 +26,19 @@ const (
 	ProtocolUnix = "unix"
 
 	syncStartTriesDefault = 4
+	blockTimeoutDefault   = time.Minute
+	pipelineLimitMax      = 10
 )
 
 type BlockTxsRetriever interface {
 	GetBlockTransactions(blockHeader ledger.BlockHeader) ([]ledger.Transaction, error)
 }
 
+// BlockRangeTxsRetriever retrieves txs of consecutive blocks (ordered by slot) with one request
+type BlockRangeTxsRetriever interface {
+	GetBlocksTransactions(blockHeaders []ledger.BlockHeader) ([][]ledger.Transaction, error)
+}
+
 type BlockSyncer interface {
 	Sync() error
 	Close() error
@@ -48,6 +58,21 @@ type BlockSyncerConfig struct {
 	RestartDelay   time.Duration `json:"restartDelay"`
 	SyncStartTries int           `json:"syncStartTries"`
 	KeepAlive      bool          `json:"keepAlive"`
+	// number of additional chain-sync header requests sent before the previous ones are answered.
+	// Zero means one request at a time. Handler callbacks are still called one by one in chain order.
+	// Must not be higher than 10
+	PipelineLimit int `json:"pipelineLimit"`
+	// maximum time of waiting for a single block from the node (one minute if not set).
+	// Block range request waits this long for every block of the range
+	BlockTimeout time.Duration `json:"blockTimeout"`
+}
+
+func (bsc BlockSyncerConfig) GetBlockTimeout() time.Duration {
+	if bsc.BlockTimeout <= 0 {
+		return blockTimeoutDefault
+	}
+
+	return bsc.BlockTimeout
 }
 
 func (bsc BlockSyncerConfig) Protocol() string {
@@ -63,6 +88,8 @@ type BlockSyncerImpl struct {
 	blockHandler BlockSyncerHandler
 	config       *BlockSyncerConfig
 	logger       hclog.Logger
+	// blocks of the requested ranges are received on this channel
+	rangeBlocksCh chan ledger.Block
 
 	errorCh  chan error
 	closeCh  chan struct{}
@@ -83,6 +110,10 @@ func NewBlockSyncer(config *BlockSyncerConfig, blockHandler BlockSyncerHandler,
 }
 
 func (bs *BlockSyncerImpl) Sync() (err error) {
+	if bs.config.PipelineLimit < 0 || bs.config.PipelineLimit > pipelineLimitMax {
+		return fmt.Errorf("pipeline limit must be between 0 and %d: %d", pipelineLimitMax, bs.config.PipelineLimit)
+	}
+
 	cntTries := bs.config.SyncStartTries
 	if cntTries <= 0 {
 		cntTries = syncStartTriesDefault
@@ -140,6 +171,8 @@ func (bs *BlockSyncerImpl) syncExecute() error {
 
 	bs.logger.Debug("Start syncing requested", "addr", bs.config.NodeAddress, "magic", bs.config.NetworkMagic)
 
+	rangeBlocksCh := make(chan ledger.Block)
+
 	// create connection
 	connection, err := ouroboros.NewConnection(
 		ouroboros.WithNetworkMagic(bs.config.NetworkMagic),
@@ -148,6 +181,18 @@ func (bs *BlockSyncerImpl) syncExecute() error {
 		ouroboros.WithChainSyncConfig(chainsync.NewConfig(
 			chainsync.WithRollBackwardFunc(bs.rollBackwardCallback),
 			chainsync.WithRollForwardFunc(bs.rollForwardCallback),
+			chainsync.WithPipelineLimit(bs.config.PipelineLimit),
+		)),
+		ouroboros.WithBlockFetchConfig(blockfetch.NewConfig(
+			blockfetch.WithBlockTimeout(bs.config.GetBlockTimeout()),
+			blockfetch.WithBlockFunc(func(ctx blockfetch.CallbackContext, _ uint, block ledger.Block) error {
+				select {
+				case rangeBlocksCh <- block:
+				case <-ctx.Client.DoneChan():
+				}
+
+				return nil
+			}),
 		)),
 	)
 	if err != nil {
@@ -160,6 +205,7 @@ func (bs *BlockSyncerImpl) syncExecute() error {
 	}
 
 	bs.connection = connection
+	bs.rangeBlocksCh = rangeBlocksCh
 
 	bs.logger.Debug("Connection established", "addr", bs.config.NodeAddress, "magic", bs.config.NetworkMagic)
 
@@ -208,10 +254,12 @@ func (bs *BlockSyncerImpl) rollForwardCallback(
 	}
 
 	txsRetriever := NewBlockTxsRetriever(bs.connection, bs.logger)
+	txsRetriever.rangeBlocksCh = bs.rangeBlocksCh
+	txsRetriever.blockTimeout = bs.config.GetBlockTimeout()
 	bs.lock.Unlock()
 
 	bs.logger.Debug("Roll forward",
-		"hash", blockHeader.Hash(), "slot", blockHeader.SlotNumber(), "number", blockHeader.BlockNumber(),
+		"hash", blockHeader.Hash(), "slot", blockHeader.SlotNumber(), "number", GetBlockNumber(blockHeader),
 		"tip_slot", tip.Point.Slot, "tip_hash", hex.EncodeToString(tip.Point.Hash))
 
 	return bs.blockHandler.RollForwardFunc(blockHeader, txsRetriever)
@@ -269,12 +317,22 @@ func (bs *BlockSyncerImpl) closeConnectionNoLock() {
 type BlockTxsRetrieverImpl struct {
 	connection *ouroboros.Connection
 	logger     hclog.Logger
+	// blocks of the requested ranges. If nil, blocks are retrieved one by one
+	rangeBlocksCh <-chan ledger.Block
+	// maximum time of waiting for a single block of the range
+	blockTimeout time.Duration
 }
 
+var (
+	_ BlockTxsRetriever      = (*BlockTxsRetrieverImpl)(nil)
+	_ BlockRangeTxsRetriever = (*BlockTxsRetrieverImpl)(nil)
+)
+
 func NewBlockTxsRetriever(conn *ouroboros.Connection, logger hclog.Logger) *BlockTxsRetrieverImpl {
 	return &BlockTxsRetrieverImpl{
-		connection: conn,
-		logger:     logger,
+		connection:   conn,
+		logger:       logger,
+		blockTimeout: blockTimeoutDefault,
 	}
 }
 
@@ -294,3 +352,62 @@ func (br *BlockTxsRetrieverImpl) GetBlockTransactions(
 
 	return block.Transactions(), nil
 }
+
+func (br *BlockTxsRetrieverImpl) GetBlocksTransactions(
+	blockHeaders []ledger.BlockHeader,
+) ([][]ledger.Transaction, error) {
+	if len(blockHeaders) == 0 {
+		return nil, nil
+	}
+
+	result := make([][]ledger.Transaction, len(blockHeaders))
+
+	if br.rangeBlocksCh == nil {
+		for i, header := range blockHeaders {
+			txs, err := br.GetBlockTransactions(header)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = txs
+		}
+
+		return result, nil
+	}
+
+	first, last := blockHeaders[0], blockHeaders[len(blockHeaders)-1]
+
+	br.logger.Debug("Get blocks transactions", "from_slot", first.SlotNumber(), "to_slot", last.SlotNumber(),
+		"cnt", len(blockHeaders))
+
+	firstHash, lastHash := NewHashFromHexString(first.Hash()), NewHashFromHexString(last.Hash())
+	client := br.connection.BlockFetch().Client
+
+	err := client.GetBlockRange(
+		common.NewPoint(first.SlotNumber(), firstHash[:]), common.NewPoint(last.SlotNumber(), lastHash[:]))
+	if err != nil {
+		return nil, err
+	}
+
+	// the whole range is expected in as much time as the same number of single block requests
+	timeoutCh := time.After(br.blockTimeout * time.Duration(len(blockHeaders)))
+
+	// blocks of the range are streamed in order
+	for i, header := range blockHeaders {
+		select {
+		case block := <-br.rangeBlocksCh:
+			if block.Hash() != header.Hash() {
+				return nil, fmt.Errorf("unexpected block in range: expected (%d, %s) but got (%d, %s)",
+					header.SlotNumber(), header.Hash(), block.SlotNumber(), block.Hash())
+			}
+
+			result[i] = block.Transactions()
+		case <-client.DoneChan():
+			return nil, protocol.ProtocolShuttingDownError
+		case <-timeoutCh:
+			return nil, fmt.Errorf("timeout while waiting for block (%d, %s)", header.SlotNumber(), header.Hash())
+		}
+	}
+
+	return result, nil
+}
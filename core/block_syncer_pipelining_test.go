@@ -0,0 +1,140 @@
+package core
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockSyncer_Pipelining(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 50)
+	node := startTestChainNode(t, chain, time.Millisecond)
+
+	for _, pipelineLimit := range []int{0, 10} {
+		t.Run(fmt.Sprintf("pipeline limit %d", pipelineLimit), func(t *testing.T) {
+			var (
+				slots    []uint64
+				inFlight atomic.Int32
+				doneCh   = make(chan struct{})
+			)
+
+			handler := &BlockSyncerHandlerMock{
+				BlockPoint: &BlockPoint{
+					BlockSlot: chain[9].slot,
+					BlockHash: Hash(chain[9].hash),
+				},
+				RollBackwardFuncFn: func(point common.Point) error {
+					require.Equal(t, chain[9].point(), point)
+
+					return nil
+				},
+				RollForwardFn: func(header ledger.BlockHeader, txsRetriever BlockTxsRetriever) error {
+					require.Equal(t, int32(1), inFlight.Add(1), "callbacks must not overlap")
+					defer inFlight.Add(-1)
+
+					slots = append(slots, header.SlotNumber())
+
+					// blocks can be fetched while more headers are requested
+					if len(slots)%10 == 0 {
+						_, err := txsRetriever.GetBlockTransactions(header)
+						require.NoError(t, err)
+					}
+
+					if len(slots) == len(chain)-10 {
+						close(doneCh)
+					}
+
+					return nil
+				},
+			}
+
+			syncer := NewBlockSyncer(&BlockSyncerConfig{
+				NetworkMagic:  testChainNetworkMagic,
+				NodeAddress:   node.Address(),
+				PipelineLimit: pipelineLimit,
+			}, handler, hclog.NewNullLogger())
+
+			require.NoError(t, syncer.Sync())
+
+			select {
+			case <-doneCh:
+			case <-time.After(10 * time.Second):
+				require.Fail(t, "timeout")
+			}
+
+			require.NoError(t, syncer.Close())
+
+			for i, slot := range slots {
+				require.Equal(t, chain[i+10].slot, slot)
+			}
+		})
+	}
+}
+
+// BenchmarkBlockSyncer_CatchUp measures how fast block indexer catches up with the test chain
+// served with simulated network latency
+func BenchmarkBlockSyncer_CatchUp(b *testing.B) {
+	const (
+		chainLength       = 500
+		confirmationCount = 10
+		latency           = 2 * time.Millisecond
+	)
+
+	chain := newTestChain(b, chainLength)
+	node := startTestChainNode(b, chain, latency)
+
+	for _, pipelineLimit := range []int{0, 1, 5, 10} {
+		b.Run(fmt.Sprintf("pipeline limit %d", pipelineLimit), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var confirmedCnt int
+
+				doneCh := make(chan struct{})
+				dbMock := &DatabaseMock{Writter: &DBTransactionWriterMock{}}
+
+				dbMock.On("GetLatestBlockPoint").Return((*BlockPoint)(nil), error(nil))
+				dbMock.On("OpenTx")
+				dbMock.Writter.On("Execute").Return(error(nil))
+				dbMock.Writter.On("AddTxOutputs", mock.Anything)
+				dbMock.Writter.On("RemoveTxOutputs", mock.Anything, false)
+				dbMock.Writter.On("AddConfirmedTxs", mock.Anything)
+				dbMock.Writter.On("AddConfirmedBlock", mock.Anything)
+				dbMock.Writter.On("SetLatestBlockPoint", mock.Anything)
+
+				blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+					AddressCheck:           AddressCheckOutputs,
+					ConfirmationBlockCount: confirmationCount,
+					BlockFetchBatchSize:    confirmationCount,
+				}, func(cb *CardanoBlock, txs []*Tx) error {
+					if confirmedCnt++; confirmedCnt == chainLength-confirmationCount {
+						close(doneCh)
+					}
+
+					return nil
+				}, dbMock, hclog.NewNullLogger())
+
+				syncer := NewBlockSyncer(&BlockSyncerConfig{
+					NetworkMagic:  testChainNetworkMagic,
+					NodeAddress:   node.Address(),
+					PipelineLimit: pipelineLimit,
+				}, blockIndexer, hclog.NewNullLogger())
+
+				require.NoError(b, syncer.Sync())
+
+				<-doneCh
+
+				require.NoError(b, syncer.Close())
+			}
+
+			b.ReportMetric(float64(chainLength*b.N)/b.Elapsed().Seconds(), "blocks/s")
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/hex"
 	"errors"
 	"strings"
 	"sync/atomic"
@@ -317,3 +318,80 @@ func TestSync_ConnectionIsClosed(t *testing.T) {
 	require.NoError(t, syncer.Sync())
 	require.Nil(t, syncer.connection)
 }
+
+func TestBlockTxsRetriever_GetBlocksTransactions(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 20)
+	node := startTestChainNode(t, chain, 0)
+	doneCh := make(chan struct{})
+
+	handler := &BlockSyncerHandlerMock{
+		BlockPoint: &BlockPoint{},
+		RollForwardFn: func(header ledger.BlockHeader, txsRetriever BlockTxsRetriever) error {
+			if header.SlotNumber() != chain[len(chain)-1].slot {
+				return nil
+			}
+
+			defer close(doneCh)
+
+			rangeRetriever, ok := txsRetriever.(BlockRangeTxsRetriever)
+			require.True(t, ok)
+
+			headers := make([]ledger.BlockHeader, len(chain))
+			for i, x := range chain {
+				headers[i] = &LedgerBlockHeaderMock{SlotNumberVal: x.slot, HashVal: hex.EncodeToString(x.hash)}
+			}
+
+			blocksTxs, err := rangeRetriever.GetBlocksTransactions(headers)
+			require.NoError(t, err)
+			require.Len(t, blocksTxs, len(chain))
+
+			// range does not match the node chain
+			headers[1] = headers[0]
+
+			_, err = rangeRetriever.GetBlocksTransactions(headers)
+			require.ErrorContains(t, err, "unexpected block in range")
+
+			return nil
+		},
+	}
+
+	syncer := NewBlockSyncer(&BlockSyncerConfig{
+		NetworkMagic: testChainNetworkMagic,
+		NodeAddress:  node.Address(),
+	}, handler, hclog.NewNullLogger())
+
+	require.NoError(t, syncer.Sync())
+
+	select {
+	case <-doneCh:
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "timeout")
+	}
+
+	require.NoError(t, syncer.Close())
+}
+
+func TestBlockSyncerConfig_GetBlockTimeout(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, blockTimeoutDefault, BlockSyncerConfig{}.GetBlockTimeout())
+	require.Equal(t, time.Second, BlockSyncerConfig{BlockTimeout: time.Second}.GetBlockTimeout())
+	require.Equal(t, blockTimeoutDefault, NewBlockTxsRetriever(nil, hclog.NewNullLogger()).blockTimeout)
+}
+
+func TestSync_InvalidPipelineLimit(t *testing.T) {
+	t.Parallel()
+
+	for _, pipelineLimit := range []int{-1, 11} {
+		syncer := NewBlockSyncer(&BlockSyncerConfig{
+			NetworkMagic:  NetworkMagic,
+			NodeAddress:   NodeAddress,
+			PipelineLimit: pipelineLimit,
+		}, &BlockSyncerHandlerMock{}, hclog.NewNullLogger())
+
+		require.ErrorContains(t, syncer.Sync(), "pipeline limit must be between 0 and 10")
+		require.Nil(t, syncer.connection)
+	}
+}
@@ -0,0 +1,319 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	brokerPollIntervalDefault = 5 * time.Second
+	brokerBatchSizeDefault    = 100
+	brokerTimeoutDefault      = 30 * time.Second
+	// the oldest queued rollback is dropped when the queue is full
+	brokerRollBacksQueueSize = 1000
+)
+
+// BrokerMessage is one message published to the message broker. Value is JSON encoded Event
+type BrokerMessage struct {
+	Topic string
+	// tx hash for txs, block hash for blocks and rollback point hash for rollbacks
+	Key   string
+	Value []byte
+}
+
+// BrokerPublisher is implemented by adapters of message brokers (see broker/nats and broker/memory packages)
+type BrokerPublisher interface {
+	// Publish returns nil only after broker acknowledged all messages
+	Publish(ctx context.Context, messages []*BrokerMessage) error
+	Close() error
+}
+
+type BrokerSinkConfig struct {
+	// topics (NATS subjects) of the events. Events of the type with empty topic are not published
+	TxsTopic       string `json:"txsTopic"`
+	BlocksTopic    string `json:"blocksTopic"`
+	RollBacksTopic string `json:"rollBacksTopic"`
+	// how often unprocessed confirmed txs are checked
+	PollInterval time.Duration `json:"pollInterval"`
+	// maximum number of txs published at once
+	BatchSize int `json:"batchSize"`
+	// timeout of one publish
+	Timeout time.Duration `json:"timeout"`
+}
+
+type BrokerSinkDB interface {
+	GetConfirmedBlocksInSlotRange(fromSlot uint64, toSlot uint64) ([]*CardanoBlock, error)
+}
+
+// BrokerSink publishes unprocessed confirmed txs and their blocks to the message broker.
+// Txs are marked as processed only after broker acknowledged them, so delivery is at least once
+// and consumers should deduplicate messages by key
+type BrokerSink struct {
+	config    *BrokerSinkConfig
+	queue     ConfirmedTxsQueue
+	db        BrokerSinkDB
+	publisher BrokerPublisher
+	logger    hclog.Logger
+
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+	wg        sync.WaitGroup
+
+	// rollbacks are queued by the syncer and published by the sink routine, so the syncer is never blocked
+	rollBacks     []*BrokerMessage
+	rollBacksLock sync.Mutex
+	rollBackCh    chan struct{}
+}
+
+func NewBrokerSink(
+	config *BrokerSinkConfig, queue ConfirmedTxsQueue, db BrokerSinkDB, publisher BrokerPublisher, logger hclog.Logger,
+) *BrokerSink {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+
+	return &BrokerSink{
+		config:     config,
+		queue:      queue,
+		db:         db,
+		publisher:  publisher,
+		logger:     logger,
+		ctx:        ctx,
+		cancelCtx:  cancelCtx,
+		rollBackCh: make(chan struct{}, 1),
+	}
+}
+
+// Start publishes txs periodically in a separate routine until Close is called
+func (bs *BrokerSink) Start() {
+	interval := bs.config.PollInterval
+	if interval <= 0 {
+		interval = brokerPollIntervalDefault
+	}
+
+	bs.wg.Add(1)
+
+	go func() {
+		defer bs.wg.Done()
+
+		for {
+			cnt, err := bs.Publish()
+			if err != nil && bs.ctx.Err() == nil {
+				bs.logger.Error("Error while publishing to the broker", "err", err)
+			}
+
+			// full batch has been published, there may be more txs
+			if err == nil && cnt > 0 && cnt == bs.batchSize() {
+				continue
+			}
+
+			select {
+			case <-bs.ctx.Done():
+				return
+			case <-bs.rollBackCh:
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// Close stops publishing. Publisher is not closed
+func (bs *BrokerSink) Close() error {
+	bs.cancelCtx()
+	bs.wg.Wait()
+
+	return nil
+}
+
+// Publish publishes queued rollbacks and then one batch of unprocessed confirmed txs (and blocks which contain them)
+// and returns number of processed txs
+func (bs *BrokerSink) Publish() (int, error) {
+	if err := bs.publishRollBacks(); err != nil {
+		return 0, err
+	}
+
+	txs, err := bs.queue.GetUnprocessedConfirmedTxs(bs.batchSize())
+	if err != nil || len(txs) == 0 {
+		return 0, err
+	}
+
+	var messages []*BrokerMessage
+
+	if bs.config.BlocksTopic != "" {
+		blocks, err := bs.db.GetConfirmedBlocksInSlotRange(txs[0].BlockSlot, txs[len(txs)-1].BlockSlot)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, block := range blocks {
+			// block without txs of the batch is published with another batch or it is not published at all
+			if len(block.Txs) == 0 || !containsBlockTx(txs, block.Slot) {
+				continue
+			}
+
+			msg, err := newBrokerMessage(bs.config.BlocksTopic, block.Hash.String(),
+				Event{Type: EventBlockConfirmed, Block: block})
+			if err != nil {
+				return 0, err
+			}
+
+			messages = append(messages, msg)
+		}
+	}
+
+	if bs.config.TxsTopic != "" {
+		for _, tx := range txs {
+			msg, err := newBrokerMessage(bs.config.TxsTopic, tx.Hash.String(), Event{Type: EventTxConfirmed, Tx: tx})
+			if err != nil {
+				return 0, err
+			}
+
+			messages = append(messages, msg)
+		}
+	}
+
+	if err := bs.publish(messages); err != nil {
+		return 0, err
+	}
+
+	if err := bs.queue.MarkConfirmedTxsProcessed(txs); err != nil {
+		return 0, err
+	}
+
+	return len(txs), nil
+}
+
+// WrapHandler returns syncer handler which queues rollbacks of the handler. Queued rollbacks are published
+// by the sink routine (or Publish) before txs and failed publish is retried. Rollbacks are not persisted,
+// so rollbacks which are still queued when the sink is closed are not published
+func (bs *BrokerSink) WrapHandler(handler BlockSyncerHandler) BlockSyncerHandler {
+	return &brokerSinkSyncerHandler{
+		BlockSyncerHandler: handler,
+		sink:               bs,
+	}
+}
+
+func (bs *BrokerSink) queueRollBack(msg *BrokerMessage) {
+	bs.rollBacksLock.Lock()
+
+	if len(bs.rollBacks) >= brokerRollBacksQueueSize {
+		bs.logger.Warn("Rollbacks queue is full, the oldest rollback is dropped", "key", bs.rollBacks[0].Key)
+
+		bs.rollBacks = bs.rollBacks[1:]
+	}
+
+	bs.rollBacks = append(bs.rollBacks, msg)
+
+	bs.rollBacksLock.Unlock()
+
+	select {
+	case bs.rollBackCh <- struct{}{}:
+	default:
+	}
+}
+
+// publishRollBacks publishes all queued rollbacks. Rollbacks are queued again if publish fails
+func (bs *BrokerSink) publishRollBacks() error {
+	bs.rollBacksLock.Lock()
+	messages := bs.rollBacks
+	bs.rollBacks = nil
+	bs.rollBacksLock.Unlock()
+
+	if err := bs.publish(messages); err != nil {
+		bs.rollBacksLock.Lock()
+		bs.rollBacks = append(messages, bs.rollBacks...)
+		bs.rollBacks = bs.rollBacks[max(0, len(bs.rollBacks)-brokerRollBacksQueueSize):]
+		bs.rollBacksLock.Unlock()
+
+		return fmt.Errorf("could not publish rollbacks: %w", err)
+	}
+
+	return nil
+}
+
+func (bs *BrokerSink) publish(messages []*BrokerMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	timeout := bs.config.Timeout
+	if timeout <= 0 {
+		timeout = brokerTimeoutDefault
+	}
+
+	ctx, cancel := context.WithTimeout(bs.ctx, timeout)
+	defer cancel()
+
+	if err := bs.publisher.Publish(ctx, messages); err != nil {
+		return fmt.Errorf("could not publish %d messages: %w", len(messages), err)
+	}
+
+	return nil
+}
+
+func (bs *BrokerSink) batchSize() int {
+	if bs.config.BatchSize <= 0 {
+		return brokerBatchSizeDefault
+	}
+
+	return bs.config.BatchSize
+}
+
+func newBrokerMessage(topic string, key string, event Event) (*BrokerMessage, error) {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal %s event: %w", event.Type, err)
+	}
+
+	return &BrokerMessage{
+		Topic: topic,
+		Key:   key,
+		Value: value,
+	}, nil
+}
+
+func containsBlockTx(txs []*Tx, slot uint64) bool {
+	for _, tx := range txs {
+		if tx.BlockSlot == slot {
+			return true
+		}
+	}
+
+	return false
+}
+
+type brokerSinkSyncerHandler struct {
+	BlockSyncerHandler
+	sink *BrokerSink
+}
+
+func (h *brokerSinkSyncerHandler) RollBackwardFunc(point common.Point) error {
+	if err := h.BlockSyncerHandler.RollBackwardFunc(point); err != nil {
+		return err
+	}
+
+	if h.sink.config.RollBacksTopic == "" {
+		return nil
+	}
+
+	blockPoint := &BlockPoint{
+		BlockSlot: point.Slot,
+		BlockHash: NewHashFromBytes(point.Hash),
+	}
+
+	msg, err := newBrokerMessage(h.sink.config.RollBacksTopic, blockPoint.BlockHash.String(),
+		Event{Type: EventRollBack, Point: blockPoint})
+	if err != nil {
+		h.sink.logger.Error("Could not publish rollback", "slot", point.Slot, "err", err)
+
+		return nil
+	}
+
+	h.sink.queueRollBack(msg)
+
+	return nil
+}
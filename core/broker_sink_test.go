@@ -0,0 +1,128 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrokerSink_Publish(t *testing.T) {
+	t.Parallel()
+
+	txs := []*Tx{
+		{BlockSlot: 10, Hash: Hash{1}},
+		{BlockSlot: 10, Indx: 1, Hash: Hash{2}},
+		{BlockSlot: 30, Hash: Hash{3}},
+	}
+	blocks := []*CardanoBlock{
+		{Slot: 10, Hash: Hash{10}, Txs: []Hash{{1}, {2}}},
+		{Slot: 20, Hash: Hash{20}},
+		{Slot: 30, Hash: Hash{30}, Txs: []Hash{{3}}},
+	}
+	publisherMock := &BrokerPublisherMock{}
+	dbMock := &DatabaseMock{}
+	sink := NewBrokerSink(&BrokerSinkConfig{
+		TxsTopic:       "txs",
+		BlocksTopic:    "blocks",
+		RollBacksTopic: "rollbacks",
+	}, dbMock, dbMock, publisherMock, hclog.NewNullLogger())
+
+	defer sink.Close()
+
+	dbMock.On("GetUnprocessedConfirmedTxs", brokerBatchSizeDefault).Return(txs, error(nil)).Twice()
+	dbMock.On("GetConfirmedBlocksInSlotRange", uint64(10), uint64(30)).Return(blocks, error(nil)).Twice()
+
+	// nothing is marked as processed if broker does not acknowledge messages
+	publisherMock.On("Publish", mock.Anything).Return(errors.New("broker is down")).Once()
+
+	_, err := sink.Publish()
+	require.ErrorContains(t, err, "broker is down")
+	dbMock.AssertNotCalled(t, "MarkConfirmedTxsProcessed")
+
+	var messages []*BrokerMessage
+
+	publisherMock.On("Publish", mock.Anything).Run(func(args mock.Arguments) {
+		messages = args.Get(0).([]*BrokerMessage) //nolint:forcetypeassert
+	}).Return(error(nil)).Once()
+	dbMock.On("MarkConfirmedTxsProcessed", txs).Return(error(nil)).Once()
+
+	cnt, err := sink.Publish()
+	require.NoError(t, err)
+	require.Equal(t, 3, cnt)
+
+	// blocks are published before their txs
+	require.Len(t, messages, 5)
+	require.Equal(t, &BrokerMessage{Topic: "blocks", Key: blocks[0].Hash.String(), Value: messages[0].Value}, messages[0])
+	require.Equal(t, &BrokerMessage{Topic: "blocks", Key: blocks[2].Hash.String(), Value: messages[1].Value}, messages[1])
+
+	for i, msg := range messages[2:] {
+		var event Event
+
+		require.Equal(t, "txs", msg.Topic)
+		require.NoError(t, json.Unmarshal(msg.Value, &event))
+		require.Equal(t, txs[i].Hash.String(), msg.Key)
+		require.Equal(t, Event{Type: EventTxConfirmed, Tx: txs[i]}, event)
+	}
+
+	handler := sink.WrapHandler(&syncerHandlerStub{})
+	isRollBack := mock.MatchedBy(func(messages []*BrokerMessage) bool {
+		return len(messages) == 1 && messages[0].Topic == "rollbacks" &&
+			messages[0].Key == NewHashFromBytes([]byte{2}).String()
+	})
+
+	// rollback is only queued, so the syncer is not blocked by the broker
+	require.NoError(t, handler.RollBackwardFunc(common.Point{Slot: 8, Hash: []byte{2}}))
+	publisherMock.AssertNumberOfCalls(t, "Publish", 2)
+
+	// failed rollback is published again before txs
+	publisherMock.On("Publish", isRollBack).Return(errors.New("broker is down")).Once()
+
+	_, err = sink.Publish()
+	require.ErrorContains(t, err, "broker is down")
+
+	publisherMock.On("Publish", isRollBack).Return(error(nil)).Once()
+	dbMock.On("GetUnprocessedConfirmedTxs", brokerBatchSizeDefault).Return([]*Tx(nil), error(nil)).Once()
+
+	cnt, err = sink.Publish()
+	require.NoError(t, err)
+	require.Equal(t, 0, cnt)
+
+	publisherMock.AssertExpectations(t)
+	dbMock.AssertExpectations(t)
+}
+
+func TestBrokerSink_StartPublishesRollBack(t *testing.T) {
+	t.Parallel()
+
+	publisherMock := &BrokerPublisherMock{}
+	dbMock := &DatabaseMock{}
+	sink := NewBrokerSink(&BrokerSinkConfig{
+		RollBacksTopic: "rollbacks",
+		PollInterval:   time.Hour,
+	}, dbMock, dbMock, publisherMock, hclog.NewNullLogger())
+	publishedCh := make(chan struct{})
+
+	dbMock.On("GetUnprocessedConfirmedTxs", brokerBatchSizeDefault).Return([]*Tx(nil), error(nil))
+	publisherMock.On("Publish", mock.Anything).Run(func(mock.Arguments) {
+		close(publishedCh)
+	}).Return(error(nil)).Once()
+
+	sink.Start()
+
+	defer sink.Close()
+
+	require.NoError(t, sink.WrapHandler(&syncerHandlerStub{}).RollBackwardFunc(common.Point{Slot: 8, Hash: []byte{2}}))
+
+	// sink routine does not wait for the poll interval
+	select {
+	case <-publishedCh:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "rollback is not published")
+	}
+}
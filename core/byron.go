@@ -0,0 +1,37 @@
+package core
+
+import (
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/ledger/byron"
+)
+
+// IsEpochBoundaryBlock returns true for byron epoch boundary block (EBB) headers.
+// EBBs have no transactions and share the slot with the first block of the epoch
+func IsEpochBoundaryBlock(header ledger.BlockHeader) bool {
+	_, ok := header.(*byron.ByronEpochBoundaryBlockHeader)
+
+	return ok
+}
+
+// GetBlockNumber returns number (height) of the block. Byron blocks do not contain block number
+// so chain difficulty is used instead. EBB has the same difficulty as the block before it
+func GetBlockNumber(header ledger.BlockHeader) uint64 {
+	switch h := header.(type) {
+	case *byron.ByronMainBlockHeader:
+		return h.ConsensusData.Difficulty.Unknown
+	case *byron.ByronEpochBoundaryBlockHeader:
+		return h.ConsensusData.Difficulty.Value
+	default:
+		return header.BlockNumber()
+	}
+}
+
+// getTxMetadata returns auxiliary data of the tx. Byron txs have attributes instead of metadata so nil is returned
+func getTxMetadata(tx ledger.Transaction) *cbor.LazyValue {
+	if _, ok := tx.(*byron.ByronTransaction); ok {
+		return nil
+	}
+
+	return tx.Metadata()
+}
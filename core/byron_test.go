@@ -0,0 +1,285 @@
+package core
+
+import (
+	"encoding/hex"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros"
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/ledger/byron"
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// headers of the first two blocks of the legacy byron testnet (genesis EBB and the first main block)
+const (
+	byronEBBHeaderHex = "851a4170cb17582096fceff972c2c06bd3bb5243c39215333be6d56aaf4823073dca31afe5038471582069b" +
+		"c32be98bb4567c0d60f571781e92c9f3100b119d03ff8886ad3f4e2a0eff78200810081a0"
+	byronEBBHash            = "8f8602837f7c6f8b8867dd1cbc1842cf51a27eaed2c70ef48325d00f8efb320f"
+	byronMainBlockHeaderHex = "851a4170cb1758208f8602837f7c6f8b8867dd1cbc1842cf51a27eaed2c70ef48325d00f8efb320f84830" +
+		"058200e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a85820afc0da64183bf2664f3d4eec7238d524b" +
+		"a607faeeab24fc100eb861dba69971b8300582022e2e7a984e1e0ac38c309419d1e6ab24cb8c63fc368c1f0ed8c4fc01ce35121582" +
+		"0d36a2619a672494604e11bb447cbcf5231e9f2ba25c2169177edc941bd50ad6c5820afc0da64183bf2664f3d4eec7238d524ba607" +
+		"faeeab24fc100eb861dba69971b58204e66280cd94d591072349bec0a3090a53aa945562efb6d08d56e53654b0e409884820019040" +
+		"758406cef56d6af8845f317949bcdff92358f1394fe11b45c02b28e1d5854c0fa13f8e079644dfc5041afb0fcbd1f47ec7ce064f37" +
+		"b7ec5c070c784a5619ebae2aa608101820282840058406cef56d6af8845f317949bcdff92358f1394fe11b45c02b28e1d5854c0fa1" +
+		"3f8e079644dfc5041afb0fcbd1f47ec7ce064f37b7ec5c070c784a5619ebae2aa605840dfb615a61568d6867f45a85c32227f27025" +
+		"180d738a8a3d7fd3c929f624d723929801b343641f148087b117e87e9695f215dbcf830ff0d58a066682c7603f6415840a9c6e4abf" +
+		"95755084a86625a98e185de75d4d33d557284d3aa67ecff3dcfd8e77994fc8b3fc4c2762879ca0c15458ade936db0977ae10dd0129" +
+		"1cf3241b9530e584003a38f3ea6e5e154fc7a92e6d14930143a18cc4613c9a045418fec9059bda47ce000f3abd837b8f09c4d9d2ba" +
+		"3d7cd4a6aeea4cf93e55e2980479c619ac1220b8483000000826a63617264616e6f2d736c00a058204ba92aa320c60acc9ad7b9a64" +
+		"f2eda55c4d2ec28e604faf186708b4f0c4e8edf"
+	byronMainBlockHash = "388a82f053603f3552717d61644a353188f2d5500f4c6354cc1ad27a36a7ea91"
+
+	// first output of the first tx of the recorded main block
+	byronAddress = "DdzFFzCqrhszvGBcfWZoxTuSXS7whAi5xEyD6pD53x4nXfRhNR4dg15XsTS8LVTH8Njy6VYaASWkbXEouEWr78AsUuUY" +
+		"q6Lf9QCv48Y4"
+	byronTxHash = "a0e512aa896f26038077bcc7c20aba4564fe380398058dc7fe65056392c60875"
+)
+
+func newByronBlockHeader(t *testing.T, blockType uint, headerHex string) ledger.BlockHeader {
+	t.Helper()
+
+	data, err := hex.DecodeString(headerHex)
+	require.NoError(t, err)
+
+	header, err := ledger.NewBlockHeaderFromCbor(blockType, data)
+	require.NoError(t, err)
+
+	return header
+}
+
+// newByronMainBlock returns recorded byron main block of the mainnet (slot 4471207) with two txs
+func newByronMainBlock(t *testing.T) (*byron.ByronMainBlock, *testChainBlock) {
+	t.Helper()
+
+	hexData, err := os.ReadFile("testdata/byron_main_block.hex")
+	require.NoError(t, err)
+
+	data, err := hex.DecodeString(strings.TrimSpace(string(hexData)))
+	require.NoError(t, err)
+
+	block, err := byron.NewByronMainBlockFromCbor(data)
+	require.NoError(t, err)
+
+	hash, err := hex.DecodeString(block.Hash())
+	require.NoError(t, err)
+
+	return block, &testChainBlock{
+		slot:      block.SlotNumber(),
+		number:    GetBlockNumber(block.Header),
+		hash:      hash,
+		blockType: ledger.BlockTypeByronMain,
+		block:     data,
+	}
+}
+
+// newByronTxsRetriever returns block txs retriever connected to the stand-in node which serves the blocks
+func newByronTxsRetriever(t *testing.T, blocks ...*testChainBlock) BlockTxsRetriever {
+	t.Helper()
+
+	node := startTestChainNode(t, blocks, 0)
+
+	conn, err := net.Dial("tcp", node.Address())
+	require.NoError(t, err)
+
+	connection, err := ouroboros.NewConnection(
+		ouroboros.WithConnection(conn),
+		ouroboros.WithNetworkMagic(testChainNetworkMagic),
+		ouroboros.WithNodeToNode(true),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = connection.Close()
+	})
+
+	return NewBlockTxsRetriever(connection, hclog.NewNullLogger())
+}
+
+func TestByronBlockHeaders(t *testing.T) {
+	t.Parallel()
+
+	ebbHeader := newByronBlockHeader(t, ledger.BlockTypeByronEbb, byronEBBHeaderHex)
+	mainHeader := newByronBlockHeader(t, ledger.BlockTypeByronMain, byronMainBlockHeaderHex)
+
+	assert.True(t, IsEpochBoundaryBlock(ebbHeader))
+	assert.False(t, IsEpochBoundaryBlock(mainHeader))
+	assert.False(t, IsEpochBoundaryBlock(&LedgerBlockHeaderMock{}))
+
+	assert.Equal(t, uint64(0), GetBlockNumber(ebbHeader))
+	assert.Equal(t, uint64(1), GetBlockNumber(mainHeader))
+	assert.Equal(t, uint64(12), GetBlockNumber(&LedgerBlockHeaderMock{BlockNumberVal: 12}))
+
+	assert.Equal(t, &CardanoBlock{
+		Slot:          0,
+		Hash:          NewHashFromHexString(byronEBBHash),
+		Number:        0,
+		EraName:       "Byron",
+		EpochBoundary: true,
+	}, NewCardanoBlock(ebbHeader, nil))
+
+	assert.Equal(t, &CardanoBlock{
+		Slot:    1031,
+		Hash:    NewHashFromHexString(byronMainBlockHash),
+		Number:  1,
+		EraName: "Byron",
+	}, NewCardanoBlock(mainHeader, nil))
+}
+
+func TestByronBlockTransactions(t *testing.T) {
+	t.Parallel()
+
+	block, chainBlock := newByronMainBlock(t)
+
+	require.Equal(t, uint64(4471207), block.SlotNumber())
+	require.Equal(t, "1451a0dbf16cfeddf4991a838961df1b08a68f43a19c0eb3b36cc4029c77a2d8", block.Hash())
+
+	txs, err := newByronTxsRetriever(t, chainBlock).GetBlockTransactions(block.Header)
+	require.NoError(t, err)
+
+	require.Len(t, txs, 2)
+	assert.Equal(t, byronTxHash, txs[0].Hash())
+	assert.Equal(t, "b7f12a75091ac0c5eac7de455fa46e342ba6dd411f1b2f157dc4142128fff71e", txs[1].Hash())
+	assert.Len(t, txs[0].Inputs(), 4)
+	assert.Len(t, txs[0].Outputs(), 2)
+	assert.Len(t, txs[1].Inputs(), 1)
+	assert.Len(t, txs[1].Outputs(), 2)
+}
+
+func TestByronAddress(t *testing.T) {
+	t.Parallel()
+
+	block, _ := newByronMainBlock(t)
+	tx := block.Transactions()[0]
+
+	require.Len(t, tx.Outputs(), 2)
+	assert.Equal(t, byronAddress, LedgerAddressToString(tx.Outputs()[0].Address()))
+	assert.Equal(t, uint64(5172024689), tx.Outputs()[0].Amount())
+
+	addr, err := ledger.NewAddress(byronAddress)
+	require.NoError(t, err)
+
+	assert.Equal(t, byronAddress, LedgerAddressToString(addr))
+	assert.Nil(t, getTxMetadata(tx))
+}
+
+func TestBlockIndexer_RollForwardFuncByronFromGenesis(t *testing.T) {
+	t.Parallel()
+
+	var (
+		confirmedBlocks []*CardanoBlock
+		confirmedTxs    []*Tx
+	)
+
+	ebbHeader := newByronBlockHeader(t, ledger.BlockTypeByronEbb, byronEBBHeaderHex)
+	block, chainBlock := newByronMainBlock(t)
+	mainHeader := block.Header
+	txsRetriever := newByronTxsRetriever(t, chainBlock)
+	txHash := NewHashFromHexString(byronTxHash)
+	blockHash := NewHashFromHexString(block.Hash())
+	expectedInputs := make([]*TxInput, 0, 4)
+
+	for _, inp := range block.Transactions()[0].Inputs() {
+		expectedInputs = append(expectedInputs, &TxInput{Hash: Hash(inp.Id()), Index: inp.Index()})
+	}
+
+	getTxsMock := &BlockTxsRetrieverMock{
+		RetrieveFn: func(blockHeader ledger.BlockHeader) ([]ledger.Transaction, error) {
+			require.False(t, IsEpochBoundaryBlock(blockHeader), "txs of EBB should not be retrieved")
+
+			return txsRetriever.GetBlockTransactions(blockHeader)
+		},
+	}
+	config := &BlockIndexerConfig{
+		AddressCheck:           AddressCheckOutputs,
+		AddressesOfInterest:    []string{byronAddress},
+		ConfirmationBlockCount: 1,
+	}
+	dbMock := &DatabaseMock{
+		Writter: &DBTransactionWriterMock{},
+	}
+	newConfirmedBlockHandler := func(cb *CardanoBlock, txs []*Tx) error {
+		confirmedBlocks = append(confirmedBlocks, cb)
+		confirmedTxs = append(confirmedTxs, txs...)
+
+		return nil
+	}
+	blockIndexer := NewBlockIndexer(config, newConfirmedBlockHandler, dbMock, hclog.NewNullLogger())
+
+	// sync from genesis
+	dbMock.On("GetLatestBlockPoint").Return((*BlockPoint)(nil), error(nil)).Once()
+
+	latestPoint, err := blockIndexer.Reset()
+	require.NoError(t, err)
+	require.Equal(t, common.NewPointOrigin(), latestPoint.ToCommonPoint())
+
+	require.NoError(t, blockIndexer.RollForwardFunc(ebbHeader, getTxsMock))
+
+	// EBB is confirmed: latest point is updated but block is not saved
+	dbMock.On("OpenTx").Twice()
+	dbMock.Writter.On("Execute").Return(error(nil)).Twice()
+	dbMock.Writter.On("SetLatestBlockPoint", &BlockPoint{
+		BlockSlot:   0,
+		BlockHash:   NewHashFromHexString(byronEBBHash),
+		BlockNumber: 0,
+	}).Once()
+	dbMock.Writter.On("AddTxOutputs", ([]*TxInputOutput)(nil)).Once()
+	dbMock.Writter.On("RemoveTxOutputs", ([]*TxInput)(nil), false).Once()
+
+	require.NoError(t, blockIndexer.RollForwardFunc(mainHeader, getTxsMock))
+	require.Len(t, confirmedBlocks, 1)
+	require.True(t, confirmedBlocks[0].EpochBoundary)
+	require.Empty(t, confirmedTxs)
+
+	// recorded main block is confirmed: block, tx and output of the byron address are saved
+	dbMock.On("GetTxOutput", mock.Anything).Return(TxOutput{}, error(nil))
+	dbMock.Writter.On("SetLatestBlockPoint", &BlockPoint{
+		BlockSlot:   block.SlotNumber(),
+		BlockHash:   blockHash,
+		BlockNumber: chainBlock.number,
+	}).Once()
+	dbMock.Writter.On("AddTxOutputs", []*TxInputOutput{
+		{
+			Input: TxInput{Hash: txHash},
+			Output: TxOutput{
+				Address: byronAddress,
+				Slot:    block.SlotNumber(),
+				Amount:  5172024689,
+			},
+		},
+	}).Once()
+	dbMock.Writter.On("RemoveTxOutputs", expectedInputs, false).Once()
+	dbMock.Writter.On("AddConfirmedTxs", mock.Anything).Once()
+	dbMock.Writter.On("AddConfirmedBlock", &CardanoBlock{
+		Slot:    block.SlotNumber(),
+		Hash:    blockHash,
+		Number:  chainBlock.number,
+		EraName: "Byron",
+		Txs:     []Hash{txHash},
+	}).Once()
+
+	require.NoError(t, blockIndexer.RollForwardFunc(&LedgerBlockHeaderMock{
+		SlotNumberVal: block.SlotNumber() + 1, HashVal: bytes2HashString([]byte{1}),
+	}, getTxsMock))
+	require.Len(t, confirmedBlocks, 2)
+	require.False(t, confirmedBlocks[1].EpochBoundary)
+	require.Len(t, confirmedTxs, 1)
+	assert.Equal(t, txHash, confirmedTxs[0].Hash)
+	assert.Nil(t, confirmedTxs[0].Metadata)
+	require.Len(t, confirmedTxs[0].Outputs, 2)
+	assert.Equal(t, byronAddress, confirmedTxs[0].Outputs[0].Address)
+	require.Len(t, confirmedTxs[0].Inputs, 4)
+
+	dbMock.AssertExpectations(t)
+	dbMock.Writter.AssertExpectations(t)
+	dbMock.Writter.AssertNotCalled(t, "AddConfirmedBlock", mock.MatchedBy(func(cb *CardanoBlock) bool {
+		return cb.EpochBoundary
+	}))
+}
@@ -0,0 +1,316 @@
+package core
+
+import (
+	"encoding/hex"
+	"sort"
+
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+type CertificateKind string
+
+const (
+	CertificateStakeRegistration           CertificateKind = "stakeRegistration"
+	CertificateStakeDeregistration         CertificateKind = "stakeDeregistration"
+	CertificateStakeDelegation             CertificateKind = "stakeDelegation"
+	CertificateStakeRegistrationDelegation CertificateKind = "stakeRegistrationDelegation"
+	CertificatePoolRegistration            CertificateKind = "poolRegistration"
+	CertificatePoolRetirement              CertificateKind = "poolRetirement"
+	CertificateVoteDelegation              CertificateKind = "voteDelegation"
+	CertificateVoteRegistrationDelegation  CertificateKind = "voteRegistrationDelegation"
+	CertificateDRepRegistration            CertificateKind = "drepRegistration"
+	CertificateDRepDeregistration          CertificateKind = "drepDeregistration"
+	CertificateDRepUpdate                  CertificateKind = "drepUpdate"
+	CertificateCommitteeHotAuth            CertificateKind = "committeeHotAuth"
+	CertificateCommitteeColdResign         CertificateKind = "committeeColdResign"
+)
+
+type DRepType string
+
+const (
+	DRepTypeKeyHash      DRepType = "keyHash"
+	DRepTypeScriptHash   DRepType = "scriptHash"
+	DRepTypeAbstain      DRepType = "abstain"
+	DRepTypeNoConfidence DRepType = "noConfidence"
+)
+
+var drepTypes = []DRepType{DRepTypeKeyHash, DRepTypeScriptHash, DRepTypeAbstain, DRepTypeNoConfidence}
+
+const (
+	StakeCredentialKeyHash    = ledgerCommon.StakeCredentialTypeAddrKeyHash
+	StakeCredentialScriptHash = ledgerCommon.StakeCredentialTypeScriptHash
+)
+
+type StakeCredential struct {
+	Type uint   `json:"type"`
+	Hash string `json:"hash"`
+}
+
+// DRep is a delegate representative. Hash is empty for abstain and no confidence
+type DRep struct {
+	Type DRepType `json:"type"`
+	Hash string   `json:"hash,omitempty"`
+}
+
+type PoolParams struct {
+	VrfKeyHash    string   `json:"vrf"`
+	Pledge        uint64   `json:"pledge"`
+	Cost          uint64   `json:"cost"`
+	Margin        string   `json:"margin"`
+	RewardAccount string   `json:"reward"`
+	Owners        []string `json:"owners,omitempty"`
+	MetadataURL   string   `json:"metaUrl,omitempty"`
+	MetadataHash  string   `json:"metaHash,omitempty"`
+}
+
+type Certificate struct {
+	Kind            CertificateKind  `json:"kind"`
+	StakeCredential *StakeCredential `json:"cred,omitempty"`
+	PoolID          string           `json:"pool,omitempty"`
+	// deposit paid (or refunded for deregistration), only present since Conway
+	Deposit int64 `json:"deposit,omitempty"`
+	// epoch in which the pool retires
+	Epoch uint64      `json:"epoch,omitempty"`
+	Pool  *PoolParams `json:"params,omitempty"`
+	// drep which is registered, updated or delegated to
+	DRep *DRep `json:"drep,omitempty"`
+	// committee hot credential authorized by the cold credential (StakeCredential)
+	HotCredential *StakeCredential `json:"hotCred,omitempty"`
+	Anchor        *Anchor          `json:"anchor,omitempty"`
+}
+
+type Withdrawal struct {
+	RewardAddress   string          `json:"addr"`
+	StakeCredential StakeCredential `json:"cred"`
+	Amount          uint64          `json:"amnt"`
+}
+
+// NewCertificate converts ledger certificate.
+// nil is returned for certificates which are not staking or governance related
+func NewCertificate(cert ledgerCommon.Certificate) *Certificate {
+	switch c := cert.(type) {
+	case *ledgerCommon.StakeRegistrationCertificate:
+		return &Certificate{
+			Kind:            CertificateStakeRegistration,
+			StakeCredential: newStakeCredential(&c.StakeRegistration),
+		}
+	case *ledgerCommon.RegistrationCertificate:
+		return &Certificate{
+			Kind:            CertificateStakeRegistration,
+			StakeCredential: newStakeCredential(&c.StakeCredential),
+			Deposit:         c.Amount,
+		}
+	case *ledgerCommon.StakeDeregistrationCertificate:
+		return &Certificate{
+			Kind:            CertificateStakeDeregistration,
+			StakeCredential: newStakeCredential(&c.StakeDeregistration),
+		}
+	case *ledgerCommon.DeregistrationCertificate:
+		return &Certificate{
+			Kind:            CertificateStakeDeregistration,
+			StakeCredential: newStakeCredential(&c.StakeCredential),
+			Deposit:         c.Amount,
+		}
+	case *ledgerCommon.StakeDelegationCertificate:
+		return &Certificate{
+			Kind:            CertificateStakeDelegation,
+			StakeCredential: newStakeCredential(c.StakeCredential),
+			PoolID:          ledgerCommon.PoolId(c.PoolKeyHash).String(),
+		}
+	case *ledgerCommon.StakeVoteDelegationCertificate:
+		return &Certificate{
+			Kind:            CertificateStakeDelegation,
+			StakeCredential: newStakeCredential(&c.StakeCredential),
+			PoolID:          poolIDFromBytes(c.PoolKeyHash),
+			DRep:            newDRep(c.Drep),
+		}
+	case *ledgerCommon.StakeRegistrationDelegationCertificate:
+		return &Certificate{
+			Kind:            CertificateStakeRegistrationDelegation,
+			StakeCredential: newStakeCredential(&c.StakeCredential),
+			PoolID:          poolIDFromBytes(c.PoolKeyHash),
+			Deposit:         c.Amount,
+		}
+	case *ledgerCommon.StakeVoteRegistrationDelegationCertificate:
+		return &Certificate{
+			Kind:            CertificateStakeRegistrationDelegation,
+			StakeCredential: newStakeCredential(&c.StakeCredential),
+			PoolID:          poolIDFromBytes(c.PoolKeyHash),
+			Deposit:         c.Amount,
+			DRep:            newDRep(c.Drep),
+		}
+	case *ledgerCommon.VoteDelegationCertificate:
+		return &Certificate{
+			Kind:            CertificateVoteDelegation,
+			StakeCredential: newStakeCredential(&c.StakeCredential),
+			DRep:            newDRep(c.Drep),
+		}
+	case *ledgerCommon.VoteRegistrationDelegationCertificate:
+		return &Certificate{
+			Kind:            CertificateVoteRegistrationDelegation,
+			StakeCredential: newStakeCredential(&c.StakeCredential),
+			DRep:            newDRep(c.Drep),
+			Deposit:         c.Amount,
+		}
+	case *ledgerCommon.RegistrationDrepCertificate:
+		return &Certificate{
+			Kind:    CertificateDRepRegistration,
+			DRep:    newDRepFromCredential(&c.DrepCredential),
+			Deposit: c.Amount,
+			Anchor:  newOptionalAnchor(c.Anchor),
+		}
+	case *ledgerCommon.DeregistrationDrepCertificate:
+		return &Certificate{
+			Kind:    CertificateDRepDeregistration,
+			DRep:    newDRepFromCredential(&c.DrepCredential),
+			Deposit: c.Amount,
+		}
+	case *ledgerCommon.UpdateDrepCertificate:
+		return &Certificate{
+			Kind:   CertificateDRepUpdate,
+			DRep:   newDRepFromCredential(&c.DrepCredential),
+			Anchor: newOptionalAnchor(c.Anchor),
+		}
+	case *ledgerCommon.AuthCommitteeHotCertificate:
+		return &Certificate{
+			Kind:            CertificateCommitteeHotAuth,
+			StakeCredential: newStakeCredential(&c.ColdCredential),
+			HotCredential:   newStakeCredential(&c.HostCredential),
+		}
+	case *ledgerCommon.ResignCommitteeColdCertificate:
+		return &Certificate{
+			Kind:            CertificateCommitteeColdResign,
+			StakeCredential: newStakeCredential(&c.ColdCredential),
+			Anchor:          newOptionalAnchor(c.Anchor),
+		}
+	case *ledgerCommon.PoolRegistrationCertificate:
+		params := &PoolParams{
+			VrfKeyHash:    hex.EncodeToString(c.VrfKeyHash[:]),
+			Pledge:        c.Pledge,
+			Cost:          c.Cost,
+			RewardAccount: hex.EncodeToString(c.RewardAccount[:]),
+		}
+
+		if c.Margin.Rat != nil {
+			params.Margin = c.Margin.String()
+		}
+
+		for _, owner := range c.PoolOwners {
+			params.Owners = append(params.Owners, hex.EncodeToString(owner[:]))
+		}
+
+		if c.PoolMetadata != nil {
+			params.MetadataURL = c.PoolMetadata.Url
+			params.MetadataHash = hex.EncodeToString(c.PoolMetadata.Hash[:])
+		}
+
+		return &Certificate{
+			Kind:   CertificatePoolRegistration,
+			PoolID: ledgerCommon.PoolId(c.Operator).String(),
+			Pool:   params,
+		}
+	case *ledgerCommon.PoolRetirementCertificate:
+		return &Certificate{
+			Kind:   CertificatePoolRetirement,
+			PoolID: ledgerCommon.PoolId(c.PoolKeyHash).String(),
+			Epoch:  c.Epoch,
+		}
+	default:
+		return nil
+	}
+}
+
+func NewCertificates(certs []ledgerCommon.Certificate) (result []*Certificate) {
+	for _, cert := range certs {
+		if x := NewCertificate(cert); x != nil {
+			result = append(result, x)
+		}
+	}
+
+	return result
+}
+
+// NewWithdrawals converts ledger withdrawals. Result is sorted by reward address
+func NewWithdrawals(withdrawals map[*ledgerCommon.Address]uint64) []*Withdrawal {
+	if len(withdrawals) == 0 {
+		return nil
+	}
+
+	result := make([]*Withdrawal, 0, len(withdrawals))
+
+	for addr, amount := range withdrawals {
+		// reward address is header byte followed by the stake credential hash
+		bytes := addr.Bytes()
+		credType := uint(StakeCredentialKeyHash)
+
+		if bytes[0]>>4 == ledgerCommon.AddressTypeNoneScript {
+			credType = StakeCredentialScriptHash
+		}
+
+		result = append(result, &Withdrawal{
+			RewardAddress: LedgerAddressToString(*addr),
+			StakeCredential: StakeCredential{
+				Type: credType,
+				Hash: hex.EncodeToString(bytes[1:]),
+			},
+			Amount: amount,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].RewardAddress < result[j].RewardAddress
+	})
+
+	return result
+}
+
+func newStakeCredential(cred *ledgerCommon.StakeCredential) *StakeCredential {
+	if cred == nil {
+		return nil
+	}
+
+	return &StakeCredential{
+		Type: cred.CredType,
+		Hash: hex.EncodeToString(cred.Credential),
+	}
+}
+
+func newDRep(drep ledgerCommon.Drep) *DRep {
+	result := &DRep{
+		Type: getEnumValue(drepTypes, uint(drep.Type), DRepType("unknown")), //nolint:gosec
+	}
+
+	if len(drep.Credential) > 0 {
+		result.Hash = hex.EncodeToString(drep.Credential)
+	}
+
+	return result
+}
+
+func newDRepFromCredential(cred *ledgerCommon.StakeCredential) *DRep {
+	drepType := DRepTypeKeyHash
+	if cred.CredType == StakeCredentialScriptHash {
+		drepType = DRepTypeScriptHash
+	}
+
+	return &DRep{
+		Type: drepType,
+		Hash: hex.EncodeToString(cred.Credential),
+	}
+}
+
+func newOptionalAnchor(anchor *ledgerCommon.GovAnchor) *Anchor {
+	if anchor == nil {
+		return nil
+	}
+
+	return newAnchor(*anchor)
+}
+
+func poolIDFromBytes(bytes []byte) string {
+	var poolID ledgerCommon.PoolId
+
+	copy(poolID[:], bytes)
+
+	return poolID.String()
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"encoding/hex"
+	"testing"
+
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCertificates(t *testing.T) {
+	t.Parallel()
+
+	credential := []byte{
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28,
+	}
+	poolKeyHash := ledgerCommon.PoolKeyHash(ledgerCommon.NewBlake2b224(credential))
+	poolID := ledgerCommon.PoolId(poolKeyHash).String()
+
+	certs := NewCertificates([]ledgerCommon.Certificate{
+		&ledgerCommon.StakeRegistrationCertificate{
+			StakeRegistration: ledgerCommon.StakeCredential{
+				CredType:   ledgerCommon.StakeCredentialTypeAddrKeyHash,
+				Credential: credential,
+			},
+		},
+		&ledgerCommon.StakeDelegationCertificate{
+			StakeCredential: &ledgerCommon.StakeCredential{
+				CredType:   ledgerCommon.StakeCredentialTypeScriptHash,
+				Credential: credential,
+			},
+			PoolKeyHash: poolKeyHash,
+		},
+		&ledgerCommon.PoolRetirementCertificate{
+			PoolKeyHash: poolKeyHash,
+			Epoch:       100,
+		},
+		&ledgerCommon.VoteDelegationCertificate{
+			StakeCredential: ledgerCommon.StakeCredential{Credential: credential},
+			Drep:            ledgerCommon.Drep{Type: ledgerCommon.DrepTypeAbstain},
+		},
+		&ledgerCommon.RegistrationDrepCertificate{
+			DrepCredential: ledgerCommon.StakeCredential{
+				CredType:   ledgerCommon.StakeCredentialTypeScriptHash,
+				Credential: credential,
+			},
+			Amount: 500,
+			Anchor: &ledgerCommon.GovAnchor{Url: "https://drep"},
+		},
+		&ledgerCommon.AuthCommitteeHotCertificate{
+			ColdCredential: ledgerCommon.StakeCredential{Credential: credential},
+			HostCredential: ledgerCommon.StakeCredential{Credential: poolKeyHash[:]},
+		},
+		&ledgerCommon.GenesisKeyDelegationCertificate{}, // not staking related
+	})
+
+	require.Equal(t, []*Certificate{
+		{
+			Kind: CertificateStakeRegistration,
+			StakeCredential: &StakeCredential{
+				Type: StakeCredentialKeyHash,
+				Hash: hex.EncodeToString(credential),
+			},
+		},
+		{
+			Kind: CertificateStakeDelegation,
+			StakeCredential: &StakeCredential{
+				Type: StakeCredentialScriptHash,
+				Hash: hex.EncodeToString(credential),
+			},
+			PoolID: poolID,
+		},
+		{
+			Kind:   CertificatePoolRetirement,
+			PoolID: poolID,
+			Epoch:  100,
+		},
+		{
+			Kind: CertificateVoteDelegation,
+			StakeCredential: &StakeCredential{
+				Type: StakeCredentialKeyHash,
+				Hash: hex.EncodeToString(credential),
+			},
+			DRep: &DRep{Type: DRepTypeAbstain},
+		},
+		{
+			Kind:    CertificateDRepRegistration,
+			DRep:    &DRep{Type: DRepTypeScriptHash, Hash: hex.EncodeToString(credential)},
+			Deposit: 500,
+			Anchor:  &Anchor{URL: "https://drep", DataHash: Hash{}.String()},
+		},
+		{
+			Kind: CertificateCommitteeHotAuth,
+			StakeCredential: &StakeCredential{
+				Type: StakeCredentialKeyHash,
+				Hash: hex.EncodeToString(credential),
+			},
+			HotCredential: &StakeCredential{
+				Type: StakeCredentialKeyHash,
+				Hash: hex.EncodeToString(poolKeyHash[:]),
+			},
+		},
+	}, certs)
+}
+
+func TestNewWithdrawals(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewWithdrawals(nil))
+
+	credentials := [][]byte{
+		{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28},
+		{28, 27, 26, 25, 24, 23, 22, 21, 20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+	}
+
+	keyAddr, err := ledgerCommon.NewAddressFromParts(
+		ledgerCommon.AddressTypeNoneKey, ledgerCommon.AddressNetworkMainnet, credentials[0], nil)
+	require.NoError(t, err)
+
+	scriptAddr, err := ledgerCommon.NewAddressFromParts(
+		ledgerCommon.AddressTypeNoneScript, ledgerCommon.AddressNetworkMainnet, credentials[1], nil)
+	require.NoError(t, err)
+
+	withdrawals := NewWithdrawals(map[*ledgerCommon.Address]uint64{
+		&keyAddr:    100,
+		&scriptAddr: 200,
+	})
+
+	require.Len(t, withdrawals, 2)
+	require.Less(t, withdrawals[0].RewardAddress, withdrawals[1].RewardAddress)
+
+	for _, w := range withdrawals {
+		switch w.RewardAddress {
+		case keyAddr.String():
+			require.Equal(t, StakeCredential{
+				Type: StakeCredentialKeyHash,
+				Hash: hex.EncodeToString(credentials[0]),
+			}, w.StakeCredential)
+			require.Equal(t, uint64(100), w.Amount)
+		case scriptAddr.String():
+			require.Equal(t, StakeCredential{
+				Type: StakeCredentialScriptHash,
+				Hash: hex.EncodeToString(credentials[1]),
+			}, w.StakeCredential)
+			require.Equal(t, uint64(200), w.Amount)
+		default:
+			require.Fail(t, "unexpected reward address", w.RewardAddress)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ouroboros "github.com/blinklabs-io/gouroboros"
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/protocol/blockfetch"
+	"github.com/blinklabs-io/gouroboros/protocol/chainsync"
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testChainNetworkMagic = 42
+
+type testChainBlock struct {
+	slot      uint64
+	number    uint64
+	hash      []byte
+	blockType uint
+	block     []byte
+}
+
+func (rb *testChainBlock) point() common.Point {
+	return common.NewPoint(rb.slot, rb.hash)
+}
+
+// newTestChain creates synthetic chain of consecutive blocks by cloning the shelley block from testdata.
+// Only block number, slot and previous hash of the header are patched, so every block has its own hash
+func newTestChain(t testing.TB, cnt int) []*testChainBlock {
+	t.Helper()
+
+	hexData, err := os.ReadFile("testdata/shelley_block.hex")
+	require.NoError(t, err)
+
+	blockData, err := hex.DecodeString(strings.TrimSpace(string(hexData)))
+	require.NoError(t, err)
+
+	var block, header, headerBody []cbor.RawMessage
+
+	_, err = cbor.Decode(blockData, &block)
+	require.NoError(t, err)
+
+	_, err = cbor.Decode(block[0], &header)
+	require.NoError(t, err)
+
+	_, err = cbor.Decode(header[0], &headerBody)
+	require.NoError(t, err)
+
+	encode := func(value interface{}) []byte {
+		data, err := cbor.Encode(value)
+		require.NoError(t, err)
+
+		return data
+	}
+
+	chain := make([]*testChainBlock, cnt)
+	prevHash := make([]byte, 32)
+
+	for i := range chain {
+		number, slot := uint64(i+1), uint64(i+1)*20
+
+		headerBody[0], headerBody[1], headerBody[2] = encode(number), encode(slot), encode(prevHash)
+		header[0] = encode(headerBody)
+		block[0] = encode(header)
+
+		ledgerHeader, err := ledger.NewBlockHeaderFromCbor(ledger.BlockTypeShelley, block[0])
+		require.NoError(t, err)
+
+		hash, err := hex.DecodeString(ledgerHeader.Hash())
+		require.NoError(t, err)
+
+		chain[i] = &testChainBlock{
+			slot:      slot,
+			number:    number,
+			hash:      hash,
+			blockType: ledger.BlockTypeShelley,
+			block:     encode(block),
+		}
+		prevHash = hash
+	}
+
+	return chain
+}
+
+// testChainNode is local stand-in of the cardano node which serves test chain over
+// node-to-node chain-sync and block-fetch. Messages of the node are delayed to simulate network latency
+type testChainNode struct {
+	chain    []*testChainBlock
+	latency  time.Duration
+	listener net.Listener
+}
+
+func startTestChainNode(t testing.TB, chain []*testChainBlock, latency time.Duration) *testChainNode {
+	t.Helper()
+
+	listener, err := net.Listen(ProtocolTCP, "127.0.0.1:0")
+	require.NoError(t, err)
+
+	node := &testChainNode{
+		chain:    chain,
+		latency:  latency,
+		listener: listener,
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go node.serve(newDelayedConn(conn, latency))
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	return node
+}
+
+func (n *testChainNode) Address() string {
+	return n.listener.Addr().String()
+}
+
+func (n *testChainNode) tip() chainsync.Tip {
+	last := n.chain[len(n.chain)-1]
+
+	return chainsync.Tip{Point: last.point(), BlockNumber: last.number}
+}
+
+func (n *testChainNode) find(point common.Point) int {
+	for i, x := range n.chain {
+		if x.slot == point.Slot && bytes.Equal(x.hash, point.Hash) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (n *testChainNode) serve(conn net.Conn) {
+	var (
+		// index of the next block sent to the client
+		cursor int
+		// real node rolls back to the intersection before the first block
+		intersection *common.Point
+	)
+
+	connection, err := ouroboros.NewConnection(
+		ouroboros.WithConnection(conn),
+		ouroboros.WithNetworkMagic(testChainNetworkMagic),
+		ouroboros.WithNodeToNode(true),
+		ouroboros.WithServer(true),
+		ouroboros.WithChainSyncConfig(chainsync.NewConfig(
+			chainsync.WithFindIntersectFunc(
+				func(_ chainsync.CallbackContext, points []common.Point) (common.Point, chainsync.Tip, error) {
+					for _, point := range points {
+						if point.Slot == 0 && len(point.Hash) == 0 {
+							cursor, intersection = 0, &point
+
+							return point, n.tip(), nil
+						}
+
+						if indx := n.find(point); indx != -1 {
+							cursor, intersection = indx+1, &point
+
+							return point, n.tip(), nil
+						}
+					}
+
+					return common.Point{}, n.tip(), chainsync.IntersectNotFoundError
+				}),
+			chainsync.WithRequestNextFunc(func(ctx chainsync.CallbackContext) error {
+				if intersection != nil {
+					point := *intersection
+					intersection = nil
+
+					return ctx.Server.RollBackward(point, n.tip())
+				}
+
+				if cursor >= len(n.chain) {
+					return nil // the end of the test chain, client keeps waiting
+				}
+
+				cursor++
+
+				// header is extracted from the block
+				return ctx.Server.RollForward(n.chain[cursor-1].blockType, n.chain[cursor-1].block, n.tip())
+			}),
+		)),
+		ouroboros.WithBlockFetchConfig(blockfetch.NewConfig(
+			blockfetch.WithRequestRangeFunc(func(ctx blockfetch.CallbackContext, start, end common.Point) error {
+				from, to := n.find(start), n.find(end)
+				if from == -1 || to < from {
+					return ctx.Server.NoBlocks()
+				}
+
+				if err := ctx.Server.StartBatch(); err != nil {
+					return err
+				}
+
+				for _, x := range n.chain[from : to+1] {
+					if err := ctx.Server.Block(x.blockType, x.block); err != nil {
+						return err
+					}
+				}
+
+				return ctx.Server.BatchDone()
+			}),
+		)),
+	)
+	if err != nil {
+		_ = conn.Close()
+
+		return
+	}
+
+	<-connection.ErrorChan() // wait until the client disconnects
+
+	_ = connection.Close()
+}
+
+// delayedConn delays every write, but it does not block the writer, so latency does not limit throughput
+type delayedConn struct {
+	net.Conn
+	delay   time.Duration
+	writeCh chan delayedWrite
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+type delayedWrite struct {
+	data []byte
+	at   time.Time
+}
+
+func newDelayedConn(conn net.Conn, delay time.Duration) *delayedConn {
+	dc := &delayedConn{
+		Conn:    conn,
+		delay:   delay,
+		writeCh: make(chan delayedWrite, 1024),
+		closeCh: make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-dc.closeCh:
+				return
+			case w := <-dc.writeCh:
+				time.Sleep(time.Until(w.at))
+
+				if _, err := dc.Conn.Write(w.data); err != nil {
+					_ = dc.Close()
+
+					return
+				}
+			}
+		}
+	}()
+
+	return dc
+}
+
+func (dc *delayedConn) Write(data []byte) (int, error) {
+	select {
+	case <-dc.closeCh:
+		return 0, net.ErrClosed
+	case dc.writeCh <- delayedWrite{data: bytes.Clone(data), at: time.Now().Add(dc.delay)}:
+		return len(data), nil
+	}
+}
+
+func (dc *delayedConn) Close() error {
+	dc.once.Do(func() {
+		close(dc.closeCh)
+	})
+
+	return dc.Conn.Close()
+}
@@ -0,0 +1,283 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+)
+
+// TxDepthNotification is sent when watched tx reaches one of the requested depths or when its block is rolled back
+type TxDepthNotification struct {
+	TxHash Hash `json:"txHash"`
+	// block which contains the tx
+	Block BlockPoint `json:"block"`
+	// requested depth which is reached. Depth is number of blocks on top of the tx block including the block itself
+	Depth uint64 `json:"depth"`
+	// block of the tx is rolled back. Depths are notified again if tx is included in another block
+	RolledBack bool `json:"rolledBack"`
+}
+
+// TxDepthHandler is called from the syncer routine so it should not block.
+// It is called after the watcher is unlocked, so it may call Watch or Unwatch
+type TxDepthHandler func(notification TxDepthNotification)
+
+type TentativeBlocksRetriever interface {
+	GetTentativeBlocks() []*TentativeBlock
+	KeepsTentativeTxs() bool
+}
+
+type ConfirmationWatcherDB interface {
+	GetLatestBlockPoint() (*BlockPoint, error)
+	GetLatestConfirmedBlocks(maxCnt int) ([]*CardanoBlock, error)
+}
+
+// ConfirmationWatcher notifies when watched txs reach requested depths, independently of ConfirmationBlockCount.
+// Txs are located in the tentative blocks of the indexer (KeepTentativeTxs must be set) and depth is counted
+// further on the confirmed chain. Tx which is already confirmed when watch starts is looked up in the latest
+// confirmed blocks of the database. Only txs whose hashes are kept in blocks (txs of interest or all txs
+// if KeepAllTxsHashesInBlock is set) can be tracked
+type ConfirmationWatcher struct {
+	source    TentativeBlocksRetriever
+	db        ConfirmationWatcherDB
+	watches   map[Hash]*txWatch
+	tipNumber uint64
+	lock      sync.Mutex
+}
+
+// txDepthCall is notification collected under the lock and sent after the lock is released
+type txDepthCall struct {
+	handler      TxDepthHandler
+	notification TxDepthNotification
+}
+
+type txWatch struct {
+	allDepths []uint64
+	// remaining depths ordered ascending
+	depths  []uint64
+	handler TxDepthHandler
+	// nil if tx is not yet included in a block
+	block     *BlockPoint
+	confirmed bool
+}
+
+func NewConfirmationWatcher(source TentativeBlocksRetriever, db ConfirmationWatcherDB) *ConfirmationWatcher {
+	return &ConfirmationWatcher{
+		source:  source,
+		db:      db,
+		watches: map[Hash]*txWatch{},
+	}
+}
+
+// Watch starts watching tx. Handler is called once for every depth. Existing watch of the tx is replaced.
+// Watch is removed when the greatest depth is reached. Confirmed tx is found only if its block is not deeper
+// than the greatest depth, otherwise it is watched as if it is not yet included in a block
+func (cw *ConfirmationWatcher) Watch(txHash Hash, depths []uint64, handler TxDepthHandler) error {
+	if !cw.source.KeepsTentativeTxs() {
+		return errors.New("keepTentativeTxs must be set to watch txs")
+	}
+
+	if len(depths) == 0 || slices.Contains(depths, 0) {
+		return errors.New("depths must be greater than zero")
+	}
+
+	depths = slices.Clone(depths)
+	slices.Sort(depths)
+	depths = slices.Compact(depths)
+
+	cw.lock.Lock()
+	calls, err := cw.addWatch(txHash, depths, handler)
+	cw.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	notifyTxDepths(calls)
+
+	return nil
+}
+
+// addWatch adds the watch and returns notifications of already reached depths. Lock must be held
+func (cw *ConfirmationWatcher) addWatch(txHash Hash, depths []uint64, handler TxDepthHandler) ([]txDepthCall, error) {
+	watch := &txWatch{
+		allDepths: depths,
+		depths:    depths,
+		handler:   handler,
+	}
+
+	tentativeBlocks := cw.source.GetTentativeBlocks()
+	if len(tentativeBlocks) > 0 {
+		cw.tipNumber = max(cw.tipNumber, tentativeBlocks[len(tentativeBlocks)-1].Block.Number)
+	}
+
+	if !slices.ContainsFunc(tentativeBlocks, func(tb *TentativeBlock) bool {
+		return slices.Contains(tb.Block.Txs, txHash)
+	}) {
+		block, err := cw.findConfirmedBlock(txHash, depths[len(depths)-1])
+		if err != nil {
+			return nil, err
+		}
+
+		if block != nil {
+			watch.block = block
+			watch.confirmed = true
+		}
+	}
+
+	cw.watches[txHash] = watch
+
+	return cw.update(tentativeBlocks, false), nil
+}
+
+func (cw *ConfirmationWatcher) Unwatch(txHash Hash) {
+	cw.lock.Lock()
+	defer cw.lock.Unlock()
+
+	delete(cw.watches, txHash)
+}
+
+// WrapHandler returns syncer handler which updates depths of the watched txs after the handler
+func (cw *ConfirmationWatcher) WrapHandler(handler BlockSyncerHandler) BlockSyncerHandler {
+	return &confirmationWatcherSyncerHandler{
+		BlockSyncerHandler: handler,
+		watcher:            cw,
+	}
+}
+
+// findConfirmedBlock returns confirmed block of the tx if it is one of the latest maxDepth confirmed blocks.
+// Tip number is updated from the latest block point because there may be no tentative blocks yet
+func (cw *ConfirmationWatcher) findConfirmedBlock(txHash Hash, maxDepth uint64) (*BlockPoint, error) {
+	latestPoint, err := cw.db.GetLatestBlockPoint()
+	if err != nil {
+		return nil, fmt.Errorf("could not get latest block point: %w", err)
+	}
+
+	if latestPoint == nil {
+		return nil, nil
+	}
+
+	cw.tipNumber = max(cw.tipNumber, latestPoint.BlockNumber)
+
+	blocks, err := cw.db.GetLatestConfirmedBlocks(int(maxDepth)) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("could not get latest confirmed blocks: %w", err)
+	}
+
+	for _, block := range blocks {
+		if slices.Contains(block.Txs, txHash) {
+			return &BlockPoint{BlockSlot: block.Slot, BlockHash: block.Hash, BlockNumber: block.Number}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// update locates watched txs in the tentative blocks and returns notifications of reached depths.
+// Tx which disappeared from the tentative blocks is confirmed on roll forward and rolled back on roll backward
+func (cw *ConfirmationWatcher) update(tentativeBlocks []*TentativeBlock, isRollBack bool) (calls []txDepthCall) {
+	if len(cw.watches) == 0 {
+		return nil
+	}
+
+	txBlocks := map[Hash]*CardanoBlock{}
+
+	for _, tb := range tentativeBlocks {
+		for _, txHash := range tb.Block.Txs {
+			txBlocks[txHash] = tb.Block
+		}
+	}
+
+	for txHash, watch := range cw.watches {
+		block, exists := txBlocks[txHash]
+
+		if watch.block != nil && !watch.confirmed {
+			switch {
+			case exists && block.Hash == watch.block.BlockHash:
+				// still unconfirmed
+			case !exists && !isRollBack:
+				watch.confirmed = true
+			default:
+				calls = append(calls, txDepthCall{
+					handler:      watch.handler,
+					notification: TxDepthNotification{TxHash: txHash, Block: *watch.block, RolledBack: true},
+				})
+				watch.block = nil
+				watch.depths = watch.allDepths
+			}
+		}
+
+		if watch.block == nil {
+			if !exists {
+				continue
+			}
+
+			watch.block = &BlockPoint{BlockSlot: block.Slot, BlockHash: block.Hash, BlockNumber: block.Number}
+		}
+
+		if isRollBack || cw.tipNumber < watch.block.BlockNumber {
+			continue
+		}
+
+		depth := cw.tipNumber - watch.block.BlockNumber + 1
+
+		for len(watch.depths) > 0 && watch.depths[0] <= depth {
+			calls = append(calls, txDepthCall{
+				handler:      watch.handler,
+				notification: TxDepthNotification{TxHash: txHash, Block: *watch.block, Depth: watch.depths[0]},
+			})
+			watch.depths = watch.depths[1:]
+		}
+
+		if len(watch.depths) == 0 {
+			delete(cw.watches, txHash)
+		}
+	}
+
+	return calls
+}
+
+func notifyTxDepths(calls []txDepthCall) {
+	for _, call := range calls {
+		call.handler(call.notification)
+	}
+}
+
+type confirmationWatcherSyncerHandler struct {
+	BlockSyncerHandler
+	watcher *ConfirmationWatcher
+}
+
+func (h *confirmationWatcherSyncerHandler) RollBackwardFunc(point common.Point) error {
+	if err := h.BlockSyncerHandler.RollBackwardFunc(point); err != nil {
+		return err
+	}
+
+	h.watcher.lock.Lock()
+	calls := h.watcher.update(h.watcher.source.GetTentativeBlocks(), true)
+	h.watcher.lock.Unlock()
+
+	notifyTxDepths(calls)
+
+	return nil
+}
+
+func (h *confirmationWatcherSyncerHandler) RollForwardFunc(
+	blockHeader ledger.BlockHeader, txsRetriever BlockTxsRetriever,
+) error {
+	if err := h.BlockSyncerHandler.RollForwardFunc(blockHeader, txsRetriever); err != nil {
+		return err
+	}
+
+	h.watcher.lock.Lock()
+	h.watcher.tipNumber = GetBlockNumber(blockHeader)
+	calls := h.watcher.update(h.watcher.source.GetTentativeBlocks(), false)
+	h.watcher.lock.Unlock()
+
+	notifyTxDepths(calls)
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+	"github.com/stretchr/testify/require"
+)
+
+type tentativeBlocksRetrieverStub struct {
+	blocks       []*TentativeBlock
+	notTentative bool
+}
+
+func (s *tentativeBlocksRetrieverStub) GetTentativeBlocks() []*TentativeBlock {
+	return s.blocks
+}
+
+func (s *tentativeBlocksRetrieverStub) KeepsTentativeTxs() bool {
+	return !s.notTentative
+}
+
+func TestConfirmationWatcher(t *testing.T) {
+	t.Parallel()
+
+	var notifications []TxDepthNotification
+
+	txHash := Hash{1}
+	blockA := &CardanoBlock{Slot: 100, Hash: Hash{10}, Number: 10, Txs: []Hash{txHash}}
+	blockB := &CardanoBlock{Slot: 110, Hash: Hash{11}, Number: 11}
+	blockC := &CardanoBlock{Slot: 111, Hash: Hash{12}, Number: 11, Txs: []Hash{{2}, txHash}}
+	source := &tentativeBlocksRetrieverStub{blocks: []*TentativeBlock{{Block: blockA}}}
+	watcher := NewConfirmationWatcher(source, &DatabaseMock{})
+	handler := watcher.WrapHandler(&BlockSyncerHandlerMock{})
+	rollForward := func(number uint64, blocks ...*CardanoBlock) {
+		source.blocks = nil
+		for _, block := range blocks {
+			source.blocks = append(source.blocks, &TentativeBlock{Block: block})
+		}
+
+		require.NoError(t, handler.RollForwardFunc(&LedgerBlockHeaderMock{BlockNumberVal: number}, nil))
+	}
+	pointOf := func(block *CardanoBlock) BlockPoint {
+		return BlockPoint{BlockSlot: block.Slot, BlockHash: block.Hash, BlockNumber: block.Number}
+	}
+
+	require.Error(t, watcher.Watch(txHash, nil, nil))
+	require.Error(t, watcher.Watch(txHash, []uint64{0, 1}, nil))
+	require.NoError(t, watcher.Watch(txHash, []uint64{5, 1, 3, 3}, func(notification TxDepthNotification) {
+		notifications = append(notifications, notification)
+	}))
+
+	// tx is already in the tip block
+	require.Equal(t, []TxDepthNotification{{TxHash: txHash, Block: pointOf(blockA), Depth: 1}}, notifications)
+
+	rollForward(11, blockA, blockB)
+	require.Len(t, notifications, 1)
+
+	// block of the tx is rolled back
+	source.blocks = nil
+
+	require.NoError(t, handler.RollBackwardFunc(common.Point{}))
+	require.Len(t, notifications, 2)
+	require.Equal(t, TxDepthNotification{TxHash: txHash, Block: pointOf(blockA), RolledBack: true}, notifications[1])
+
+	// tx is included in another block and depths are notified again
+	rollForward(11, blockC)
+	rollForward(13, blockC, blockB, blockB)
+	// block is confirmed and depth is counted on the confirmed chain
+	rollForward(14, blockB)
+	rollForward(15, blockB)
+
+	require.Equal(t, []TxDepthNotification{
+		{TxHash: txHash, Block: pointOf(blockC), Depth: 1},
+		{TxHash: txHash, Block: pointOf(blockC), Depth: 3},
+		{TxHash: txHash, Block: pointOf(blockC), Depth: 5},
+	}, notifications[2:])
+	require.Empty(t, watcher.watches)
+
+	watcher.Unwatch(txHash)
+}
+
+func TestConfirmationWatcher_ConfirmedTx(t *testing.T) {
+	t.Parallel()
+
+	var notifications []TxDepthNotification
+
+	txHash := Hash{1}
+	confirmedBlocks := []*CardanoBlock{
+		{Slot: 120, Hash: Hash{12}, Number: 12},
+		{Slot: 110, Hash: Hash{11}, Number: 11, Txs: []Hash{{2}, txHash}},
+		{Slot: 100, Hash: Hash{10}, Number: 10},
+	}
+	blockPoint := BlockPoint{BlockSlot: 110, BlockHash: Hash{11}, BlockNumber: 11}
+	source := &tentativeBlocksRetrieverStub{blocks: []*TentativeBlock{
+		{Block: &CardanoBlock{Slot: 130, Hash: Hash{13}, Number: 13}},
+	}}
+	dbMock := &DatabaseMock{}
+	watcher := NewConfirmationWatcher(source, dbMock)
+	handler := watcher.WrapHandler(&BlockSyncerHandlerMock{})
+	handlerFn := func(notification TxDepthNotification) {
+		notifications = append(notifications, notification)
+	}
+
+	dbMock.On("GetLatestBlockPoint").Return(&BlockPoint{BlockSlot: 120, BlockNumber: 12}, error(nil)).Once()
+	dbMock.On("GetLatestConfirmedBlocks", 4).Return(confirmedBlocks, error(nil)).Once()
+
+	// depth is computed from the tip, not from the latest confirmed block
+	require.NoError(t, watcher.Watch(txHash, []uint64{1, 3, 4}, handlerFn))
+	require.Equal(t, []TxDepthNotification{
+		{TxHash: txHash, Block: blockPoint, Depth: 1},
+		{TxHash: txHash, Block: blockPoint, Depth: 3},
+	}, notifications)
+
+	// confirmed tx is not rolled back when it is not in the tentative blocks
+	source.blocks = nil
+
+	require.NoError(t, handler.RollForwardFunc(&LedgerBlockHeaderMock{BlockNumberVal: 14}, nil))
+	require.Equal(t, TxDepthNotification{TxHash: txHash, Block: blockPoint, Depth: 4}, notifications[2])
+	require.Empty(t, watcher.watches)
+
+	dbMock.On("GetLatestBlockPoint").Return((*BlockPoint)(nil), errors.New("db error")).Once()
+
+	require.ErrorContains(t, watcher.Watch(Hash{2}, []uint64{1}, handlerFn), "db error")
+	require.Empty(t, watcher.watches)
+
+	source.notTentative = true
+
+	require.ErrorContains(t, watcher.Watch(txHash, []uint64{1}, handlerFn), "keepTentativeTxs")
+
+	dbMock.AssertExpectations(t)
+}
+
+func TestConfirmationWatcher_HandlerCallsWatcher(t *testing.T) {
+	t.Parallel()
+
+	var notifications []TxDepthNotification
+
+	block := &CardanoBlock{Slot: 100, Hash: Hash{10}, Number: 10, Txs: []Hash{{1}, {2}}}
+	source := &tentativeBlocksRetrieverStub{blocks: []*TentativeBlock{{Block: block}}}
+	watcher := NewConfirmationWatcher(source, &DatabaseMock{})
+	handler := watcher.WrapHandler(&BlockSyncerHandlerMock{})
+	handlerFn := func(notification TxDepthNotification) {
+		notifications = append(notifications, notification)
+	}
+
+	require.NoError(t, watcher.Watch(Hash{2}, []uint64{2}, handlerFn))
+
+	// handler is called without the lock, so it can change watches
+	require.NoError(t, watcher.Watch(Hash{1}, []uint64{1, 2}, func(notification TxDepthNotification) {
+		handlerFn(notification)
+		watcher.Unwatch(Hash{1})
+		watcher.Unwatch(Hash{2})
+	}))
+	require.NoError(t, handler.RollForwardFunc(&LedgerBlockHeaderMock{BlockNumberVal: 11}, nil))
+
+	require.Equal(t, []TxDepthNotification{
+		{TxHash: Hash{1}, Block: BlockPoint{BlockSlot: 100, BlockHash: Hash{10}, BlockNumber: 10}, Depth: 1},
+	}, notifications)
+	require.Empty(t, watcher.watches)
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blinklabs-io/gouroboros/ledger"
 	"github.com/blinklabs-io/gouroboros/protocol/common"
@@ -61,6 +62,25 @@ type Tx struct {
 	Outputs   []*TxOutput      `json:"out"`
 	Fee       uint64           `json:"fee"`
 	Valid     bool             `json:"valid"`
+
+	Certificates []*Certificate `json:"certs,omitempty"`
+	Withdrawals  []*Withdrawal  `json:"wdrls,omitempty"`
+	Mint         []MintAmount   `json:"mint,omitempty"`
+	Votes        []*Vote        `json:"votes,omitempty"`
+	Proposals    []*Proposal    `json:"proposals,omitempty"`
+
+	// collateral is consumed instead of inputs (and collateral return is created instead of outputs)
+	// when the tx is not valid
+	CollateralInputs []*TxInputOutput `json:"colInp,omitempty"`
+	CollateralReturn *TxOutput        `json:"colRet,omitempty"`
+	TotalCollateral  uint64           `json:"colTotal,omitempty"`
+
+	// inputs which are only referenced (not spent) by the tx
+	ReferenceInputs []*TxInputOutput `json:"refInp,omitempty"`
+	// populated only if BlockIndexerConfig.KeepTxWitnesses is set
+	WitnessSet *TxWitnessSet `json:"witnesses,omitempty"`
+	// unix time of the block (populated only if BlockIndexerConfig.SlotTime is set)
+	Timestamp int64 `json:"ts,omitempty"`
 }
 
 type TxInput struct {
@@ -82,6 +102,7 @@ type TxOutput struct {
 	DatumHash Hash          `json:"datumHsh,omitempty"`
 	IsUsed    bool          `json:"used"`
 	Tokens    []TokenAmount `json:"assets,omitempty"`
+	ScriptRef *Script       `json:"scriptRef,omitempty"`
 }
 
 type TxInputOutput struct {
@@ -96,19 +117,33 @@ type CardanoBlock struct {
 	EraID   uint8  `json:"era"`
 	EraName string `json:"-"`
 	Txs     []Hash `json:"txs"`
+	// byron epoch boundary block (EBB). EBBs are not stored in the database
+	EpochBoundary bool `json:"ebb,omitempty"`
+	// unix time of the block (populated only if BlockIndexerConfig.SlotTime is set)
+	Timestamp int64 `json:"ts,omitempty"`
 }
 
 func NewCardanoBlock(header ledger.BlockHeader, txs []Hash) *CardanoBlock {
 	return &CardanoBlock{
-		Slot:    header.SlotNumber(),
-		Hash:    NewHashFromHexString(header.Hash()),
-		Number:  header.BlockNumber(),
-		EraID:   header.Era().Id,
-		EraName: header.Era().Name,
-		Txs:     txs,
+		Slot:          header.SlotNumber(),
+		Hash:          NewHashFromHexString(header.Hash()),
+		Number:        GetBlockNumber(header),
+		EraID:         header.Era().Id,
+		EraName:       header.Era().Name,
+		Txs:           txs,
+		EpochBoundary: IsEpochBoundaryBlock(header),
 	}
 }
 
+// Time returns time of the block. Zero time is returned if timestamp is not known
+func (cb CardanoBlock) Time() time.Time {
+	if cb.Timestamp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(cb.Timestamp, 0).UTC()
+}
+
 func (cb CardanoBlock) Key() []byte {
 	return SlotNumberToKey(cb.Slot)
 }
@@ -155,6 +190,37 @@ func (tx Tx) Key() []byte {
 	return key
 }
 
+// Time returns time of the block which contains the tx. Zero time is returned if timestamp is not known
+func (tx Tx) Time() time.Time {
+	if tx.Timestamp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(tx.Timestamp, 0).UTC()
+}
+
+// ConsumedInputs returns inputs spent by the tx. Phase-2 invalid tx spends only its collateral inputs
+func (tx Tx) ConsumedInputs() []*TxInputOutput {
+	if !tx.Valid {
+		return tx.CollateralInputs
+	}
+
+	return tx.Inputs
+}
+
+// ProducedOutputs returns outputs created by the tx. Phase-2 invalid tx creates only the collateral return output
+func (tx Tx) ProducedOutputs() []*TxOutput {
+	if !tx.Valid {
+		if tx.CollateralReturn != nil {
+			return []*TxOutput{tx.CollateralReturn}
+		}
+
+		return nil
+	}
+
+	return tx.Outputs
+}
+
 func (tx Tx) String() string {
 	var (
 		sb    strings.Builder
@@ -188,12 +254,33 @@ func (tx Tx) String() string {
 	sb.WriteString(tx.BlockHash.String())
 	sb.WriteString("\nblock slot = ")
 	sb.WriteString(strconv.FormatUint(tx.BlockSlot, 10))
+
+	if tx.Timestamp != 0 {
+		sb.WriteString("\nblock time = ")
+		sb.WriteString(tx.Time().Format(time.RFC3339))
+	}
+
 	sb.WriteString("\nfee = ")
 	sb.WriteString(strconv.FormatUint(tx.Fee, 10))
 
 	if tx.Metadata != nil {
 		sb.WriteString("\nmeta = ")
-		sb.WriteString(string(tx.Metadata))
+
+		if metadataJSON, err := MetadataToJSON(tx.Metadata); err == nil {
+			sb.Write(metadataJSON)
+		} else {
+			sb.WriteString(hex.EncodeToString(tx.Metadata))
+		}
+	}
+
+	for i, x := range tx.Mint {
+		if i == 0 {
+			sb.WriteString("\nmint = ")
+		} else {
+			sb.WriteString(", ")
+		}
+
+		sb.WriteString(x.String())
 	}
 
 	sb.WriteString("\ninputs = ")
@@ -265,6 +352,11 @@ func (t TxOutput) String() string {
 		sb.WriteString(token.String())
 	}
 
+	if datumJSON, err := t.DatumJSON(); err == nil && datumJSON != nil {
+		sb.WriteString("+datum ")
+		sb.Write(datumJSON)
+	}
+
 	return sb.String()
 }
 
@@ -285,11 +377,20 @@ func (tt TokenAmount) String() string {
 }
 
 // LedgerAddressToString translates string representation of address to our wallet representation
-// this will handle vector and other specific cases
+// this will handle vector and other specific cases. Byron addresses are base58 encoded, all others are bech32
 func LedgerAddressToString(addr ledger.Address) string {
 	return addr.String()
 }
 
+// SortTxs sorts txs by slot and index in the block
+func SortTxs(txs []*Tx) []*Tx {
+	sort.Slice(txs, func(i, j int) bool {
+		return bytes.Compare(txs[i].Key(), txs[j].Key()) < 0
+	})
+
+	return txs
+}
+
 func SortTxInputOutputs(txInputsOutputs []*TxInputOutput) []*TxInputOutput {
 	sort.Slice(txInputsOutputs, func(i, j int) bool {
 		first, second := txInputsOutputs[i], txInputsOutputs[j]
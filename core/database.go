@@ -1,12 +1,28 @@
 package core
 
+import (
+	"errors"
+	"io"
+)
+
+var ErrReadOnlyDatabase = errors.New("database is opened in read-only mode")
+
 type DBTransactionWriter interface {
 	SetLatestBlockPoint(point *BlockPoint) DBTransactionWriter
 	AddTxOutputs(txOutputs []*TxInputOutput) DBTransactionWriter
 	AddConfirmedBlock(block *CardanoBlock) DBTransactionWriter
 	AddConfirmedTxs(txs []*Tx) DBTransactionWriter
+	DeleteConfirmedTxs(txs []*Tx) DBTransactionWriter
+	// MarkConfirmedTxsProcessed moves txs from unprocessed to processed txs
+	MarkConfirmedTxsProcessed(txs []*Tx) DBTransactionWriter
 	RemoveTxOutputs(txInputs []*TxInput, softDelete bool) DBTransactionWriter
 	DeleteAllTxOutputsPhysically() DBTransactionWriter
+	AddMintEvents(events []*MintEvent) DBTransactionWriter
+	AddGovProposals(events []*GovProposalEvent) DBTransactionWriter
+	AddGovVotes(events []*GovVoteEvent) DBTransactionWriter
+	SetSubscriber(subscriber *Subscriber) DBTransactionWriter
+	AddDeadLetterTxs(txs []*DeadLetterTx) DBTransactionWriter
+	DeleteDeadLetterTxs(txs []*DeadLetterTx) DBTransactionWriter
 	Execute() error
 }
 
@@ -14,20 +30,88 @@ type TxOutputRetriever interface {
 	GetTxOutput(txInput TxInput) (TxOutput, error)
 }
 
+type MintEventsRetriever interface {
+	// GetMintEvents returns all indexed mint and burn events of the policy ordered by slot and tx index.
+	// Only mints and burns of txs of interest are indexed
+	GetMintEvents(policyID string) ([]*MintEvent, error)
+}
+
+type GovernanceRetriever interface {
+	// GetGovProposal returns proposal of the governance action or nil if it does not exist
+	GetGovProposal(actionID GovActionID) (*GovProposalEvent, error)
+	// GetGovVotes returns all votes of the voter ordered by slot and tx index
+	GetGovVotes(voter Voter) ([]*GovVoteEvent, error)
+}
+
+type SlotRangeRetriever interface {
+	// GetConfirmedBlocksInSlotRange returns confirmed blocks with fromSlot <= slot <= toSlot ordered by slot
+	GetConfirmedBlocksInSlotRange(fromSlot uint64, toSlot uint64) ([]*CardanoBlock, error)
+	// GetConfirmedTxsInSlotRange returns both processed and unprocessed confirmed txs with
+	// fromSlot <= slot <= toSlot ordered by slot and index in the block
+	GetConfirmedTxsInSlotRange(fromSlot uint64, toSlot uint64) ([]*Tx, error)
+}
+
+type SubscribersRetriever interface {
+	// GetSubscribers returns all registered subscribers ordered by name
+	GetSubscribers() ([]*Subscriber, error)
+	// GetSubscriberUnprocessedConfirmedTxs returns at most maxCnt (all if maxCnt is not positive) confirmed txs
+	// after the subscriber cursor ordered by slot and index in the block. Both processed and unprocessed txs
+	// of the default queue are returned, so subscribers do not depend on MarkConfirmedTxsProcessed
+	GetSubscriberUnprocessedConfirmedTxs(name string, maxCnt int) ([]*Tx, error)
+}
+
 type BlockIndexerDB interface {
 	TxOutputRetriever
 	GetLatestBlockPoint() (*BlockPoint, error)
 	OpenTx() DBTransactionWriter
 }
 
-type Database interface {
-	BlockIndexerDB
-	Init(filepath string) error
+type PrunerDB interface {
+	GetLatestBlockPoint() (*BlockPoint, error)
+	// PruneConfirmedBlocks deletes at most maxCnt oldest confirmed blocks for which isExpired returns true.
+	// Blocks are ordered by slot so deletion stops at the first block which is not expired
+	PruneConfirmedBlocks(isExpired func(block *CardanoBlock) bool, maxCnt int) (int, error)
+	// PruneProcessedTxs deletes at most maxCnt oldest processed txs with slot lower than slotNumber
+	PruneProcessedTxs(slotNumber uint64, maxCnt int) (int, error)
+	GetSubscribers() ([]*Subscriber, error)
+}
+
+type DBIterator interface {
+	IterateTxOutputs(handler func(txInputOutput *TxInputOutput) error) error
+	IterateConfirmedBlocks(handler func(block *CardanoBlock) error) error
+	IterateConfirmedTxs(processed bool, handler func(tx *Tx) error) error
+	IterateMintEvents(handler func(event *MintEvent) error) error
+	IterateGovProposals(handler func(event *GovProposalEvent) error) error
+	IterateGovVotes(handler func(event *GovVoteEvent) error) error
+}
+
+type DatabaseReader interface {
+	TxOutputRetriever
+	MintEventsRetriever
+	SlotRangeRetriever
+	GovernanceRetriever
+	SubscribersRetriever
+	DeadLetterRetriever
+	DBIterator
 	Close() error
+	// Backup writes consistent snapshot of the whole database while database is still in use
+	Backup(w io.Writer) error
 
-	MarkConfirmedTxsProcessed(txs []*Tx) error
+	GetLatestBlockPoint() (*BlockPoint, error)
 	GetUnprocessedConfirmedTxs(maxCnt int) ([]*Tx, error)
 	GetLatestConfirmedBlocks(maxCnt int) ([]*CardanoBlock, error)
 	GetConfirmedBlocksFrom(slotNumber uint64, maxCnt int) ([]*CardanoBlock, error)
 	GetAllTxOutputs(address string, onlyNotUsed bool) ([]*TxInputOutput, error)
 }
+
+type Database interface {
+	DatabaseReader
+	BlockIndexerDB
+	PrunerDB
+	SubscriptionsDB
+	Init(filepath string) error
+	// InitReadOnly opens existing database for reading only. Every write returns ErrReadOnlyDatabase
+	InitReadOnly(filepath string) error
+
+	MarkConfirmedTxsProcessed(txs []*Tx) error
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeadLetterTx is a confirmed tx which the handler failed to process even after all retries.
+// Tx is nil for a failed block without txs of interest
+type DeadLetterTx struct {
+	Block    *CardanoBlock `json:"block,omitempty"`
+	Tx       *Tx           `json:"tx,omitempty"`
+	Error    string        `json:"err"`
+	Attempts int           `json:"attempts"`
+	// unix time of the last failure
+	FailedAt int64 `json:"failedAt"`
+}
+
+type DeadLetterRetriever interface {
+	// GetDeadLetterTxs returns at most maxCnt (all if maxCnt is not positive) dead-letter txs
+	// ordered by slot and index in the block
+	GetDeadLetterTxs(maxCnt int) ([]*DeadLetterTx, error)
+}
+
+type DeadLetterDB interface {
+	DeadLetterRetriever
+	OpenTx() DBTransactionWriter
+}
+
+// NewDeadLetterTxs returns one dead-letter entry for every tx of the failed block
+// or only one entry without tx if block has no txs
+func NewDeadLetterTxs(
+	block *CardanoBlock, txs []*Tx, err error, attempts int, failedAt time.Time,
+) []*DeadLetterTx {
+	if len(txs) == 0 {
+		return []*DeadLetterTx{{
+			Block:    block,
+			Error:    err.Error(),
+			Attempts: attempts,
+			FailedAt: failedAt.Unix(),
+		}}
+	}
+
+	result := make([]*DeadLetterTx, len(txs))
+
+	for i, tx := range txs {
+		result[i] = &DeadLetterTx{
+			Block:    block,
+			Tx:       tx,
+			Error:    err.Error(),
+			Attempts: attempts,
+			FailedAt: failedAt.Unix(),
+		}
+	}
+
+	return result
+}
+
+// Key of the entry without tx is the block key, which is ordered before keys of txs in the same slot
+func (dt DeadLetterTx) Key() []byte {
+	if dt.Tx == nil {
+		return dt.Block.Key()
+	}
+
+	return dt.Tx.Key()
+}
+
+// GetBlock returns block of the entry. Only slot and hash are known for entries stored without block
+func (dt DeadLetterTx) GetBlock() *CardanoBlock {
+	if dt.Block != nil {
+		return dt.Block
+	}
+
+	return &CardanoBlock{Slot: dt.Tx.BlockSlot, Hash: dt.Tx.BlockHash}
+}
+
+// ReplayDeadLetterTxs calls handler for at most maxCnt (all if maxCnt is not positive) dead-letter entries.
+// Handler is called once per block with all its dead-letter txs (none for a failed block without txs).
+// Successfully handled entries are removed from the dead-letter store, failed ones are kept with the latest error.
+// Returns number of successfully handled entries
+func ReplayDeadLetterTxs(db DeadLetterDB, handler NewConfirmedBlockHandler, maxCnt int) (int, error) {
+	deadLetterTxs, err := db.GetDeadLetterTxs(maxCnt)
+	if err != nil {
+		return 0, err
+	}
+
+	var handled, failed []*DeadLetterTx
+
+	for _, group := range groupDeadLetterTxsByBlock(deadLetterTxs) {
+		var txs []*Tx
+
+		for _, deadLetterTx := range group {
+			if deadLetterTx.Tx != nil {
+				txs = append(txs, deadLetterTx.Tx)
+			}
+		}
+
+		if err := handler(group[0].GetBlock(), txs); err != nil {
+			for _, deadLetterTx := range group {
+				deadLetterTx.Error = err.Error()
+				deadLetterTx.Attempts++
+				deadLetterTx.FailedAt = time.Now().Unix()
+			}
+
+			failed = append(failed, group...)
+		} else {
+			handled = append(handled, group...)
+		}
+	}
+
+	if err := db.OpenTx().DeleteDeadLetterTxs(handled).AddDeadLetterTxs(failed).Execute(); err != nil {
+		return 0, fmt.Errorf("could not update dead-letter txs: %w", err)
+	}
+
+	return len(handled), nil
+}
+
+// groupDeadLetterTxsByBlock splits entries ordered by key into groups of the same block
+func groupDeadLetterTxsByBlock(deadLetterTxs []*DeadLetterTx) (result [][]*DeadLetterTx) {
+	for _, deadLetterTx := range deadLetterTxs {
+		if cnt := len(result); cnt > 0 {
+			last := result[cnt-1][0].GetBlock()
+			if block := deadLetterTx.GetBlock(); last.Slot == block.Slot && last.Hash == block.Hash {
+				result[cnt-1] = append(result[cnt-1], deadLetterTx)
+
+				continue
+			}
+		}
+
+		result = append(result, []*DeadLetterTx{deadLetterTx})
+	}
+
+	return result
+}
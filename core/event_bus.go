@@ -0,0 +1,371 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+	"github.com/hashicorp/go-hclog"
+)
+
+type EventType string
+
+const (
+	EventBlockConfirmed EventType = "blockConfirmed"
+	EventTxConfirmed    EventType = "txConfirmed"
+	EventUtxoSpent      EventType = "utxoSpent"
+	EventTipUpdated     EventType = "tipUpdated"
+	EventRollBack       EventType = "rollBack"
+
+	// listener buffer is full: wait until listener reads events (confirmed events are never lost)
+	EventOverflowBlock = "block"
+	// listener buffer is full: drop the event which is being published
+	EventOverflowDropNewest = "dropNewest"
+	// listener buffer is full: drop the oldest event from the buffer
+	EventOverflowDropOldest = "dropOldest"
+
+	eventBusSubscriberNameDefault = "eventbus"
+	eventBusBufferSizeDefault     = 1024
+	eventBusPollIntervalDefault   = 5 * time.Second
+	eventBusBatchSizeDefault      = 100
+)
+
+var errEventBusClosed = errors.New("event bus is closed")
+
+// Event is a typed indexer notification. Only fields related to the event type are populated
+type Event struct {
+	Type EventType `json:"type"`
+	// confirmed block (BlockConfirmed)
+	Block *CardanoBlock `json:"block,omitempty"`
+	// confirmed tx (TxConfirmed, UtxoSpent)
+	Tx *Tx `json:"tx,omitempty"`
+	// input spent by the tx together with its output (UtxoSpent)
+	Utxo *TxInputOutput `json:"utxo,omitempty"`
+	// new tip (TipUpdated) or point to which chain is rolled back (RollBack)
+	Point *BlockPoint `json:"point,omitempty"`
+}
+
+type EventBusConfig struct {
+	// name of the subscriber whose persisted cursor tracks already published confirmed blocks
+	SubscriberName string `json:"subscriberName"`
+	// confirmed blocks are published from this slot when bus is started for the first time
+	FromSlot uint64 `json:"fromSlot"`
+	// size of the buffered channel of every listener
+	BufferSize int `json:"bufferSize"`
+	// what happens when listener buffer is full (block, dropNewest or dropOldest)
+	Overflow string `json:"overflow"`
+	// how often database is checked for new confirmed blocks if bus is not notified by the indexer
+	PollInterval time.Duration `json:"pollInterval"`
+	// maximum number of confirmed blocks read from the database at once
+	BatchSize int `json:"batchSize"`
+}
+
+type EventBusDB interface {
+	SubscriptionsDB
+	GetConfirmedBlocksFrom(slotNumber uint64, maxCnt int) ([]*CardanoBlock, error)
+	GetConfirmedTxsInSlotRange(fromSlot uint64, toSlot uint64) ([]*Tx, error)
+}
+
+// EventBus publishes indexer events to listeners through buffered channels.
+// BlockConfirmed, TxConfirmed and UtxoSpent events are read from the confirmed blocks and txs of the database
+// (not from the unprocessed txs queue, so blocks without txs are published too) after the persisted cursor
+// of the bus subscriber. Cursor is moved as soon as events of a batch are in the listener buffers, not when
+// listeners handled them. Events which were not yet published are published after restart, but buffered events
+// which were not handled before the process stopped are lost (at most once). Listener which needs at least once
+// delivery should use Subscription instead.
+// TipUpdated and RollBack events are published live from the syncer and are dropped if listener buffer is full,
+// so slow listener never stalls chain sync
+type EventBus struct {
+	config EventBusConfig
+	db     EventBusDB
+	logger hclog.Logger
+
+	listeners []chan Event
+	notifyCh  chan struct{}
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+	// publishes in progress. Listener channels are closed only after all of them are done
+	publishWg sync.WaitGroup
+	lock      sync.RWMutex
+	isClosed  bool
+}
+
+func NewEventBus(config EventBusConfig, db EventBusDB, logger hclog.Logger) *EventBus {
+	if config.SubscriberName == "" {
+		config.SubscriberName = eventBusSubscriberNameDefault
+	}
+
+	if config.BufferSize <= 0 {
+		config.BufferSize = eventBusBufferSizeDefault
+	}
+
+	if config.Overflow == "" {
+		config.Overflow = EventOverflowBlock
+	}
+
+	if config.PollInterval <= 0 {
+		config.PollInterval = eventBusPollIntervalDefault
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = eventBusBatchSizeDefault
+	}
+
+	return &EventBus{
+		config:   config,
+		db:       db,
+		logger:   logger,
+		notifyCh: make(chan struct{}, 1),
+		closeCh:  make(chan struct{}),
+	}
+}
+
+// Subscribe returns channel of a new listener. Channel is closed when bus is closed.
+// Listeners should subscribe before Start so they do not miss any event
+func (b *EventBus) Subscribe() <-chan Event {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	ch := make(chan Event, b.config.BufferSize)
+	if b.isClosed {
+		close(ch)
+	} else {
+		b.listeners = append(b.listeners, ch)
+	}
+
+	return ch
+}
+
+// Start publishes confirmed events in a separate routine until Close is called
+func (b *EventBus) Start() error {
+	switch b.config.Overflow {
+	case EventOverflowBlock, EventOverflowDropNewest, EventOverflowDropOldest:
+	default:
+		return fmt.Errorf("unknown event bus overflow: %s", b.config.Overflow)
+	}
+
+	subscriber, err := b.db.RegisterSubscriber(b.config.SubscriberName, b.config.FromSlot)
+	if err != nil {
+		return fmt.Errorf("could not register event bus subscriber: %w", err)
+	}
+
+	nextSlot := uint64(0)
+	if len(subscriber.Cursor) > 0 {
+		nextSlot = subscriber.CursorSlot() + 1
+	}
+
+	b.wg.Add(1)
+
+	go func() {
+		defer b.wg.Done()
+
+		for {
+			slot, err := b.publishConfirmed(nextSlot)
+			if err != nil && !errors.Is(err, errEventBusClosed) {
+				b.logger.Error("Error while publishing confirmed events", "slot", nextSlot, "err", err)
+			}
+
+			// read next batch immediately if there are more blocks
+			if slot > nextSlot && err == nil {
+				nextSlot = slot
+
+				continue
+			}
+
+			select {
+			case <-b.closeCh:
+				return
+			case <-b.notifyCh:
+			case <-time.After(b.config.PollInterval):
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (b *EventBus) Close() error {
+	// unblocks publish which waits for a slow listener
+	b.closeOnce.Do(func() {
+		close(b.closeCh)
+	})
+
+	b.wg.Wait()
+
+	b.lock.Lock()
+
+	if b.isClosed {
+		b.lock.Unlock()
+
+		return nil
+	}
+
+	b.isClosed = true
+	listeners := b.listeners
+	b.listeners = nil
+
+	b.lock.Unlock()
+
+	// no new publish can start, wait for the running ones before listener channels are closed
+	b.publishWg.Wait()
+
+	for _, ch := range listeners {
+		close(ch)
+	}
+
+	return nil
+}
+
+// NotifyConfirmedBlock wakes up the bus. It can be used as (non blocking) confirmed block handler of the indexer
+func (b *EventBus) NotifyConfirmedBlock(*CardanoBlock, []*Tx) error {
+	select {
+	case b.notifyCh <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// WrapHandler returns syncer handler which publishes TipUpdated and RollBack events of the handler
+func (b *EventBus) WrapHandler(handler BlockSyncerHandler) BlockSyncerHandler {
+	return &eventBusSyncerHandler{
+		BlockSyncerHandler: handler,
+		bus:                b,
+	}
+}
+
+// publishConfirmed publishes events of one batch of confirmed blocks starting from the slot,
+// moves the persisted cursor and returns slot from which the next batch starts
+func (b *EventBus) publishConfirmed(slot uint64) (uint64, error) {
+	blocks, err := b.db.GetConfirmedBlocksFrom(slot, b.config.BatchSize)
+	if err != nil || len(blocks) == 0 {
+		return slot, err
+	}
+
+	txs, err := b.db.GetConfirmedTxsInSlotRange(blocks[0].Slot, blocks[len(blocks)-1].Slot)
+	if err != nil {
+		return slot, err
+	}
+
+	for _, block := range blocks {
+		if !b.publish(Event{Type: EventBlockConfirmed, Block: block}, true) {
+			return slot, errEventBusClosed
+		}
+
+		for ; len(txs) > 0 && txs[0].BlockSlot <= block.Slot; txs = txs[1:] {
+			if txs[0].BlockSlot < block.Slot {
+				continue // block of the tx is already pruned
+			}
+
+			if !b.publish(Event{Type: EventTxConfirmed, Tx: txs[0]}, true) {
+				return slot, errEventBusClosed
+			}
+
+			// phase-2 invalid tx spends its collateral instead of inputs
+			for _, utxo := range txs[0].ConsumedInputs() {
+				if !b.publish(Event{Type: EventUtxoSpent, Tx: txs[0], Utxo: utxo}, true) {
+					return slot, errEventBusClosed
+				}
+			}
+		}
+	}
+
+	lastSlot := blocks[len(blocks)-1].Slot
+	// cursor is moved after all txs of the last block
+	err = b.db.MarkSubscriberConfirmedTxsProcessed(b.config.SubscriberName, []*Tx{
+		{BlockSlot: lastSlot, Indx: math.MaxUint32},
+	})
+	if err != nil {
+		return slot, err
+	}
+
+	return lastSlot + 1, nil
+}
+
+// publish sends event to all listeners. Returns false if bus is closed.
+// If canBlock is false, event is dropped for listener with full buffer even if overflow is block.
+// Events are sent without holding the lock, so slow listener does not block Subscribe
+func (b *EventBus) publish(event Event, canBlock bool) bool {
+	b.lock.RLock()
+
+	if b.isClosed {
+		b.lock.RUnlock()
+
+		return false
+	}
+
+	listeners := make([]chan Event, len(b.listeners))
+	copy(listeners, b.listeners)
+	b.publishWg.Add(1)
+
+	b.lock.RUnlock()
+
+	defer b.publishWg.Done()
+
+	for _, ch := range listeners {
+		switch {
+		case b.config.Overflow == EventOverflowBlock && canBlock:
+			select {
+			case ch <- event:
+			case <-b.closeCh:
+				return false
+			}
+		case b.config.Overflow == EventOverflowDropOldest:
+			for sent := false; !sent; {
+				select {
+				case ch <- event:
+					sent = true
+				default:
+					select {
+					case <-ch:
+					default:
+					}
+				}
+			}
+		default:
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+
+	return true
+}
+
+type eventBusSyncerHandler struct {
+	BlockSyncerHandler
+	bus *EventBus
+}
+
+func (h *eventBusSyncerHandler) RollBackwardFunc(point common.Point) error {
+	if err := h.BlockSyncerHandler.RollBackwardFunc(point); err != nil {
+		return err
+	}
+
+	h.bus.publish(Event{Type: EventRollBack, Point: &BlockPoint{
+		BlockSlot: point.Slot,
+		BlockHash: NewHashFromBytes(point.Hash),
+	}}, false)
+
+	return nil
+}
+
+func (h *eventBusSyncerHandler) RollForwardFunc(blockHeader ledger.BlockHeader, txsRetriever BlockTxsRetriever) error {
+	if err := h.BlockSyncerHandler.RollForwardFunc(blockHeader, txsRetriever); err != nil {
+		return err
+	}
+
+	h.bus.publish(Event{Type: EventTipUpdated, Point: &BlockPoint{
+		BlockSlot:   blockHeader.SlotNumber(),
+		BlockHash:   NewHashFromHexString(blockHeader.Hash()),
+		BlockNumber: GetBlockNumber(blockHeader),
+	}}, false)
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+type syncerHandlerStub struct {
+	err error
+}
+
+func (h *syncerHandlerStub) RollBackwardFunc(point common.Point) error {
+	return h.err
+}
+
+func (h *syncerHandlerStub) RollForwardFunc(blockHeader ledger.BlockHeader, txsRetriver BlockTxsRetriever) error {
+	return h.err
+}
+
+func (h *syncerHandlerStub) Reset() (BlockPoint, error) {
+	return BlockPoint{}, h.err
+}
+
+func readEvents(ch <-chan Event) (result []Event) {
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return result
+			}
+
+			result = append(result, event)
+		default:
+			return result
+		}
+	}
+}
+
+func TestEventBus_Overflow(t *testing.T) {
+	t.Parallel()
+
+	events := []Event{
+		{Type: EventBlockConfirmed, Block: &CardanoBlock{Slot: 1}},
+		{Type: EventBlockConfirmed, Block: &CardanoBlock{Slot: 2}},
+		{Type: EventBlockConfirmed, Block: &CardanoBlock{Slot: 3}},
+	}
+
+	for overflow, expected := range map[string][]Event{
+		EventOverflowDropNewest: events[:2],
+		EventOverflowDropOldest: events[1:],
+	} {
+		bus := NewEventBus(EventBusConfig{BufferSize: 2, Overflow: overflow}, &DatabaseMock{}, hclog.NewNullLogger())
+		ch := bus.Subscribe()
+
+		for _, event := range events {
+			require.True(t, bus.publish(event, true))
+		}
+
+		require.Equal(t, expected, readEvents(ch), overflow)
+		require.NoError(t, bus.Close())
+		require.False(t, bus.publish(events[0], true))
+
+		_, ok := <-ch
+		require.False(t, ok)
+	}
+
+	// live events never block
+	bus := NewEventBus(EventBusConfig{BufferSize: 1}, &DatabaseMock{}, hclog.NewNullLogger())
+	ch := bus.Subscribe()
+
+	require.True(t, bus.publish(events[0], false))
+	require.True(t, bus.publish(events[1], false))
+	require.Equal(t, events[:1], readEvents(ch))
+
+	// unknown overflow
+	bus = NewEventBus(EventBusConfig{Overflow: "unknown"}, &DatabaseMock{}, hclog.NewNullLogger())
+	require.ErrorContains(t, bus.Start(), "unknown event bus overflow")
+}
+
+func TestEventBus_SlowListener(t *testing.T) {
+	t.Parallel()
+
+	event := Event{Type: EventBlockConfirmed, Block: &CardanoBlock{Slot: 1}}
+	bus := NewEventBus(EventBusConfig{BufferSize: 1}, &DatabaseMock{}, hclog.NewNullLogger())
+	ch := bus.Subscribe()
+
+	require.True(t, bus.publish(event, true))
+
+	publishedCh := make(chan bool)
+
+	go func() {
+		publishedCh <- bus.publish(event, true) // waits for the slow listener
+	}()
+
+	// listener can subscribe while publish waits
+	other := bus.Subscribe()
+
+	// close unblocks waiting publish before listener channels are closed
+	require.NoError(t, bus.Close())
+	require.False(t, <-publishedCh)
+	require.Equal(t, []Event{event}, readEvents(ch))
+	require.Empty(t, readEvents(other))
+}
+
+func TestEventBus_WrapHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := &syncerHandlerStub{}
+	bus := NewEventBus(EventBusConfig{}, &DatabaseMock{}, hclog.NewNullLogger())
+	ch := bus.Subscribe()
+	wrapped := bus.WrapHandler(handler)
+
+	require.NoError(t, wrapped.RollForwardFunc(&LedgerBlockHeaderMock{
+		SlotNumberVal: 10, BlockNumberVal: 5, HashVal: bytes2HashString([]byte{1}),
+	}, nil))
+	require.NoError(t, wrapped.RollBackwardFunc(common.Point{Slot: 8, Hash: []byte{2}}))
+
+	handler.err = errors.New("handler failed")
+
+	require.ErrorIs(t, wrapped.RollBackwardFunc(common.NewPointOrigin()), handler.err)
+
+	require.Equal(t, []Event{
+		{Type: EventTipUpdated, Point: &BlockPoint{BlockSlot: 10, BlockNumber: 5, BlockHash: NewHashFromBytes([]byte{1})}},
+		{Type: EventRollBack, Point: &BlockPoint{BlockSlot: 8, BlockHash: NewHashFromBytes([]byte{2})}},
+	}, readEvents(ch))
+	require.NoError(t, bus.Close())
+}
+
+func TestEventBus_PublishConfirmed(t *testing.T) {
+	t.Parallel()
+
+	utxos := []*TxInputOutput{
+		{Input: TxInput{Hash: Hash{1}}, Output: TxOutput{Address: addresses[0]}},
+		{Input: TxInput{Hash: Hash{2}}, Output: TxOutput{Address: addresses[1]}},
+	}
+	blocks := []*CardanoBlock{{Slot: 10, Hash: Hash{10}, Txs: []Hash{{11}, {12}}}}
+	txs := []*Tx{
+		{BlockSlot: 10, Hash: Hash{11}, Valid: true, Inputs: utxos[:1]},
+		// invalid tx spends only its collateral
+		{BlockSlot: 10, Indx: 1, Hash: Hash{12}, Inputs: utxos[:1], CollateralInputs: utxos[1:]},
+	}
+	dbMock := &DatabaseMock{}
+	bus := NewEventBus(EventBusConfig{}, dbMock, hclog.NewNullLogger())
+	ch := bus.Subscribe()
+
+	dbMock.On("GetConfirmedBlocksFrom", uint64(5), eventBusBatchSizeDefault).Return(blocks, error(nil)).Once()
+	dbMock.On("GetConfirmedTxsInSlotRange", uint64(10), uint64(10)).Return(txs, error(nil)).Once()
+	dbMock.On("MarkSubscriberConfirmedTxsProcessed", eventBusSubscriberNameDefault,
+		[]*Tx{{BlockSlot: 10, Indx: math.MaxUint32}}).Return(error(nil)).Once()
+
+	nextSlot, err := bus.publishConfirmed(5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), nextSlot)
+	require.Equal(t, []Event{
+		{Type: EventBlockConfirmed, Block: blocks[0]},
+		{Type: EventTxConfirmed, Tx: txs[0]},
+		{Type: EventUtxoSpent, Tx: txs[0], Utxo: utxos[0]},
+		{Type: EventTxConfirmed, Tx: txs[1]},
+		{Type: EventUtxoSpent, Tx: txs[1], Utxo: utxos[1]},
+	}, readEvents(ch))
+
+	require.NoError(t, bus.Close())
+	dbMock.AssertExpectations(t)
+}
@@ -0,0 +1,317 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	ExportVersion = 1
+
+	ExportItemHeader        = "header"
+	ExportItemTxOutput      = "utxo"
+	ExportItemBlock         = "block"
+	ExportItemUnprocessedTx = "tx"
+	ExportItemProcessedTx   = "processedTx"
+	ExportItemLatestPoint   = "point"
+	ExportItemMintEvent     = "mint"
+	ExportItemGovProposal   = "govProposal"
+	ExportItemGovVote       = "govVote"
+	ExportItemSubscriber    = "subscriber"
+	ExportItemDeadLetterTx  = "deadLetterTx"
+
+	importBatchSizeDefault = 1000
+	importMaxLineSize      = 64 * 1024 * 1024
+)
+
+// ExportItem is one line of the JSON Lines export. The first line is always the header
+type ExportItem struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type ExportHeader struct {
+	Version int `json:"version"`
+}
+
+// ExportJSONLines streams all indexed data from the database as versioned JSON Lines.
+// Database should not be written to during the export, otherwise buckets could be mutually inconsistent
+func ExportJSONLines(db DatabaseReader, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+
+	writeItem := func(itemType string, data interface{}) error {
+		bytes, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("could not marshal %s: %w", itemType, err)
+		}
+
+		return encoder.Encode(ExportItem{
+			Type: itemType,
+			Data: bytes,
+		})
+	}
+
+	if err := writeItem(ExportItemHeader, ExportHeader{Version: ExportVersion}); err != nil {
+		return err
+	}
+
+	latestPoint, err := db.GetLatestBlockPoint()
+	if err != nil {
+		return err
+	}
+
+	if latestPoint != nil {
+		if err := writeItem(ExportItemLatestPoint, latestPoint); err != nil {
+			return err
+		}
+	}
+
+	if err := db.IterateTxOutputs(func(txInputOutput *TxInputOutput) error {
+		return writeItem(ExportItemTxOutput, txInputOutput)
+	}); err != nil {
+		return err
+	}
+
+	if err := db.IterateConfirmedBlocks(func(block *CardanoBlock) error {
+		return writeItem(ExportItemBlock, block)
+	}); err != nil {
+		return err
+	}
+
+	if err := db.IterateConfirmedTxs(false, func(tx *Tx) error {
+		return writeItem(ExportItemUnprocessedTx, tx)
+	}); err != nil {
+		return err
+	}
+
+	if err := db.IterateConfirmedTxs(true, func(tx *Tx) error {
+		return writeItem(ExportItemProcessedTx, tx)
+	}); err != nil {
+		return err
+	}
+
+	if err := db.IterateMintEvents(func(event *MintEvent) error {
+		return writeItem(ExportItemMintEvent, event)
+	}); err != nil {
+		return err
+	}
+
+	if err := db.IterateGovProposals(func(event *GovProposalEvent) error {
+		return writeItem(ExportItemGovProposal, event)
+	}); err != nil {
+		return err
+	}
+
+	if err := db.IterateGovVotes(func(event *GovVoteEvent) error {
+		return writeItem(ExportItemGovVote, event)
+	}); err != nil {
+		return err
+	}
+
+	subscribers, err := db.GetSubscribers()
+	if err != nil {
+		return err
+	}
+
+	for _, subscriber := range subscribers {
+		if err := writeItem(ExportItemSubscriber, subscriber); err != nil {
+			return err
+		}
+	}
+
+	deadLetterTxs, err := db.GetDeadLetterTxs(0)
+	if err != nil {
+		return err
+	}
+
+	for _, deadLetterTx := range deadLetterTxs {
+		if err := writeItem(ExportItemDeadLetterTx, deadLetterTx); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ImportJSONLines writes data created by ExportJSONLines into the database.
+// Data is written in batches of batchSize items (default is used if batchSize is not positive)
+func ImportJSONLines(db Database, r io.Reader, batchSize int) error {
+	if batchSize <= 0 {
+		batchSize = importBatchSizeDefault
+	}
+
+	var (
+		dbTx         = db.OpenTx()
+		processedTxs []*Tx
+		cnt          int
+		lineNum      int
+	)
+
+	flush := func() error {
+		if err := dbTx.Execute(); err != nil {
+			return err
+		}
+
+		if len(processedTxs) > 0 {
+			if err := db.MarkConfirmedTxsProcessed(processedTxs); err != nil {
+				return err
+			}
+		}
+
+		processedTxs, cnt = nil, 0
+
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, importMaxLineSize)
+
+	for scanner.Scan() {
+		var item ExportItem
+
+		lineNum++
+
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		if lineNum == 1 {
+			if err := checkExportHeader(item); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := importItem(dbTx, &processedTxs, item); err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		if cnt++; cnt == batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if lineNum == 0 {
+		return errors.New("export header is missing")
+	}
+
+	return flush()
+}
+
+func checkExportHeader(item ExportItem) error {
+	var header ExportHeader
+
+	if item.Type != ExportItemHeader {
+		return errors.New("export header is missing")
+	}
+
+	if err := json.Unmarshal(item.Data, &header); err != nil {
+		return fmt.Errorf("could not unmarshal export header: %w", err)
+	}
+
+	if header.Version != ExportVersion {
+		return fmt.Errorf("unsupported export version: %d", header.Version)
+	}
+
+	return nil
+}
+
+func importItem(dbTx DBTransactionWriter, processedTxs *[]*Tx, item ExportItem) error {
+	switch item.Type {
+	case ExportItemLatestPoint:
+		var point *BlockPoint
+
+		if err := json.Unmarshal(item.Data, &point); err != nil {
+			return err
+		}
+
+		dbTx.SetLatestBlockPoint(point)
+	case ExportItemTxOutput:
+		var txInputOutput *TxInputOutput
+
+		if err := json.Unmarshal(item.Data, &txInputOutput); err != nil {
+			return err
+		}
+
+		dbTx.AddTxOutputs([]*TxInputOutput{txInputOutput})
+	case ExportItemBlock:
+		var block *CardanoBlock
+
+		if err := json.Unmarshal(item.Data, &block); err != nil {
+			return err
+		}
+
+		dbTx.AddConfirmedBlock(block)
+	case ExportItemUnprocessedTx:
+		var tx *Tx
+
+		if err := json.Unmarshal(item.Data, &tx); err != nil {
+			return err
+		}
+
+		dbTx.AddConfirmedTxs([]*Tx{tx})
+	case ExportItemProcessedTx:
+		var tx *Tx
+
+		if err := json.Unmarshal(item.Data, &tx); err != nil {
+			return err
+		}
+
+		*processedTxs = append(*processedTxs, tx)
+	case ExportItemMintEvent:
+		var event *MintEvent
+
+		if err := json.Unmarshal(item.Data, &event); err != nil {
+			return err
+		}
+
+		dbTx.AddMintEvents([]*MintEvent{event})
+	case ExportItemGovProposal:
+		var event *GovProposalEvent
+
+		if err := json.Unmarshal(item.Data, &event); err != nil {
+			return err
+		}
+
+		dbTx.AddGovProposals([]*GovProposalEvent{event})
+	case ExportItemGovVote:
+		var event *GovVoteEvent
+
+		if err := json.Unmarshal(item.Data, &event); err != nil {
+			return err
+		}
+
+		dbTx.AddGovVotes([]*GovVoteEvent{event})
+	case ExportItemSubscriber:
+		var subscriber *Subscriber
+
+		if err := json.Unmarshal(item.Data, &subscriber); err != nil {
+			return err
+		}
+
+		dbTx.SetSubscriber(subscriber)
+	case ExportItemDeadLetterTx:
+		var deadLetterTx *DeadLetterTx
+
+		if err := json.Unmarshal(item.Data, &deadLetterTx); err != nil {
+			return err
+		}
+
+		dbTx.AddDeadLetterTxs([]*DeadLetterTx{deadLetterTx})
+	default:
+		return fmt.Errorf("unknown export item type: %s", item.Type)
+	}
+
+	return nil
+}
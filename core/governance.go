@@ -0,0 +1,352 @@
+package core
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+const (
+	// CredentialHashSize is size of the key or script hash of committee, drep and pool credentials
+	CredentialHashSize = 28
+
+	govActionIDKeySize = HashSize + 4
+)
+
+type VoterType string
+
+const (
+	VoterTypeCommitteeKeyHash    VoterType = "committeeKeyHash"
+	VoterTypeCommitteeScriptHash VoterType = "committeeScriptHash"
+	VoterTypeDRepKeyHash         VoterType = "drepKeyHash"
+	VoterTypeDRepScriptHash      VoterType = "drepScriptHash"
+	VoterTypePoolKeyHash         VoterType = "poolKeyHash"
+)
+
+// voterTypes are ordered by the ledger voter type
+var voterTypes = []VoterType{
+	VoterTypeCommitteeKeyHash, VoterTypeCommitteeScriptHash,
+	VoterTypeDRepKeyHash, VoterTypeDRepScriptHash, VoterTypePoolKeyHash,
+}
+
+type VoteKind string
+
+const (
+	VoteNo      VoteKind = "no"
+	VoteYes     VoteKind = "yes"
+	VoteAbstain VoteKind = "abstain"
+)
+
+var voteKinds = []VoteKind{VoteNo, VoteYes, VoteAbstain}
+
+type GovActionType string
+
+const (
+	GovActionParameterChange    GovActionType = "parameterChange"
+	GovActionHardForkInitiation GovActionType = "hardForkInitiation"
+	GovActionTreasuryWithdrawal GovActionType = "treasuryWithdrawal"
+	GovActionNoConfidence       GovActionType = "noConfidence"
+	GovActionUpdateCommittee    GovActionType = "updateCommittee"
+	GovActionNewConstitution    GovActionType = "newConstitution"
+	GovActionInfo               GovActionType = "info"
+)
+
+var govActionTypes = []GovActionType{
+	GovActionParameterChange, GovActionHardForkInitiation, GovActionTreasuryWithdrawal, GovActionNoConfidence,
+	GovActionUpdateCommittee, GovActionNewConstitution, GovActionInfo,
+}
+
+type Voter struct {
+	Type VoterType `json:"type"`
+	// hex hash of the committee hot credential, drep credential or pool key
+	Hash string `json:"hash"`
+}
+
+// GovActionID identifies governance action by the tx which proposed it and index of the proposal inside the tx
+type GovActionID struct {
+	TxHash Hash   `json:"tx"`
+	Index  uint32 `json:"ind"`
+}
+
+type Anchor struct {
+	URL      string `json:"url"`
+	DataHash string `json:"hash"`
+}
+
+type Vote struct {
+	Voter    Voter       `json:"voter"`
+	ActionID GovActionID `json:"action"`
+	Vote     VoteKind    `json:"vote"`
+	Anchor   *Anchor     `json:"anchor,omitempty"`
+}
+
+type TreasuryWithdrawal struct {
+	RewardAddress string `json:"addr"`
+	Amount        uint64 `json:"amnt"`
+}
+
+type Proposal struct {
+	ActionID      GovActionID   `json:"action"`
+	Type          GovActionType `json:"type"`
+	Deposit       uint64        `json:"deposit"`
+	RewardAccount string        `json:"reward"`
+	Anchor        Anchor        `json:"anchor"`
+	// previous action of the same purpose (not present for treasury withdrawals and info actions)
+	PrevActionID *GovActionID          `json:"prev,omitempty"`
+	Withdrawals  []*TreasuryWithdrawal `json:"wdrls,omitempty"`
+}
+
+// GovProposalEvent is proposal of a confirmed tx. Events are indexed by governance action id
+type GovProposalEvent struct {
+	Proposal  *Proposal `json:"proposal"`
+	BlockSlot uint64    `json:"slot"`
+	TxIndx    uint32    `json:"ind"`
+}
+
+// GovVoteEvent is vote of a confirmed tx. Events are indexed by voter
+type GovVoteEvent struct {
+	Vote      *Vote  `json:"vote"`
+	BlockSlot uint64 `json:"slot"`
+	TxIndx    uint32 `json:"ind"`
+	TxHash    Hash   `json:"hash"`
+}
+
+// NewVotes converts ledger voting procedures. Result is sorted by voter and governance action id
+func NewVotes(procedures ledgerCommon.VotingProcedures) []*Vote {
+	var result []*Vote
+
+	for voter, votes := range procedures {
+		for actionID, procedure := range votes {
+			vote := &Vote{
+				Voter: Voter{
+					Type: getEnumValue(voterTypes, voter.Type, VoterType("unknown")),
+					Hash: hex.EncodeToString(voter.Hash[:]),
+				},
+				ActionID: newGovActionID(actionID),
+				Vote:     getEnumValue(voteKinds, procedure.Vote, VoteKind("unknown")),
+			}
+
+			if procedure.Anchor != nil {
+				vote.Anchor = newAnchor(*procedure.Anchor)
+			}
+
+			result = append(result, vote)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Voter.Type != result[j].Voter.Type {
+			return result[i].Voter.Type < result[j].Voter.Type
+		}
+
+		if result[i].Voter.Hash != result[j].Voter.Hash {
+			return result[i].Voter.Hash < result[j].Voter.Hash
+		}
+
+		return result[i].ActionID.String() < result[j].ActionID.String()
+	})
+
+	return result
+}
+
+// NewProposals converts ledger proposal procedures of the tx
+func NewProposals(txHash Hash, procedures []ledgerCommon.ProposalProcedure) []*Proposal {
+	if len(procedures) == 0 {
+		return nil
+	}
+
+	result := make([]*Proposal, len(procedures))
+
+	for i, procedure := range procedures {
+		proposal := &Proposal{
+			ActionID:      GovActionID{TxHash: txHash, Index: uint32(i)}, //nolint:gosec
+			Type:          getEnumValue(govActionTypes, procedure.GovAction.Type, GovActionType("unknown")),
+			Deposit:       procedure.Deposit,
+			RewardAccount: LedgerAddressToString(procedure.RewardAccount),
+			Anchor:        *newAnchor(procedure.Anchor),
+		}
+
+		var prevActionID *ledgerCommon.GovActionId
+
+		switch action := procedure.GovAction.Action.(type) {
+		case *ledgerCommon.ParameterChangeGovAction:
+			prevActionID = action.ActionId
+		case *ledgerCommon.HardForkInitiationGovAction:
+			prevActionID = action.ActionId
+		case *ledgerCommon.NoConfidenceGovAction:
+			prevActionID = action.ActionId
+		case *ledgerCommon.UpdateCommitteeGovAction:
+			prevActionID = action.ActionId
+		case *ledgerCommon.NewConstitutionGovAction:
+			prevActionID = action.ActionId
+		case *ledgerCommon.TreasuryWithdrawalGovAction:
+			proposal.Withdrawals = newTreasuryWithdrawals(action.Withdrawals)
+		}
+
+		if prevActionID != nil {
+			id := newGovActionID(prevActionID)
+			proposal.PrevActionID = &id
+		}
+
+		result[i] = proposal
+	}
+
+	return result
+}
+
+// NewGovEvents creates proposal and vote events of valid txs
+func NewGovEvents(txs []*Tx) (proposals []*GovProposalEvent, votes []*GovVoteEvent) {
+	for _, tx := range txs {
+		if !tx.Valid {
+			continue
+		}
+
+		for _, proposal := range tx.Proposals {
+			proposals = append(proposals, &GovProposalEvent{
+				Proposal:  proposal,
+				BlockSlot: tx.BlockSlot,
+				TxIndx:    tx.Indx,
+			})
+		}
+
+		for _, vote := range tx.Votes {
+			votes = append(votes, &GovVoteEvent{
+				Vote:      vote,
+				BlockSlot: tx.BlockSlot,
+				TxIndx:    tx.Indx,
+				TxHash:    tx.Hash,
+			})
+		}
+	}
+
+	return proposals, votes
+}
+
+// ParseGovActionID parses governance action id in the tx_hash#index format
+func ParseGovActionID(str string) (GovActionID, error) {
+	hash, indexStr, found := strings.Cut(str, "#")
+	if !found {
+		return GovActionID{}, fmt.Errorf("invalid governance action id: %s", str)
+	}
+
+	hashBytes, err := hex.DecodeString(strings.TrimPrefix(hash, "0x"))
+	if err != nil || len(hashBytes) != HashSize {
+		return GovActionID{}, fmt.Errorf("invalid governance action id hash: %s", hash)
+	}
+
+	index, err := strconv.ParseUint(indexStr, 10, 32)
+	if err != nil {
+		return GovActionID{}, fmt.Errorf("invalid governance action id index: %w", err)
+	}
+
+	return GovActionID{
+		TxHash: Hash(hashBytes),
+		Index:  uint32(index),
+	}, nil
+}
+
+// VoterToKey returns the key prefix of all vote events of the voter
+func VoterToKey(voter Voter) ([]byte, error) {
+	typeIndx := -1
+
+	for i, x := range voterTypes {
+		if x == voter.Type {
+			typeIndx = i
+
+			break
+		}
+	}
+
+	if typeIndx == -1 {
+		return nil, fmt.Errorf("invalid voter type: %s", voter.Type)
+	}
+
+	hash, err := hex.DecodeString(strings.TrimPrefix(voter.Hash, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid voter hash %s: %w", voter.Hash, err)
+	}
+
+	if len(hash) != CredentialHashSize {
+		return nil, fmt.Errorf("invalid voter hash size: %d", len(hash))
+	}
+
+	return append([]byte{byte(typeIndx)}, hash...), nil
+}
+
+func (id GovActionID) String() string {
+	return fmt.Sprintf("%s#%d", id.TxHash, id.Index)
+}
+
+func (id GovActionID) Key() []byte {
+	key := make([]byte, govActionIDKeySize)
+
+	copy(key, id.TxHash[:])
+	binary.BigEndian.PutUint32(key[HashSize:], id.Index)
+
+	return key
+}
+
+func (pe GovProposalEvent) Key() []byte {
+	return pe.Proposal.ActionID.Key()
+}
+
+// Key is voter followed by slot, tx index and governance action id, so votes of the voter are ordered by time
+func (ve GovVoteEvent) Key() ([]byte, error) {
+	voterKey, err := VoterToKey(ve.Vote.Voter)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, len(voterKey)+8+4+govActionIDKeySize)
+
+	copy(key, voterKey)
+	binary.BigEndian.PutUint64(key[len(voterKey):], ve.BlockSlot)
+	binary.BigEndian.PutUint32(key[len(voterKey)+8:], ve.TxIndx)
+	copy(key[len(voterKey)+12:], ve.Vote.ActionID.Key())
+
+	return key, nil
+}
+
+func newGovActionID(id *ledgerCommon.GovActionId) GovActionID {
+	return GovActionID{
+		TxHash: Hash(id.TransactionId),
+		Index:  id.GovActionIdx,
+	}
+}
+
+func newAnchor(anchor ledgerCommon.GovAnchor) *Anchor {
+	return &Anchor{
+		URL:      anchor.Url,
+		DataHash: hex.EncodeToString(anchor.DataHash[:]),
+	}
+}
+
+func newTreasuryWithdrawals(withdrawals map[*ledgerCommon.Address]uint64) []*TreasuryWithdrawal {
+	result := make([]*TreasuryWithdrawal, 0, len(withdrawals))
+
+	for addr, amount := range withdrawals {
+		result = append(result, &TreasuryWithdrawal{
+			RewardAddress: LedgerAddressToString(*addr),
+			Amount:        amount,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].RewardAddress < result[j].RewardAddress
+	})
+
+	return result
+}
+
+func getEnumValue[T any, I uint8 | uint](values []T, indx I, defaultValue T) T {
+	if int(indx) < len(values) {
+		return values[indx]
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,156 @@
+package core
+
+import (
+	"encoding/hex"
+	"testing"
+
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVotes(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewVotes(nil))
+
+	drepHash, poolHash := [28]byte{1}, [28]byte{2}
+	actionIDs := []*ledgerCommon.GovActionId{
+		{TransactionId: [32]byte{5}, GovActionIdx: 1},
+		{TransactionId: [32]byte{5}, GovActionIdx: 0},
+	}
+
+	votes := NewVotes(ledgerCommon.VotingProcedures{
+		{Type: ledgerCommon.VoterTypeStakingPoolKeyHash, Hash: poolHash}: {
+			actionIDs[0]: {Vote: ledgerCommon.GovVoteAbstain},
+		},
+		{Type: ledgerCommon.VoterTypeDRepKeyHash, Hash: drepHash}: {
+			actionIDs[0]: {Vote: ledgerCommon.GovVoteNo},
+			actionIDs[1]: {
+				Vote:   ledgerCommon.GovVoteYes,
+				Anchor: &ledgerCommon.GovAnchor{Url: "https://vote", DataHash: [32]byte{9}},
+			},
+		},
+	})
+
+	drep := Voter{Type: VoterTypeDRepKeyHash, Hash: hex.EncodeToString(drepHash[:])}
+	require.Equal(t, []*Vote{
+		{
+			Voter:    drep,
+			ActionID: GovActionID{TxHash: Hash{5}, Index: 0},
+			Vote:     VoteYes,
+			Anchor:   &Anchor{URL: "https://vote", DataHash: Hash{9}.String()},
+		},
+		{Voter: drep, ActionID: GovActionID{TxHash: Hash{5}, Index: 1}, Vote: VoteNo},
+		{
+			Voter:    Voter{Type: VoterTypePoolKeyHash, Hash: hex.EncodeToString(poolHash[:])},
+			ActionID: GovActionID{TxHash: Hash{5}, Index: 1},
+			Vote:     VoteAbstain,
+		},
+	}, votes)
+}
+
+func TestNewProposals(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewProposals(Hash{1}, nil))
+
+	rewardAddr, err := ledgerCommon.NewAddressFromParts(
+		ledgerCommon.AddressTypeNoneKey, ledgerCommon.AddressNetworkMainnet, make([]byte, 28), nil)
+	require.NoError(t, err)
+
+	anchor := ledgerCommon.GovAnchor{Url: "https://proposal", DataHash: [32]byte{7}}
+	proposals := NewProposals(Hash{1}, []ledgerCommon.ProposalProcedure{
+		{
+			Deposit:       100,
+			RewardAccount: rewardAddr,
+			GovAction: ledgerCommon.GovActionWrapper{
+				Type: ledgerCommon.GovActionTypeNoConfidence,
+				Action: &ledgerCommon.NoConfidenceGovAction{
+					ActionId: &ledgerCommon.GovActionId{TransactionId: [32]byte{2}, GovActionIdx: 3},
+				},
+			},
+			Anchor: anchor,
+		},
+		{
+			Deposit:       200,
+			RewardAccount: rewardAddr,
+			GovAction: ledgerCommon.GovActionWrapper{
+				Type: ledgerCommon.GovActionTypeTreasuryWithdrawal,
+				Action: &ledgerCommon.TreasuryWithdrawalGovAction{
+					Withdrawals: map[*ledgerCommon.Address]uint64{&rewardAddr: 50},
+				},
+			},
+			Anchor: anchor,
+		},
+	})
+
+	expectedAnchor := Anchor{URL: "https://proposal", DataHash: Hash{7}.String()}
+	require.Equal(t, []*Proposal{
+		{
+			ActionID:      GovActionID{TxHash: Hash{1}, Index: 0},
+			Type:          GovActionNoConfidence,
+			Deposit:       100,
+			RewardAccount: rewardAddr.String(),
+			Anchor:        expectedAnchor,
+			PrevActionID:  &GovActionID{TxHash: Hash{2}, Index: 3},
+		},
+		{
+			ActionID:      GovActionID{TxHash: Hash{1}, Index: 1},
+			Type:          GovActionTreasuryWithdrawal,
+			Deposit:       200,
+			RewardAccount: rewardAddr.String(),
+			Anchor:        expectedAnchor,
+			Withdrawals:   []*TreasuryWithdrawal{{RewardAddress: rewardAddr.String(), Amount: 50}},
+		},
+	}, proposals)
+}
+
+func TestNewGovEvents(t *testing.T) {
+	t.Parallel()
+
+	proposal := &Proposal{ActionID: GovActionID{TxHash: Hash{1}}, Type: GovActionInfo}
+	vote := &Vote{Voter: Voter{Type: VoterTypeDRepKeyHash, Hash: "ab"}, ActionID: proposal.ActionID, Vote: VoteYes}
+
+	proposals, votes := NewGovEvents([]*Tx{
+		{BlockSlot: 10, Indx: 1, Hash: Hash{1}, Valid: true, Proposals: []*Proposal{proposal}},
+		{BlockSlot: 10, Indx: 2, Hash: Hash{2}, Valid: false, Votes: []*Vote{vote}},
+		{BlockSlot: 20, Indx: 0, Hash: Hash{3}, Valid: true, Votes: []*Vote{vote}},
+	})
+
+	require.Equal(t, []*GovProposalEvent{{Proposal: proposal, BlockSlot: 10, TxIndx: 1}}, proposals)
+	require.Equal(t, []*GovVoteEvent{{Vote: vote, BlockSlot: 20, TxHash: Hash{3}}}, votes)
+}
+
+func TestParseGovActionID(t *testing.T) {
+	t.Parallel()
+
+	actionID := GovActionID{TxHash: Hash{1, 2, 3}, Index: 7}
+
+	result, err := ParseGovActionID(actionID.String())
+	require.NoError(t, err)
+	require.Equal(t, actionID, result)
+
+	for _, invalid := range []string{"", "abcd#1", actionID.TxHash.String(), actionID.TxHash.String() + "#x"} {
+		_, err := ParseGovActionID(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestVoterToKey(t *testing.T) {
+	t.Parallel()
+
+	hash := hex.EncodeToString(make([]byte, CredentialHashSize))
+
+	key, err := VoterToKey(Voter{Type: VoterTypeDRepScriptHash, Hash: hash})
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{3}, make([]byte, CredentialHashSize)...), key)
+
+	_, err = VoterToKey(Voter{Type: "unknown", Hash: hash})
+	require.Error(t, err)
+
+	_, err = VoterToKey(Voter{Type: VoterTypePoolKeyHash, Hash: "abcd"})
+	require.Error(t, err)
+
+	_, err = GovVoteEvent{Vote: &Vote{Voter: Voter{Type: VoterTypePoolKeyHash, Hash: "abcd"}}}.Key()
+	require.Error(t, err)
+}
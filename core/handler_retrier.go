@@ -0,0 +1,127 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const handlerRetrierQueueSize = 64
+
+type confirmedBlockItem struct {
+	block *CardanoBlock
+	txs   []*Tx
+}
+
+// handlerRetrier calls confirmed block handler in a separate routine, in the order in which blocks are confirmed.
+// Failed handler is retried and after the last failed attempt block is moved to the dead-letter store
+type handlerRetrier struct {
+	config  RetryConfig
+	handler NewConfirmedBlockHandler
+	db      BlockIndexerDB
+	logger  hclog.Logger
+
+	itemsCh   chan confirmedBlockItem
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+	lock      sync.Mutex
+	isClosed  bool
+}
+
+func newHandlerRetrier(
+	config RetryConfig, handler NewConfirmedBlockHandler, db BlockIndexerDB, logger hclog.Logger,
+) *handlerRetrier {
+	return &handlerRetrier{
+		config:  config,
+		handler: handler,
+		db:      db,
+		logger:  logger,
+		itemsCh: make(chan confirmedBlockItem, handlerRetrierQueueSize),
+		closeCh: make(chan struct{}),
+	}
+}
+
+func (r *handlerRetrier) Start() {
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		// channel is closed by Close, so all queued blocks are handled before the routine ends
+		for item := range r.itemsCh {
+			r.handle(item)
+		}
+	}()
+}
+
+// Notify queues already saved confirmed block for the handler. It blocks while the queue is full.
+// Block confirmed after Close is handled right away, after all queued blocks
+func (r *handlerRetrier) Notify(block *CardanoBlock, txs []*Tx) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	item := confirmedBlockItem{block: block, txs: txs}
+
+	if r.isClosed {
+		r.wg.Wait()
+		r.handle(item)
+
+		return
+	}
+
+	r.itemsCh <- item
+}
+
+// Close handles all queued blocks and stops the routine. Remaining attempts of the block which is being retried
+// and of the queued blocks are made without waiting for the backoff, so only exhausted blocks are dead-lettered
+func (r *handlerRetrier) Close() error {
+	// closing the channel stops waiting for the backoff, so the queue is drained and Notify releases the lock
+	r.closeOnce.Do(func() {
+		close(r.closeCh)
+	})
+
+	r.lock.Lock()
+
+	if !r.isClosed {
+		r.isClosed = true
+
+		close(r.itemsCh)
+	}
+
+	r.lock.Unlock()
+
+	r.wg.Wait()
+
+	return nil
+}
+
+func (r *handlerRetrier) handle(item confirmedBlockItem) {
+	handler := func() error {
+		return r.handler(item.block, item.txs)
+	}
+
+	attempts, err := Retry(r.config, r.closeCh, handler)
+	// retrier is closed while waiting for the next attempt, remaining attempts are made right away
+	for err != nil && attempts < max(r.config.MaxAttempts, 1) {
+		attempts++
+		err = handler()
+	}
+
+	if err != nil {
+		r.deadLetter(item, err, attempts)
+	}
+}
+
+func (r *handlerRetrier) deadLetter(item confirmedBlockItem, err error, attempts int) {
+	r.logger.Error("Confirmed block handler failed", "hash", item.block.Hash, "slot", item.block.Slot,
+		"txs", len(item.txs), "attempts", attempts, "err", err)
+
+	deadLetterTxs := NewDeadLetterTxs(item.block, item.txs, err, attempts, time.Now())
+
+	if err := r.db.OpenTx().AddDeadLetterTxs(deadLetterTxs).Execute(); err != nil {
+		r.logger.Error("Could not save dead-letter txs", "hash", item.block.Hash, "slot", item.block.Slot,
+			"err", err)
+	}
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+)
+
+const (
+	MetadataLabelCIP20 = 674
+	MetadataLabelCIP25 = 721
+
+	// alonzo auxiliary data is a map tagged with this tag
+	cborTagAuxiliaryData = 259
+)
+
+var errMetadataMissing = errors.New("metadata is missing")
+
+// DecodeMetadata decodes tx auxiliary data (shelley metadata map, shelley-ma array or alonzo tagged map).
+// Metadatum values are converted the same way as cardano-cli does for metadata without schema:
+// maps become objects with string keys, bytes become 0x prefixed hex strings
+func DecodeMetadata(auxData []byte) (map[uint64]interface{}, error) {
+	labels, err := decodeMetadataLabels(auxData)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint64]interface{}, len(labels))
+
+	for label, value := range labels {
+		result[label], err = metadatumToJSONValue(value.Value())
+		if err != nil {
+			return nil, fmt.Errorf("label %d: %w", label, err)
+		}
+	}
+
+	return result, nil
+}
+
+// MetadataToJSON decodes tx auxiliary data into JSON object with labels as keys
+func MetadataToJSON(auxData []byte) ([]byte, error) {
+	metadata, err := DecodeMetadata(auxData)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{}, len(metadata))
+	for label, value := range metadata {
+		result[strconv.FormatUint(label, 10)] = value
+	}
+
+	return json.Marshal(result)
+}
+
+// GetMetadataLabels returns sorted labels of the tx auxiliary data
+func GetMetadataLabels(auxData []byte) ([]uint64, error) {
+	labels, err := decodeMetadataLabels(auxData)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]uint64, 0, len(labels))
+	for label := range labels {
+		result = append(result, label)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+
+	return result, nil
+}
+
+// GetCIP20Message returns CIP-20 transaction message lines. nil is returned if there is no message
+func GetCIP20Message(auxData []byte) ([]string, error) {
+	metadata, err := DecodeMetadata(auxData)
+	if err != nil {
+		return nil, err
+	}
+
+	labelValue, ok := metadata[MetadataLabelCIP20].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	switch msg := labelValue["msg"].(type) {
+	case string:
+		return []string{msg}, nil
+	case []interface{}:
+		lines := make([]string, len(msg))
+
+		for i, x := range msg {
+			line, ok := x.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid CIP-20 message line: %v", x)
+			}
+
+			lines[i] = line
+		}
+
+		return lines, nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeMetadataLabels(auxData []byte) (map[uint64]cbor.Value, error) {
+	metadataCbor, err := getMetadataCbor(auxData)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels map[uint64]cbor.Value
+
+	if len(metadataCbor) == 0 {
+		return labels, nil
+	}
+
+	if _, err := cbor.Decode(metadataCbor, &labels); err != nil {
+		return nil, fmt.Errorf("could not decode metadata: %w", err)
+	}
+
+	return labels, nil
+}
+
+// getMetadataCbor extracts metadata map from any form of auxiliary data
+func getMetadataCbor(auxData []byte) ([]byte, error) {
+	if len(auxData) == 0 {
+		return nil, errMetadataMissing
+	}
+
+	switch auxData[0] & cbor.CborTypeMask {
+	case cbor.CborTypeMap:
+		return auxData, nil
+	case cbor.CborTypeArray:
+		var items []cbor.RawMessage
+
+		if _, err := cbor.Decode(auxData, &items); err != nil {
+			return nil, fmt.Errorf("could not decode auxiliary data: %w", err)
+		}
+
+		if len(items) == 0 {
+			return nil, errMetadataMissing
+		}
+
+		return items[0], nil
+	case cbor.CborTypeTag:
+		var (
+			tag   cbor.RawTag
+			items map[uint64]cbor.RawMessage
+		)
+
+		if _, err := cbor.Decode(auxData, &tag); err != nil {
+			return nil, fmt.Errorf("could not decode auxiliary data: %w", err)
+		}
+
+		if tag.Number != cborTagAuxiliaryData {
+			return nil, fmt.Errorf("invalid auxiliary data tag: %d", tag.Number)
+		}
+
+		if _, err := cbor.Decode(tag.Content, &items); err != nil {
+			return nil, fmt.Errorf("could not decode auxiliary data: %w", err)
+		}
+
+		return items[0], nil
+	default:
+		return nil, fmt.Errorf("invalid auxiliary data type: %x", auxData[0])
+	}
+}
+
+func metadatumToJSONValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case uint64, int64, string:
+		return v, nil
+	case big.Int:
+		return &v, nil
+	case cbor.ByteString:
+		return "0x" + hex.EncodeToString(v.Bytes()), nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+
+		for i, x := range v {
+			item, err := metadatumToJSONValue(x)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = item
+		}
+
+		return result, nil
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+
+		for key, x := range v {
+			keyValue, err := metadatumToJSONValue(key)
+			if err != nil {
+				return nil, err
+			}
+
+			item, err := metadatumToJSONValue(x)
+			if err != nil {
+				return nil, err
+			}
+
+			result[fmt.Sprint(keyValue)] = item
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported metadatum type: %T", value)
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeMetadata(t *testing.T) {
+	t.Parallel()
+
+	metadata := map[uint64]interface{}{
+		MetadataLabelCIP20: map[string]interface{}{
+			"msg": []interface{}{"hello", "world"},
+		},
+		1: map[interface{}]interface{}{
+			uint64(5): []byte{1, 2},
+			"list":    []interface{}{uint64(1), int64(-2)},
+		},
+	}
+
+	metadataCbor, err := cbor.Encode(metadata)
+	require.NoError(t, err)
+
+	shelleyMaCbor, err := cbor.Encode([]interface{}{cbor.RawMessage(metadataCbor), []interface{}{}})
+	require.NoError(t, err)
+
+	alonzoCbor, err := cbor.Encode(cbor.Tag{
+		Number:  cborTagAuxiliaryData,
+		Content: map[uint64]interface{}{0: cbor.RawMessage(metadataCbor)},
+	})
+	require.NoError(t, err)
+
+	for name, auxData := range map[string][]byte{
+		"shelley":    metadataCbor,
+		"shelley-ma": shelleyMaCbor,
+		"alonzo":     alonzoCbor,
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := MetadataToJSON(auxData)
+			require.NoError(t, err)
+			require.JSONEq(t, `{
+				"1": {"5": "0x0102", "list": [1, -2]},
+				"674": {"msg": ["hello", "world"]}
+			}`, string(result))
+
+			labels, err := GetMetadataLabels(auxData)
+			require.NoError(t, err)
+			require.Equal(t, []uint64{1, MetadataLabelCIP20}, labels)
+
+			msg, err := GetCIP20Message(auxData)
+			require.NoError(t, err)
+			require.Equal(t, []string{"hello", "world"}, msg)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := DecodeMetadata(nil)
+		require.ErrorIs(t, err, errMetadataMissing)
+
+		_, err = DecodeMetadata([]byte{0x01})
+		require.ErrorContains(t, err, "invalid auxiliary data type")
+	})
+
+	t.Run("tx string", func(t *testing.T) {
+		str := Tx{Metadata: metadataCbor}.String()
+
+		require.True(t, strings.Contains(str, `"674":{"msg":["hello","world"]}`), str)
+	})
+}
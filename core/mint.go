@@ -0,0 +1,152 @@
+package core
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+const PolicyIDSize = 28
+
+// MintAmount is minted (positive amount) or burned (negative amount) quantity of an asset
+type MintAmount struct {
+	PolicyID string `json:"polid"`
+	Name     string `json:"name"`
+	Amount   int64  `json:"amnt"`
+}
+
+// MintEvent is one mint or burn of an asset inside a confirmed tx. Events are indexed by policy ID
+type MintEvent struct {
+	PolicyID  string `json:"polid"`
+	Name      string `json:"name"`
+	Amount    int64  `json:"amnt"`
+	BlockSlot uint64 `json:"slot"`
+	TxIndx    uint32 `json:"ind"`
+	TxHash    Hash   `json:"hash"`
+}
+
+// AssetSupply is the supply of an asset right after the mint event
+type AssetSupply struct {
+	Event  *MintEvent `json:"event"`
+	Supply int64      `json:"supply"`
+}
+
+// NewMintAmounts converts ledger mint field. Result is sorted by policy id and asset name
+func NewMintAmounts(assets *ledgerCommon.MultiAsset[ledgerCommon.MultiAssetTypeMint]) (result []MintAmount) {
+	if assets == nil {
+		return nil
+	}
+
+	for _, policyIDRaw := range assets.Policies() {
+		policyID := policyIDRaw.String()
+
+		for _, asset := range assets.Assets(policyIDRaw) {
+			result = append(result, MintAmount{
+				PolicyID: policyID,
+				Name:     string(asset),
+				Amount:   assets.Asset(policyIDRaw, asset),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PolicyID != result[j].PolicyID {
+			return result[i].PolicyID < result[j].PolicyID
+		}
+
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// NewMintEvents creates mint events for all mints and burns of valid txs
+func NewMintEvents(txs []*Tx) (result []*MintEvent) {
+	for _, tx := range txs {
+		if !tx.Valid {
+			continue
+		}
+
+		for _, mint := range tx.Mint {
+			result = append(result, &MintEvent{
+				PolicyID:  mint.PolicyID,
+				Name:      mint.Name,
+				Amount:    mint.Amount,
+				BlockSlot: tx.BlockSlot,
+				TxIndx:    tx.Indx,
+				TxHash:    tx.Hash,
+			})
+		}
+	}
+
+	return result
+}
+
+// GetAssetSupplyHistory returns every mint and burn of the asset together with the supply after it.
+// Mint events are indexed only for txs of interest, so history (and supply) is complete only if the policy is
+// in BlockIndexerConfig.PoliciesOfInterest or no filter is configured, and the indexer started before the first mint
+func GetAssetSupplyHistory(db MintEventsRetriever, policyID string, name string) ([]*AssetSupply, error) {
+	events, err := db.GetMintEvents(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result []*AssetSupply
+		supply int64
+	)
+
+	for _, event := range events {
+		if event.Name != name {
+			continue
+		}
+
+		supply += event.Amount
+
+		result = append(result, &AssetSupply{
+			Event:  event,
+			Supply: supply,
+		})
+	}
+
+	return result, nil
+}
+
+// PolicyIDToKey returns the key prefix of all mint events of the policy
+func PolicyIDToKey(policyID string) ([]byte, error) {
+	bytes, err := hex.DecodeString(strings.TrimPrefix(policyID, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy id %s: %w", policyID, err)
+	}
+
+	if len(bytes) != PolicyIDSize {
+		return nil, fmt.Errorf("invalid policy id size: %d", len(bytes))
+	}
+
+	return bytes, nil
+}
+
+// Key is policy id followed by slot, tx index and asset name, so events of the policy are ordered by time
+func (me MintEvent) Key() []byte {
+	policyKey, _ := hex.DecodeString(me.PolicyID)
+	key := make([]byte, PolicyIDSize+8+4+len(me.Name))
+
+	copy(key, policyKey)
+	binary.BigEndian.PutUint64(key[PolicyIDSize:], me.BlockSlot)
+	binary.BigEndian.PutUint32(key[PolicyIDSize+8:], me.TxIndx)
+	copy(key[PolicyIDSize+12:], me.Name)
+
+	return key
+}
+
+func (ma MintAmount) TokenName() string {
+	return fmt.Sprintf("%s.%s", ma.PolicyID, hex.EncodeToString([]byte(ma.Name)))
+}
+
+func (ma MintAmount) String() string {
+	return fmt.Sprintf("%d %s", ma.Amount, ma.TokenName())
+}
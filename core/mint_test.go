@@ -0,0 +1,69 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMintAmounts(t *testing.T) {
+	t.Parallel()
+
+	policyIDs := []ledgerCommon.Blake2b224{{1, 2, 3}, {4, 5, 6}}
+	mint := ledgerCommon.NewMultiAsset[ledgerCommon.MultiAssetTypeMint](
+		map[ledgerCommon.Blake2b224]map[cbor.ByteString]int64{
+			policyIDs[1]: {
+				cbor.NewByteString([]byte("b")): 10,
+				cbor.NewByteString([]byte("a")): -5,
+			},
+			policyIDs[0]: {
+				cbor.NewByteString([]byte("c")): 1,
+			},
+		})
+
+	require.Nil(t, NewMintAmounts(nil))
+	require.Equal(t, []MintAmount{
+		{PolicyID: policyIDs[0].String(), Name: "c", Amount: 1},
+		{PolicyID: policyIDs[1].String(), Name: "a", Amount: -5},
+		{PolicyID: policyIDs[1].String(), Name: "b", Amount: 10},
+	}, NewMintAmounts(&mint))
+}
+
+func TestNewMintEvents(t *testing.T) {
+	t.Parallel()
+
+	mint := []MintAmount{{PolicyID: "01", Name: "a", Amount: 10}}
+	events := NewMintEvents([]*Tx{
+		{BlockSlot: 10, Indx: 1, Hash: Hash{1}, Valid: true, Mint: mint},
+		{BlockSlot: 10, Indx: 2, Hash: Hash{2}, Valid: false, Mint: mint}, // phase-2 failed tx does not mint
+		{BlockSlot: 10, Indx: 3, Hash: Hash{3}, Valid: true},
+	})
+
+	require.Equal(t, []*MintEvent{
+		{PolicyID: "01", Name: "a", Amount: 10, BlockSlot: 10, TxIndx: 1, TxHash: Hash{1}},
+	}, events)
+}
+
+func TestGetAssetSupplyHistory(t *testing.T) {
+	t.Parallel()
+
+	events := []*MintEvent{
+		{PolicyID: "01", Name: "a", Amount: 10, BlockSlot: 1},
+		{PolicyID: "01", Name: "b", Amount: 3, BlockSlot: 1},
+		{PolicyID: "01", Name: "a", Amount: -4, BlockSlot: 2},
+	}
+	dbMock := &DatabaseMock{}
+
+	dbMock.On("GetMintEvents", "01").Return(events, error(nil)).Once()
+
+	history, err := GetAssetSupplyHistory(dbMock, "01", "a")
+
+	require.NoError(t, err)
+	require.Equal(t, []*AssetSupply{
+		{Event: events[0], Supply: 10},
+		{Event: events[2], Supply: 6},
+	}, history)
+	dbMock.AssertExpectations(t)
+}
@@ -0,0 +1,322 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+)
+
+const (
+	CIP68LabelReferenceNFT = 100
+	CIP68LabelNFT          = 222
+	CIP68LabelFT           = 333
+	CIP68LabelRFT          = 444
+
+	cip67LabelSize = 4
+)
+
+type CIP25File struct {
+	Name      string `json:"name,omitempty"`
+	MediaType string `json:"mediaType"`
+	Src       string `json:"src"`
+}
+
+// CIP25Asset is NFT metadata of one asset from the 721 metadata label
+type CIP25Asset struct {
+	PolicyID    string      `json:"polid"`
+	AssetName   string      `json:"asset"`
+	Name        string      `json:"name"`
+	Image       string      `json:"image"`
+	MediaType   string      `json:"mediaType,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Files       []CIP25File `json:"files,omitempty"`
+	// all other properties of the asset
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// CIP68Metadata is metadata stored in the datum of CIP-68 reference token
+type CIP68Metadata struct {
+	Metadata map[string]interface{} `json:"metadata"`
+	Version  uint64                 `json:"version"`
+}
+
+// GetCIP25Assets returns NFT metadata of all assets from tx auxiliary data sorted by policy id and asset name.
+// Both version 1 (text keys) and version 2 (bytes keys) are supported
+func GetCIP25Assets(auxData []byte) ([]*CIP25Asset, error) {
+	labels, err := decodeMetadataLabels(auxData)
+	if err != nil {
+		return nil, err
+	}
+
+	labelValue, exists := labels[MetadataLabelCIP25]
+	if !exists {
+		return nil, nil
+	}
+
+	policies, ok := labelValue.Value().(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("invalid CIP-25 metadata")
+	}
+
+	var result []*CIP25Asset
+
+	for policyKey, assetsRaw := range policies {
+		var policyID string
+
+		switch v := policyKey.(type) {
+		case string:
+			if v == "version" {
+				continue
+			}
+
+			policyID = v
+		case cbor.ByteString:
+			policyID = hex.EncodeToString(v.Bytes())
+		default:
+			return nil, fmt.Errorf("invalid CIP-25 policy id: %v", policyKey)
+		}
+
+		assets, ok := assetsRaw.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid CIP-25 assets of policy %s", policyID)
+		}
+
+		for assetKey, propertiesRaw := range assets {
+			asset, err := newCIP25Asset(policyID, assetKey, propertiesRaw)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, asset)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PolicyID != result[j].PolicyID {
+			return result[i].PolicyID < result[j].PolicyID
+		}
+
+		return result[i].AssetName < result[j].AssetName
+	})
+
+	return result, nil
+}
+
+// DecodeCIP68Datum decodes datum of CIP-68 reference token: constructor 0 [metadata, version, extra].
+// Bytes which are valid utf-8 are converted to strings, all other bytes to 0x prefixed hex strings
+func DecodeCIP68Datum(datum []byte) (*CIP68Metadata, error) {
+	var value cbor.Value
+
+	if _, err := cbor.Decode(datum, &value); err != nil {
+		return nil, fmt.Errorf("could not decode CIP-68 datum: %w", err)
+	}
+
+	constr, ok := value.Value().(cbor.Constructor)
+	if !ok || constr.Constructor() != 0 || len(constr.Fields()) < 2 {
+		return nil, errors.New("invalid CIP-68 datum")
+	}
+
+	version, ok := constr.Fields()[1].(uint64)
+	if !ok {
+		return nil, errors.New("invalid CIP-68 datum version")
+	}
+
+	metadataRaw, ok := constr.Fields()[0].(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("invalid CIP-68 datum metadata")
+	}
+
+	metadata, err := cip68ValueToJSONValue(metadataRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CIP68Metadata{
+		Metadata: metadata.(map[string]interface{}), //nolint:forcetypeassert
+		Version:  version,
+	}, nil
+}
+
+// ParseCIP68AssetName splits asset name into CIP-67 label and the rest of the name.
+// ok is false if asset name does not start with a valid CIP-67 label
+func ParseCIP68AssetName(assetName string) (label uint16, name string, ok bool) {
+	if len(assetName) < cip67LabelSize {
+		return 0, assetName, false
+	}
+
+	// label is encoded as: 0000 | 16 bits label | 8 bits crc-8 of the label | 0000
+	prefix := []byte(assetName[:cip67LabelSize])
+	if prefix[0]>>4 != 0 || prefix[3]&0x0f != 0 {
+		return 0, assetName, false
+	}
+
+	label = uint16(prefix[0])<<12 | uint16(prefix[1])<<4 | uint16(prefix[2])>>4
+	checksum := prefix[2]<<4 | prefix[3]>>4
+
+	if crc8([]byte{byte(label >> 8), byte(label)}) != checksum {
+		return 0, assetName, false
+	}
+
+	return label, assetName[cip67LabelSize:], true
+}
+
+// CIP68AssetName returns asset name prefixed with the CIP-67 label
+func CIP68AssetName(label uint16, name string) string {
+	checksum := crc8([]byte{byte(label >> 8), byte(label)})
+	prefix := []byte{
+		byte(label >> 12),
+		byte(label >> 4),
+		byte(label<<4) | checksum>>4,
+		checksum << 4,
+	}
+
+	return string(prefix) + name
+}
+
+func newCIP25Asset(policyID string, assetKey interface{}, propertiesRaw interface{}) (*CIP25Asset, error) {
+	asset := &CIP25Asset{
+		PolicyID: policyID,
+	}
+
+	switch v := assetKey.(type) {
+	case string:
+		asset.AssetName = v
+	case cbor.ByteString:
+		asset.AssetName = string(v.Bytes())
+	default:
+		return nil, fmt.Errorf("invalid CIP-25 asset name: %v", assetKey)
+	}
+
+	propertiesValue, err := metadatumToJSONValue(propertiesRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, ok := propertiesValue.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid CIP-25 properties of asset %s", asset.AssetName)
+	}
+
+	for key, value := range properties {
+		switch key {
+		case "name":
+			asset.Name = joinMetadataString(value)
+		case "image":
+			asset.Image = joinMetadataString(value)
+		case "mediaType":
+			asset.MediaType = joinMetadataString(value)
+		case "description":
+			asset.Description = joinMetadataString(value)
+		case "files":
+			files, _ := value.([]interface{})
+
+			for _, fileRaw := range files {
+				file, _ := fileRaw.(map[string]interface{})
+
+				asset.Files = append(asset.Files, CIP25File{
+					Name:      joinMetadataString(file["name"]),
+					MediaType: joinMetadataString(file["mediaType"]),
+					Src:       joinMetadataString(file["src"]),
+				})
+			}
+		default:
+			if asset.Attributes == nil {
+				asset.Attributes = map[string]interface{}{}
+			}
+
+			asset.Attributes[key] = value
+		}
+	}
+
+	return asset, nil
+}
+
+// joinMetadataString joins metadata strings which are split into chunks because of the 64 bytes limit
+func joinMetadataString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		var sb strings.Builder
+
+		for _, x := range v {
+			if str, ok := x.(string); ok {
+				sb.WriteString(str)
+			}
+		}
+
+		return sb.String()
+	default:
+		return ""
+	}
+}
+
+func cip68ValueToJSONValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case cbor.ByteString:
+		if utf8.Valid(v.Bytes()) {
+			return string(v.Bytes()), nil
+		}
+
+		return "0x" + hex.EncodeToString(v.Bytes()), nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+
+		for i, x := range v {
+			item, err := cip68ValueToJSONValue(x)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = item
+		}
+
+		return result, nil
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+
+		for key, x := range v {
+			keyValue, err := cip68ValueToJSONValue(key)
+			if err != nil {
+				return nil, err
+			}
+
+			item, err := cip68ValueToJSONValue(x)
+			if err != nil {
+				return nil, err
+			}
+
+			result[fmt.Sprint(keyValue)] = item
+		}
+
+		return result, nil
+	case cbor.Constructor:
+		return nil, errors.New("constructors are not supported in CIP-68 metadata")
+	default:
+		return metadatumToJSONValue(value)
+	}
+}
+
+// crc8 with polynomial 0x07 as used by CIP-67
+func crc8(data []byte) byte {
+	var crc byte
+
+	for _, b := range data {
+		crc ^= b
+
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
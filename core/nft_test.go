@@ -0,0 +1,127 @@
+package core
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCIP25Assets(t *testing.T) {
+	t.Parallel()
+
+	const policyID = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b"
+
+	properties := map[string]interface{}{
+		"name":      "NFT 1",
+		"image":     []interface{}{"ipfs://", "Qm123"},
+		"mediaType": "image/png",
+		"files": []interface{}{
+			map[string]interface{}{"mediaType": "image/png", "src": "ipfs://Qm123"},
+		},
+		"rarity": "rare",
+	}
+	expected := []*CIP25Asset{
+		{
+			PolicyID:  policyID,
+			AssetName: "nft1",
+			Name:      "NFT 1",
+			Image:     "ipfs://Qm123",
+			MediaType: "image/png",
+			Files:     []CIP25File{{MediaType: "image/png", Src: "ipfs://Qm123"}},
+			Attributes: map[string]interface{}{
+				"rarity": "rare",
+			},
+		},
+	}
+
+	policyIDBytes, err := hex.DecodeString(policyID)
+	require.NoError(t, err)
+
+	for name, metadata := range map[string]interface{}{
+		"version 1": map[interface{}]interface{}{
+			policyID: map[interface{}]interface{}{"nft1": properties},
+		},
+		"version 2": map[interface{}]interface{}{
+			cbor.NewByteString(policyIDBytes): map[interface{}]interface{}{
+				cbor.NewByteString([]byte("nft1")): properties,
+			},
+			"version": 2,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			auxData, err := cbor.Encode(map[uint64]interface{}{MetadataLabelCIP25: metadata})
+			require.NoError(t, err)
+
+			assets, err := GetCIP25Assets(auxData)
+			require.NoError(t, err)
+			require.Equal(t, expected, assets)
+		})
+	}
+
+	auxData, err := cbor.Encode(map[uint64]interface{}{MetadataLabelCIP20: "msg"})
+	require.NoError(t, err)
+
+	assets, err := GetCIP25Assets(auxData)
+	require.NoError(t, err)
+	require.Nil(t, assets)
+}
+
+func TestCIP68AssetName(t *testing.T) {
+	t.Parallel()
+
+	for label, prefix := range map[uint16]string{
+		CIP68LabelReferenceNFT: "000643b0",
+		CIP68LabelNFT:          "000de140",
+		CIP68LabelFT:           "0014df10",
+		CIP68LabelRFT:          "001bc280",
+	} {
+		assetName := CIP68AssetName(label, "token")
+		require.Equal(t, prefix+hex.EncodeToString([]byte("token")), hex.EncodeToString([]byte(assetName)))
+
+		parsedLabel, name, ok := ParseCIP68AssetName(assetName)
+		require.True(t, ok)
+		require.Equal(t, label, parsedLabel)
+		require.Equal(t, "token", name)
+	}
+
+	_, name, ok := ParseCIP68AssetName("token")
+	require.False(t, ok)
+	require.Equal(t, "token", name)
+
+	_, _, ok = ParseCIP68AssetName("\x00\x06\x43\xc0token") // wrong checksum
+	require.False(t, ok)
+}
+
+func TestDecodeCIP68Datum(t *testing.T) {
+	t.Parallel()
+
+	datum, err := cbor.Encode(cbor.NewConstructor(0, []interface{}{
+		map[interface{}]interface{}{
+			cbor.NewByteString([]byte("name")):  cbor.NewByteString([]byte("NFT 1")),
+			cbor.NewByteString([]byte("image")): cbor.NewByteString([]byte("ipfs://Qm123")),
+			cbor.NewByteString([]byte("hash")):  cbor.NewByteString([]byte{0xff, 0xfe}),
+		},
+		uint64(1),
+		[]byte{},
+	}))
+	require.NoError(t, err)
+
+	metadata, err := DecodeCIP68Datum(datum)
+	require.NoError(t, err)
+	require.Equal(t, &CIP68Metadata{
+		Metadata: map[string]interface{}{
+			"name":  "NFT 1",
+			"image": "ipfs://Qm123",
+			"hash":  "0xfffe",
+		},
+		Version: 1,
+	}, metadata)
+
+	datum, err = cbor.Encode(cbor.NewConstructor(1, []interface{}{}))
+	require.NoError(t, err)
+
+	_, err = DecodeCIP68Datum(datum)
+	require.ErrorContains(t, err, "invalid CIP-68 datum")
+}
@@ -0,0 +1,246 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+)
+
+const (
+	// constructors 0-6 are encoded with tags 121-127, constructors 7-127 with tags 1280-1400
+	// and all other constructors with tag 102 and [constructor, fields] content
+	cborTagConstrGeneral  = 102
+	constrExtOffset       = cbor.CborTagAlternative2Min - 7
+	cborTagPositiveBigNum = 2
+	cborTagNegativeBigNum = 3
+
+	cborTypePositiveInt  uint8 = 0x00
+	cborTypeNegativeInt  uint8 = 0x20
+	cborIndefiniteLength uint8 = 0x1f
+)
+
+// PlutusDataConstr is constructor in the detailed schema json
+type PlutusDataConstr struct {
+	Constructor uint64        `json:"constructor"`
+	Fields      []interface{} `json:"fields"`
+}
+
+// PlutusDataMapEntry is one key/value pair of a map in the detailed schema json
+type PlutusDataMapEntry struct {
+	K interface{} `json:"k"`
+	V interface{} `json:"v"`
+}
+
+// DecodePlutusData decodes plutus data cbor into value with the same structure as
+// cardano-cli detailed schema json: {"constructor", "fields"}, {"int"}, {"bytes"}, {"list"} or {"map": [{"k", "v"}]}
+func DecodePlutusData(data []byte) (interface{}, error) {
+	var raw cbor.RawMessage
+
+	n, err := cbor.Decode(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode plutus data: %w", err)
+	}
+
+	if n != len(data) {
+		return nil, fmt.Errorf("could not decode plutus data: %d extra bytes", len(data)-n)
+	}
+
+	value, err := decodePlutusDataItem(raw)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode plutus data: %w", err)
+	}
+
+	return value, nil
+}
+
+// PlutusDataToJSON converts plutus data cbor into cardano-cli detailed schema json
+func PlutusDataToJSON(data []byte) ([]byte, error) {
+	value, err := DecodePlutusData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(value)
+}
+
+// DatumJSON returns datum of the output in detailed schema json. nil is returned if output has no datum
+func (t TxOutput) DatumJSON() ([]byte, error) {
+	if len(t.Datum) == 0 {
+		return nil, nil
+	}
+
+	return PlutusDataToJSON(t.Datum)
+}
+
+// hasUnresolvedDatum returns true if output has only datum hash
+func (t TxOutput) hasUnresolvedDatum() bool {
+	return len(t.Datum) == 0 && t.DatumHash != (Hash{})
+}
+
+// decodePlutusDataItem decodes one well-formed cbor item. Nesting depth is already limited by the decoder
+func decodePlutusDataItem(data []byte) (interface{}, error) {
+	switch data[0] & cbor.CborTypeMask {
+	case cborTypePositiveInt, cborTypeNegativeInt:
+		return decodePlutusDataInt(data)
+	case cbor.CborTypeByteString:
+		var bytes []byte
+
+		if _, err := cbor.Decode(data, &bytes); err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"bytes": hex.EncodeToString(bytes)}, nil
+	case cbor.CborTypeArray:
+		items, err := decodePlutusDataList(data)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"list": items}, nil
+	case cbor.CborTypeMap:
+		entries, err := decodePlutusDataMap(data)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"map": entries}, nil
+	case cbor.CborTypeTag:
+		return decodePlutusDataTag(data)
+	default:
+		return nil, fmt.Errorf("unsupported cbor type: 0x%x", data[0])
+	}
+}
+
+func decodePlutusDataInt(data []byte) (interface{}, error) {
+	var value big.Int
+
+	if _, err := cbor.Decode(data, &value); err != nil {
+		return nil, err
+	}
+
+	if value.IsInt64() {
+		return map[string]interface{}{"int": value.Int64()}, nil
+	}
+
+	return map[string]interface{}{"int": &value}, nil
+}
+
+func decodePlutusDataTag(data []byte) (interface{}, error) {
+	var tag cbor.RawTag
+
+	if _, err := cbor.Decode(data, &tag); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case tag.Number >= cbor.CborTagAlternative1Min && tag.Number <= cbor.CborTagAlternative1Max:
+		return decodePlutusDataConstr(tag.Number-cbor.CborTagAlternative1Min, tag.Content)
+	case tag.Number >= cbor.CborTagAlternative2Min && tag.Number <= cbor.CborTagAlternative2Max:
+		return decodePlutusDataConstr(tag.Number-constrExtOffset, tag.Content)
+	case tag.Number == cborTagConstrGeneral:
+		var general []cbor.RawMessage
+
+		if _, err := cbor.Decode(tag.Content, &general); err != nil || len(general) != 2 {
+			return nil, errors.New("invalid general constructor")
+		}
+
+		var constr uint64
+
+		if _, err := cbor.Decode(general[0], &constr); err != nil {
+			return nil, fmt.Errorf("invalid general constructor index: %w", err)
+		}
+
+		return decodePlutusDataConstr(constr, general[1])
+	case tag.Number == cborTagPositiveBigNum || tag.Number == cborTagNegativeBigNum:
+		return decodePlutusDataInt(data)
+	default:
+		return nil, fmt.Errorf("unsupported tag: %d", tag.Number)
+	}
+}
+
+func decodePlutusDataConstr(constr uint64, fieldsData []byte) (interface{}, error) {
+	if fieldsData[0]&cbor.CborTypeMask != cbor.CborTypeArray {
+		return nil, errors.New("constructor fields must be a list")
+	}
+
+	fields, err := decodePlutusDataList(fieldsData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlutusDataConstr{
+		Constructor: constr,
+		Fields:      fields,
+	}, nil
+}
+
+func decodePlutusDataList(data []byte) ([]interface{}, error) {
+	var rawItems []cbor.RawMessage
+
+	if _, err := cbor.Decode(data, &rawItems); err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, len(rawItems))
+
+	for i, raw := range rawItems {
+		item, err := decodePlutusDataItem(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = item
+	}
+
+	return items, nil
+}
+
+// decodePlutusDataMap reads entries one by one, because decoding into go map would lose the order of entries
+// and would not support lists or maps as keys
+func decodePlutusDataMap(data []byte) ([]*PlutusDataMapEntry, error) {
+	var items []interface{}
+
+	// items follow the map head. Indefinite length map is terminated by the break code
+	content := data[cborHeadSize(data[0]):]
+	if data[0]&^cbor.CborTypeMask == cborIndefiniteLength {
+		content = content[:len(content)-1]
+	}
+
+	for len(content) > 0 {
+		var raw cbor.RawMessage
+
+		n, err := cbor.Decode(content, &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		item, err := decodePlutusDataItem(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+		content = content[n:]
+	}
+
+	entries := make([]*PlutusDataMapEntry, 0, len(items)/2)
+
+	for i := 0; i+1 < len(items); i += 2 {
+		entries = append(entries, &PlutusDataMapEntry{K: items[i], V: items[i+1]})
+	}
+
+	return entries, nil
+}
+
+// cborHeadSize returns size of the head of the data item with the given initial byte
+func cborHeadSize(initial byte) int {
+	if info := initial &^ cbor.CborTypeMask; info >= 24 && info <= 27 {
+		return 1 + 1<<(info-24)
+	}
+
+	return 1
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/ledger/alonzo"
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlutusDataToJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		cbor     string
+		expected string
+	}{
+		{"int", "1864", `{"int":100}`},
+		{"negative int", "3863", `{"int":-100}`},
+		{"big int", "c249010000000000000000", `{"int":18446744073709551616}`},
+		{"negative big int", "c349010000000000000000", `{"int":-18446744073709551617}`},
+		{"bytes", "43010203", `{"bytes":"010203"}`},
+		{"chunked bytes", "5f41014102ff", `{"bytes":"0102"}`},
+		{"list", "9f0120ff", `{"list":[{"int":1},{"int":-1}]}`},
+		{"min negative int", "3bffffffffffffffff", `{"int":-18446744073709551616}`},
+		{"indefinite map", "bf0102ff", `{"map":[{"k":{"int":1},"v":{"int":2}}]}`},
+		{
+			"map keeps order and allows any key", "a20201810120",
+			`{"map":[{"k":{"int":2},"v":{"int":1}},{"k":{"list":[{"int":1}]},"v":{"int":-1}}]}`,
+		},
+		{"constructor 0", "d8799f4100ff", `{"constructor":0,"fields":[{"bytes":"00"}]}`},
+		{"constructor 7", "d9050080", `{"constructor":7,"fields":[]}`},
+		{"constructor 1000", "d866821903e88101", `{"constructor":1000,"fields":[{"int":1}]}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.cbor)
+			require.NoError(t, err)
+
+			result, err := PlutusDataToJSON(data)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(result))
+		})
+	}
+
+	for _, invalid := range []string{"", "6161", "0000", "9f01", "d87a01", "f6", "ff"} {
+		data, err := hex.DecodeString(invalid)
+		require.NoError(t, err)
+
+		_, err = PlutusDataToJSON(data)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestTxOutput_DatumJSON(t *testing.T) {
+	t.Parallel()
+
+	result, err := TxOutput{}.DatumJSON()
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	output := TxOutput{Address: "addr", Amount: 10, Datum: []byte{0xd8, 0x79, 0x80}}
+
+	result, err = output.DatumJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"constructor":0,"fields":[]}`, string(result))
+	require.Equal(t, `addr+10+datum {"constructor":0,"fields":[]}`, output.String())
+}
+
+func TestResolveTxDatums(t *testing.T) {
+	t.Parallel()
+
+	datum := &Datum{Hash: Hash{1}, Data: []byte{1}}
+	inlineDatum := []byte{2}
+	tx := &Tx{
+		Inputs: []*TxInputOutput{
+			{Output: TxOutput{DatumHash: datum.Hash}},
+			{Output: TxOutput{DatumHash: Hash{2}}},
+		},
+		Outputs: []*TxOutput{
+			{DatumHash: datum.Hash},
+			{Datum: inlineDatum},
+			{},
+		},
+	}
+
+	resolveTxDatums(tx, &TxWitnessSet{Datums: []*Datum{datum}})
+
+	require.Equal(t, datum.Data, tx.Inputs[0].Output.Datum)
+	require.Nil(t, tx.Inputs[1].Output.Datum)
+	require.Equal(t, datum.Data, tx.Outputs[0].Datum)
+	require.Equal(t, inlineDatum, tx.Outputs[1].Datum)
+	require.Nil(t, tx.Outputs[2].Datum)
+}
+
+func TestBlockIndexer_getTxOutputsResolvesDatums(t *testing.T) {
+	t.Parallel()
+
+	datum := []byte{0xd8, 0x79, 0x80}
+	datumHash := ledgerCommon.Blake2b256Hash(datum)
+
+	witnessSetBytes, err := cbor.Encode(map[uint]interface{}{4: []cbor.RawMessage{datum}})
+	require.NoError(t, err)
+
+	addr, err := ledgerCommon.NewAddress(addresses[0])
+	require.NoError(t, err)
+
+	tx := &alonzo.AlonzoTransaction{IsTxValid: true}
+	tx.Body.TxOutputs = []alonzo.AlonzoTransactionOutput{
+		{OutputAddress: addr, TxOutputDatumHash: &datumHash},
+		{OutputAddress: addr},
+	}
+	require.NoError(t, tx.WitnessSet.UnmarshalCBOR(witnessSetBytes))
+
+	for _, resolve := range []bool{false, true} {
+		blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+			AddressCheck:       AddressCheckOutputs,
+			ResolveDatumHashes: resolve,
+		}, nil, &DatabaseMock{}, hclog.NewNullLogger())
+
+		outputs, err := blockIndexer.getTxOutputs(10, []ledger.Transaction{tx}, nil)
+		require.NoError(t, err)
+		require.Len(t, outputs, 2)
+		require.Equal(t, Hash(datumHash), outputs[0].Output.DatumHash)
+		require.Nil(t, outputs[1].Output.Datum)
+
+		if resolve {
+			require.Equal(t, datum, outputs[0].Output.Datum)
+		} else {
+			require.Nil(t, outputs[0].Output.Datum)
+		}
+	}
+}
+
+func TestBlockIndexer_InvalidWitnessSet(t *testing.T) {
+	t.Parallel()
+
+	datumHash := ledgerCommon.Blake2b256Hash([]byte{0xd8, 0x79, 0x80})
+
+	addr, err := ledgerCommon.NewAddress(addresses[0])
+	require.NoError(t, err)
+
+	tx := &alonzo.AlonzoTransaction{IsTxValid: true}
+	tx.Body.TxOutputs = []alonzo.AlonzoTransactionOutput{{OutputAddress: addr, TxOutputDatumHash: &datumHash}}
+	tx.WitnessSet.SetCbor([]byte{1, 2, 3})
+	require.NotEmpty(t, getTxWitnessSetCbor(tx))
+
+	blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+		AddressCheck:       AddressCheckOutputs,
+		KeepTxWitnesses:    true,
+		ResolveDatumHashes: true,
+	}, nil, &DatabaseMock{}, hclog.NewNullLogger())
+
+	// tx is indexed without witnesses and resolved datums
+	outputs, err := blockIndexer.getTxOutputs(10, []ledger.Transaction{tx}, nil)
+	require.NoError(t, err)
+	require.Len(t, outputs, 1)
+	require.Nil(t, outputs[0].Output.Datum)
+
+	indexedTx, err := blockIndexer.createTx(&LedgerBlockHeaderMock{SlotNumberVal: 10}, tx, 0)
+	require.NoError(t, err)
+	require.Nil(t, indexedTx.WitnessSet)
+	require.Len(t, indexedTx.Outputs, 1)
+	require.Nil(t, indexedTx.Outputs[0].Datum)
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	pruneIntervalDefault  = time.Minute
+	pruneBatchSizeDefault = 1000
+)
+
+type RetentionConfig struct {
+	// how many latest confirmed blocks are kept in the database (0 means all)
+	KeepBlocksCount uint64 `json:"keepBlocksCount"`
+	// how many slots of confirmed blocks, counted back from the latest block, are kept (0 means all).
+	// one slot is one second since Shelley, so 86400 slots is one day
+	KeepBlocksSlots uint64 `json:"keepBlocksSlots"`
+	// how many slots of processed txs, counted back from the latest block, are kept (0 means all).
+	// txs which are not yet processed by every subscriber are kept as well, so subscriber which is not used anymore
+	// stops pruning of processed txs until it is removed with UnregisterSubscriber
+	KeepProcessedTxsSlots uint64 `json:"keepProcessedTxsSlots"`
+	// how often pruning is executed
+	PruneInterval time.Duration `json:"pruneInterval"`
+	// maximum number of items deleted in one database transaction
+	BatchSize int `json:"batchSize"`
+}
+
+func (rc RetentionConfig) IsEnabled() bool {
+	return rc.KeepBlocksCount > 0 || rc.KeepBlocksSlots > 0 || rc.KeepProcessedTxsSlots > 0
+}
+
+type Pruner struct {
+	config *RetentionConfig
+	db     PrunerDB
+	logger hclog.Logger
+
+	closeCh  chan struct{}
+	wg       sync.WaitGroup
+	lock     sync.Mutex
+	isClosed bool
+}
+
+func NewPruner(config *RetentionConfig, db PrunerDB, logger hclog.Logger) *Pruner {
+	return &Pruner{
+		config:  config,
+		db:      db,
+		logger:  logger,
+		closeCh: make(chan struct{}),
+	}
+}
+
+// Start executes pruning periodically in a separate routine until Close is called
+func (p *Pruner) Start() {
+	if !p.config.IsEnabled() {
+		p.logger.Debug("Pruning is disabled")
+
+		return
+	}
+
+	interval := p.config.PruneInterval
+	if interval <= 0 {
+		interval = pruneIntervalDefault
+	}
+
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		for {
+			if err := p.Prune(); err != nil {
+				p.logger.Error("Error while pruning", "err", err)
+			}
+
+			select {
+			case <-p.closeCh:
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+func (p *Pruner) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !p.isClosed {
+		p.isClosed = true
+
+		close(p.closeCh)
+		p.wg.Wait()
+	}
+
+	return nil
+}
+
+// Prune deletes all confirmed blocks and processed txs outside of the retention window.
+// Deletion is done in batches so the database write lock is never held for too long
+func (p *Pruner) Prune() error {
+	latestPoint, err := p.db.GetLatestBlockPoint()
+	if err != nil || latestPoint == nil {
+		return err
+	}
+
+	if isExpired := p.getBlockExpiredFn(latestPoint); isExpired != nil {
+		cnt, err := p.pruneInBatches(func(batchSize int) (int, error) {
+			return p.db.PruneConfirmedBlocks(isExpired, batchSize)
+		})
+		if err != nil {
+			return err
+		}
+
+		if cnt > 0 {
+			p.logger.Debug("Confirmed blocks pruned", "cnt", cnt, "latest", latestPoint.BlockSlot)
+		}
+	}
+
+	if keep := p.config.KeepProcessedTxsSlots; keep > 0 && latestPoint.BlockSlot > keep {
+		pruneSlot, err := p.getProcessedTxsPruneSlot(latestPoint.BlockSlot - keep)
+		if err != nil || pruneSlot == 0 {
+			return err
+		}
+
+		cnt, err := p.pruneInBatches(func(batchSize int) (int, error) {
+			return p.db.PruneProcessedTxs(pruneSlot, batchSize)
+		})
+		if err != nil {
+			return err
+		}
+
+		if cnt > 0 {
+			p.logger.Debug("Processed txs pruned", "cnt", cnt, "latest", latestPoint.BlockSlot)
+		}
+	}
+
+	return nil
+}
+
+// getProcessedTxsPruneSlot lowers prune slot so txs which are not yet processed by every subscriber are kept
+func (p *Pruner) getProcessedTxsPruneSlot(slot uint64) (uint64, error) {
+	subscribers, err := p.db.GetSubscribers()
+	if err != nil {
+		return 0, err
+	}
+
+	pruneSlot := slot
+
+	for _, subscriber := range subscribers {
+		if cursorSlot := subscriber.CursorSlot(); cursorSlot < slot {
+			p.logger.Warn("Subscriber cursor stalls pruning of processed txs, unregister subscriber if it is not used",
+				"subscriber", subscriber.Name, "cursor", cursorSlot, "prune", slot)
+
+			pruneSlot = min(pruneSlot, cursorSlot)
+		}
+	}
+
+	return pruneSlot, nil
+}
+
+func (p *Pruner) getBlockExpiredFn(latestPoint *BlockPoint) func(block *CardanoBlock) bool {
+	keepCnt, keepSlots := p.config.KeepBlocksCount, p.config.KeepBlocksSlots
+	if keepCnt == 0 && keepSlots == 0 {
+		return nil
+	}
+
+	return func(block *CardanoBlock) bool {
+		return (keepCnt > 0 && block.Number+keepCnt <= latestPoint.BlockNumber) ||
+			(keepSlots > 0 && block.Slot+keepSlots < latestPoint.BlockSlot)
+	}
+}
+
+func (p *Pruner) pruneInBatches(pruneFn func(batchSize int) (int, error)) (total int, err error) {
+	batchSize := p.config.BatchSize
+	if batchSize <= 0 {
+		batchSize = pruneBatchSizeDefault
+	}
+
+	for {
+		cnt, err := pruneFn(batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += cnt
+
+		if cnt < batchSize {
+			return total, nil
+		}
+
+		// other writers can acquire database lock between two batches
+		select {
+		case <-p.closeCh:
+			return total, nil
+		default:
+		}
+	}
+}
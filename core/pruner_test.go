@@ -0,0 +1,163 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruner_Prune(t *testing.T) {
+	t.Parallel()
+
+	latestPoint := &BlockPoint{
+		BlockSlot:   1000,
+		BlockNumber: 100,
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		dbMock := &DatabaseMock{}
+		dbMock.On("GetLatestBlockPoint").Return(latestPoint, error(nil)).Once()
+
+		require.NoError(t, NewPruner(&RetentionConfig{}, dbMock, hclog.NewNullLogger()).Prune())
+		dbMock.AssertExpectations(t)
+	})
+
+	t.Run("empty database", func(t *testing.T) {
+		t.Parallel()
+
+		dbMock := &DatabaseMock{}
+		dbMock.On("GetLatestBlockPoint").Return((*BlockPoint)(nil), error(nil)).Once()
+
+		require.NoError(t, NewPruner(&RetentionConfig{
+			KeepBlocksCount: 10,
+		}, dbMock, hclog.NewNullLogger()).Prune())
+		dbMock.AssertExpectations(t)
+	})
+
+	t.Run("blocks count", func(t *testing.T) {
+		t.Parallel()
+
+		dbMock := &DatabaseMock{}
+		dbMock.On("GetLatestBlockPoint").Return(latestPoint, error(nil)).Once()
+		dbMock.On("PruneConfirmedBlocks", mock.Anything, 5).Return(5, error(nil)).Twice()
+		dbMock.On("PruneConfirmedBlocks", mock.Anything, 5).Return(2, error(nil)).Once()
+
+		pruner := NewPruner(&RetentionConfig{
+			KeepBlocksCount: 10,
+			BatchSize:       5,
+		}, dbMock, hclog.NewNullLogger())
+
+		require.NoError(t, pruner.Prune())
+		dbMock.AssertExpectations(t)
+		dbMock.AssertNotCalled(t, "PruneProcessedTxs", mock.Anything, mock.Anything)
+
+		isExpired := pruner.getBlockExpiredFn(latestPoint)
+		require.True(t, isExpired(&CardanoBlock{Number: 90, Slot: 999}))
+		require.False(t, isExpired(&CardanoBlock{Number: 91, Slot: 10}))
+	})
+
+	t.Run("blocks slots and processed txs", func(t *testing.T) {
+		t.Parallel()
+
+		dbMock := &DatabaseMock{}
+		dbMock.On("GetLatestBlockPoint").Return(latestPoint, error(nil)).Once()
+		dbMock.On("PruneConfirmedBlocks", mock.Anything, pruneBatchSizeDefault).Return(3, error(nil)).Once()
+		dbMock.On("GetSubscribers").Return([]*Subscriber(nil), error(nil)).Once()
+		dbMock.On("PruneProcessedTxs", uint64(800), pruneBatchSizeDefault).Return(7, error(nil)).Once()
+
+		pruner := NewPruner(&RetentionConfig{
+			KeepBlocksSlots:       100,
+			KeepProcessedTxsSlots: 200,
+		}, dbMock, hclog.NewNullLogger())
+
+		require.NoError(t, pruner.Prune())
+		dbMock.AssertExpectations(t)
+
+		isExpired := pruner.getBlockExpiredFn(latestPoint)
+		require.True(t, isExpired(&CardanoBlock{Number: 99, Slot: 899}))
+		require.False(t, isExpired(&CardanoBlock{Number: 1, Slot: 900}))
+	})
+}
+
+func TestPruner_PruneWithSubscribers(t *testing.T) {
+	t.Parallel()
+
+	latestPoint := &BlockPoint{
+		BlockSlot:   1000,
+		BlockNumber: 100,
+	}
+	config := &RetentionConfig{
+		KeepProcessedTxsSlots: 200,
+	}
+
+	t.Run("lagging subscriber", func(t *testing.T) {
+		t.Parallel()
+
+		dbMock := &DatabaseMock{}
+		dbMock.On("GetLatestBlockPoint").Return(latestPoint, error(nil)).Once()
+		dbMock.On("GetSubscribers").Return([]*Subscriber{
+			NewSubscriber("first", 900),
+			{Name: "second", Cursor: Tx{BlockSlot: 650, Indx: 2}.Key()},
+		}, error(nil)).Once()
+		dbMock.On("PruneProcessedTxs", uint64(650), pruneBatchSizeDefault).Return(7, error(nil)).Once()
+
+		var logs bytes.Buffer
+
+		require.NoError(t, NewPruner(config, dbMock, hclog.New(&hclog.LoggerOptions{Output: &logs})).Prune())
+		dbMock.AssertExpectations(t)
+
+		// only subscriber which is behind the retention window is reported
+		require.Contains(t, logs.String(), "subscriber=second")
+		require.NotContains(t, logs.String(), "subscriber=first")
+	})
+
+	t.Run("subscriber without processed txs", func(t *testing.T) {
+		t.Parallel()
+
+		dbMock := &DatabaseMock{}
+		dbMock.On("GetLatestBlockPoint").Return(latestPoint, error(nil)).Once()
+		dbMock.On("GetSubscribers").Return([]*Subscriber{{Name: "first"}}, error(nil)).Once()
+
+		require.NoError(t, NewPruner(config, dbMock, hclog.NewNullLogger()).Prune())
+		dbMock.AssertExpectations(t)
+		dbMock.AssertNotCalled(t, "PruneProcessedTxs", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPruner_StartClose(t *testing.T) {
+	t.Parallel()
+
+	called := make(chan struct{}, 1)
+	dbMock := &DatabaseMock{}
+	dbMock.On("GetLatestBlockPoint").Return(&BlockPoint{BlockSlot: 10}, error(nil))
+	dbMock.On("GetSubscribers").Return([]*Subscriber(nil), error(nil))
+	dbMock.On("PruneProcessedTxs", uint64(5), 10).Run(func(args mock.Arguments) {
+		select {
+		case called <- struct{}{}:
+		default:
+		}
+	}).Return(0, error(nil))
+
+	pruner := NewPruner(&RetentionConfig{
+		KeepProcessedTxsSlots: 5,
+		BatchSize:             10,
+		PruneInterval:         time.Millisecond,
+	}, dbMock, hclog.NewNullLogger())
+
+	pruner.Start()
+
+	select {
+	case <-called:
+	case <-time.After(time.Second * 5):
+		t.Fatal("pruning has not been executed")
+	}
+
+	require.NoError(t, pruner.Close())
+	require.NoError(t, pruner.Close())
+}
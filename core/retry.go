@@ -0,0 +1,57 @@
+package core
+
+import (
+	"time"
+)
+
+const (
+	retryInitialBackoffDefault = time.Second
+	retryMaxBackoffDefault     = time.Minute
+)
+
+// RetryConfig is exponential backoff retry policy
+type RetryConfig struct {
+	// how many times operation is executed before giving up (at least once)
+	MaxAttempts int `json:"maxAttempts"`
+	// delay before the first retry. Every next delay is doubled
+	InitialBackoff time.Duration `json:"initialBackoff"`
+	// maximum delay between two attempts
+	MaxBackoff time.Duration `json:"maxBackoff"`
+}
+
+// Backoff returns delay after the failed attempt (attempts are counted from 1)
+func (rc RetryConfig) Backoff(attempt int) time.Duration {
+	backoff, maxBackoff := rc.InitialBackoff, rc.MaxBackoff
+	if backoff <= 0 {
+		backoff = retryInitialBackoffDefault
+	}
+
+	if maxBackoff <= 0 {
+		maxBackoff = retryMaxBackoffDefault
+	}
+
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maxBackoff)
+}
+
+// Retry executes fn until it succeeds, max attempts are reached or closeCh is closed (closeCh can be nil).
+// Returns number of attempts and the last error
+func Retry(config RetryConfig, closeCh <-chan struct{}, fn func() error) (int, error) {
+	maxAttempts := max(config.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= maxAttempts {
+			return attempt, err
+		}
+
+		select {
+		case <-closeCh:
+			return attempt, err
+		case <-time.After(config.Backoff(attempt)):
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryConfig_Backoff(t *testing.T) {
+	t.Parallel()
+
+	config := RetryConfig{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	require.Equal(t, time.Second, config.Backoff(1))
+	require.Equal(t, 2*time.Second, config.Backoff(2))
+	require.Equal(t, 4*time.Second, config.Backoff(3))
+	require.Equal(t, 5*time.Second, config.Backoff(4))
+	require.Equal(t, 5*time.Second, config.Backoff(100))
+
+	require.Equal(t, retryInitialBackoffDefault, RetryConfig{}.Backoff(1))
+	require.Equal(t, retryMaxBackoffDefault, RetryConfig{}.Backoff(100))
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test")
+	config := RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	attempts, err := Retry(config, nil, func() error {
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+	require.Equal(t, 3, attempts)
+
+	calls := 0
+
+	attempts, err = Retry(config, nil, func() error {
+		calls++
+		if calls == 2 {
+			return nil
+		}
+
+		return errTest
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+
+	// executed at least once
+	attempts, err = Retry(RetryConfig{}, nil, func() error {
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+	require.Equal(t, 1, attempts)
+
+	closeCh := make(chan struct{})
+	close(closeCh)
+
+	attempts, err = Retry(RetryConfig{MaxAttempts: 10, InitialBackoff: time.Hour}, closeCh, func() error {
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+	require.Equal(t, 1, attempts)
+}
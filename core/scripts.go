@@ -0,0 +1,97 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger/babbage"
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+type ScriptType uint
+
+const (
+	ScriptTypeNative   ScriptType = 0
+	ScriptTypePlutusV1 ScriptType = 1
+	ScriptTypePlutusV2 ScriptType = 2
+	ScriptTypePlutusV3 ScriptType = 3
+
+	// tag used for embedded cbor data items
+	cborTagEmbedded = 24
+)
+
+type Script struct {
+	Type ScriptType `json:"type"`
+	// blake2b-224 hash of the script (hex) which is used in script addresses and policy ids
+	Hash string `json:"hash"`
+	// native script cbor or serialized plutus script
+	Bytes []byte `json:"bytes"`
+}
+
+// NewScript creates script and calculates its hash
+func NewScript(scriptType ScriptType, bytes []byte) *Script {
+	prefixedBytes := make([]byte, 1+len(bytes))
+	prefixedBytes[0] = byte(scriptType)
+	copy(prefixedBytes[1:], bytes)
+
+	return &Script{
+		Type:  scriptType,
+		Hash:  ledgerCommon.Blake2b224Hash(prefixedBytes).String(),
+		Bytes: bytes,
+	}
+}
+
+// NewScriptFromScriptRef decodes script reference of the tx output: #6.24(bytes .cbor [type, script])
+func NewScriptFromScriptRef(scriptRef *cbor.Tag) (*Script, error) {
+	if scriptRef.Number != cborTagEmbedded {
+		return nil, fmt.Errorf("invalid script ref tag: %d", scriptRef.Number)
+	}
+
+	content, ok := scriptRef.Content.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("invalid script ref content: %T", scriptRef.Content)
+	}
+
+	var scriptRaw struct {
+		cbor.StructAsArray
+		Type   ScriptType
+		Script cbor.RawMessage
+	}
+
+	if _, err := cbor.Decode(content, &scriptRaw); err != nil {
+		return nil, fmt.Errorf("could not decode script ref: %w", err)
+	}
+
+	switch scriptRaw.Type {
+	case ScriptTypeNative:
+		return NewScript(scriptRaw.Type, scriptRaw.Script), nil
+	case ScriptTypePlutusV1, ScriptTypePlutusV2, ScriptTypePlutusV3:
+		var bytes []byte
+
+		if _, err := cbor.Decode(scriptRaw.Script, &bytes); err != nil {
+			return nil, fmt.Errorf("could not decode plutus script ref: %w", err)
+		}
+
+		return NewScript(scriptRaw.Type, bytes), nil
+	default:
+		return nil, fmt.Errorf("unknown script type: %d", scriptRaw.Type)
+	}
+}
+
+// getTxOutputScriptRef returns reference script of the output (available since babbage). nil if there is no script
+func getTxOutputScriptRef(txOut ledgerCommon.TransactionOutput) (*Script, error) {
+	var scriptRef *cbor.Tag
+
+	switch out := txOut.(type) {
+	case *babbage.BabbageTransactionOutput:
+		scriptRef = out.ScriptRef
+	case babbage.BabbageTransactionOutput:
+		scriptRef = out.ScriptRef
+	}
+
+	if scriptRef == nil {
+		return nil, nil
+	}
+
+	return NewScriptFromScriptRef(scriptRef)
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger/babbage"
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScriptFromScriptRef(t *testing.T) {
+	t.Parallel()
+
+	newScriptRef := func(t *testing.T, scriptType ScriptType, script interface{}) *cbor.Tag {
+		t.Helper()
+
+		content, err := cbor.Encode([]interface{}{scriptType, script})
+		require.NoError(t, err)
+
+		return &cbor.Tag{Number: cborTagEmbedded, Content: content}
+	}
+
+	t.Run("plutus", func(t *testing.T) {
+		plutusScript := []byte{0x46, 0x01, 0x00, 0x00, 0x22, 0x22, 0x01}
+
+		script, err := NewScriptFromScriptRef(newScriptRef(t, ScriptTypePlutusV2, plutusScript))
+
+		require.NoError(t, err)
+		require.Equal(t, ScriptTypePlutusV2, script.Type)
+		require.Equal(t, plutusScript, script.Bytes)
+		require.Equal(t,
+			ledgerCommon.Blake2b224Hash(append([]byte{byte(ScriptTypePlutusV2)}, plutusScript...)).String(), script.Hash)
+	})
+
+	t.Run("native", func(t *testing.T) {
+		// script which requires signature of key hash
+		nativeScript := []interface{}{0, make([]byte, 28)}
+		nativeScriptBytes, err := cbor.Encode(nativeScript)
+		require.NoError(t, err)
+
+		script, err := NewScriptFromScriptRef(newScriptRef(t, ScriptTypeNative, nativeScript))
+
+		require.NoError(t, err)
+		require.Equal(t, NewScript(ScriptTypeNative, nativeScriptBytes), script)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewScriptFromScriptRef(&cbor.Tag{Number: 25, Content: []byte{}})
+		require.ErrorContains(t, err, "invalid script ref tag")
+
+		_, err = NewScriptFromScriptRef(newScriptRef(t, ScriptType(9), []byte{1}))
+		require.ErrorContains(t, err, "unknown script type")
+	})
+
+	t.Run("tx output", func(t *testing.T) {
+		plutusScript := []byte{1, 2, 3}
+
+		script, err := getTxOutputScriptRef(&babbage.BabbageTransactionOutput{
+			ScriptRef: newScriptRef(t, ScriptTypePlutusV3, plutusScript),
+		})
+		require.NoError(t, err)
+		require.Equal(t, NewScript(ScriptTypePlutusV3, plutusScript), script)
+
+		script, err = getTxOutputScriptRef(&babbage.BabbageTransactionOutput{})
+		require.NoError(t, err)
+		require.Nil(t, script)
+
+		script, err = getTxOutputScriptRef(NewLedgerTransactionOutputMock(t, addresses[0], 1))
+		require.NoError(t, err)
+		require.Nil(t, script)
+	})
+}
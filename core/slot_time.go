@@ -0,0 +1,203 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	MainnetNetworkMagic = uint32(764824073)
+	PreprodNetworkMagic = uint32(1)
+	PreviewNetworkMagic = uint32(2)
+)
+
+// SlotTimeEra is a part of the chain history with the same slot length
+type SlotTimeEra struct {
+	StartSlot  uint64        `json:"startSlot"`
+	SlotLength time.Duration `json:"slotLength"`
+}
+
+// SlotTimeConfig describes the era history needed for slot to wall clock time conversion
+type SlotTimeConfig struct {
+	SystemStart time.Time `json:"systemStart"`
+	// eras ordered by start slot. The first one must start at slot 0
+	Eras []SlotTimeEra `json:"eras"`
+}
+
+// slotTimeEra is an era with precalculated start time
+type slotTimeEra struct {
+	SlotTimeEra
+	startTime time.Time
+}
+
+// SlotTimeConverter converts slot numbers into wall clock time and back
+type SlotTimeConverter struct {
+	eras []slotTimeEra
+}
+
+// NewSlotTimeConfigFromGenesis creates config from byron and shelley genesis parameters.
+// shelleyStartEpoch is the epoch of the byron to shelley hard fork (0 if chain starts in shelley)
+func NewSlotTimeConfigFromGenesis(
+	systemStart time.Time, byronSlotLength time.Duration, byronEpochLength uint64,
+	shelleyStartEpoch uint64, shelleySlotLength time.Duration,
+) *SlotTimeConfig {
+	config := &SlotTimeConfig{
+		SystemStart: systemStart.UTC(),
+	}
+
+	if shelleyStartEpoch > 0 {
+		config.Eras = append(config.Eras, SlotTimeEra{StartSlot: 0, SlotLength: byronSlotLength})
+	}
+
+	config.Eras = append(config.Eras, SlotTimeEra{
+		StartSlot:  shelleyStartEpoch * byronEpochLength,
+		SlotLength: shelleySlotLength,
+	})
+
+	return config
+}
+
+// GetSlotTimeConfig returns era history preset for mainnet, preprod or preview network
+func GetSlotTimeConfig(networkMagic uint32) (*SlotTimeConfig, error) {
+	switch networkMagic {
+	case MainnetNetworkMagic:
+		return NewSlotTimeConfigFromGenesis(
+			time.Date(2017, 9, 23, 21, 44, 51, 0, time.UTC), time.Second*20, 21600, 208, time.Second), nil
+	case PreprodNetworkMagic:
+		return NewSlotTimeConfigFromGenesis(
+			time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), time.Second*20, 21600, 4, time.Second), nil
+	case PreviewNetworkMagic:
+		return NewSlotTimeConfigFromGenesis(
+			time.Date(2022, 10, 25, 0, 0, 0, 0, time.UTC), time.Second*20, 4320, 0, time.Second), nil
+	default:
+		return nil, fmt.Errorf("no slot time preset for network magic %d", networkMagic)
+	}
+}
+
+// NewSlotTimeConverter validates era history and creates converter
+func NewSlotTimeConverter(config *SlotTimeConfig) (*SlotTimeConverter, error) {
+	if len(config.Eras) == 0 || config.Eras[0].StartSlot != 0 {
+		return nil, errors.New("the first era must start at slot 0")
+	}
+
+	eras := make([]slotTimeEra, len(config.Eras))
+	startTime := config.SystemStart
+
+	for i, era := range config.Eras {
+		if era.SlotLength <= 0 {
+			return nil, fmt.Errorf("invalid slot length of era %d: %s", i, era.SlotLength)
+		}
+
+		if i > 0 {
+			prev := eras[i-1]
+			if era.StartSlot <= prev.StartSlot {
+				return nil, fmt.Errorf("era %d does not start after the previous one", i)
+			}
+
+			startTime = prev.startTime.Add(time.Duration(era.StartSlot-prev.StartSlot) * prev.SlotLength) //nolint:gosec
+		}
+
+		eras[i] = slotTimeEra{
+			SlotTimeEra: era,
+			startTime:   startTime,
+		}
+	}
+
+	return &SlotTimeConverter{
+		eras: eras,
+	}, nil
+}
+
+// SlotToTime returns wall clock time (UTC) at the beginning of the slot
+func (c *SlotTimeConverter) SlotToTime(slot uint64) time.Time {
+	era := c.eras[0]
+
+	for _, x := range c.eras[1:] {
+		if x.StartSlot > slot {
+			break
+		}
+
+		era = x
+	}
+
+	return era.startTime.Add(time.Duration(slot-era.StartSlot) * era.SlotLength) //nolint:gosec
+}
+
+// TimeToSlot returns slot which contains the given time. Time before system start is mapped to slot 0
+func (c *SlotTimeConverter) TimeToSlot(t time.Time) uint64 {
+	era := c.eras[0]
+
+	for _, x := range c.eras[1:] {
+		if x.startTime.After(t) {
+			break
+		}
+
+		era = x
+	}
+
+	if t.Before(era.startTime) {
+		return 0
+	}
+
+	return era.StartSlot + uint64(t.Sub(era.startTime)/era.SlotLength)
+}
+
+// TimeRangeToSlotRange returns the first and the last slot which start inside [from, to] time range.
+// ok is false if no slot starts inside the range
+func (c *SlotTimeConverter) TimeRangeToSlotRange(from, to time.Time) (fromSlot uint64, toSlot uint64, ok bool) {
+	fromSlot = c.TimeToSlot(from)
+	if c.SlotToTime(fromSlot).Before(from) {
+		fromSlot++
+	}
+
+	toSlot = c.TimeToSlot(to)
+	if to.Before(c.SlotToTime(toSlot)) {
+		return 0, 0, false
+	}
+
+	return fromSlot, toSlot, fromSlot <= toSlot
+}
+
+// GetConfirmedBlocksInTimeRange returns confirmed blocks created inside [from, to] time range ordered by slot
+func GetConfirmedBlocksInTimeRange(
+	db SlotRangeRetriever, converter *SlotTimeConverter, from, to time.Time,
+) ([]*CardanoBlock, error) {
+	fromSlot, toSlot, ok := converter.TimeRangeToSlotRange(from, to)
+	if !ok {
+		return nil, nil
+	}
+
+	blocks, err := db.GetConfirmedBlocksInSlotRange(fromSlot, toSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		block.Timestamp = converter.SlotToTime(block.Slot).Unix()
+	}
+
+	return blocks, nil
+}
+
+// GetConfirmedTxsInTimeRange returns confirmed txs (processed and unprocessed) from blocks created
+// inside [from, to] time range ordered by slot and index in the block
+func GetConfirmedTxsInTimeRange(
+	db SlotRangeRetriever, converter *SlotTimeConverter, from, to time.Time,
+) ([]*Tx, error) {
+	fromSlot, toSlot, ok := converter.TimeRangeToSlotRange(from, to)
+	if !ok {
+		return nil, nil
+	}
+
+	txs, err := db.GetConfirmedTxsInSlotRange(fromSlot, toSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range txs {
+		tx.Timestamp = converter.SlotToTime(tx.BlockSlot).Unix()
+	}
+
+	return txs, nil
+}
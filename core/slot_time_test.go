@@ -0,0 +1,110 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlotTimeConverter_Presets(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		networkMagic uint32
+		slot         uint64
+		expected     time.Time
+	}{
+		{MainnetNetworkMagic, 0, time.Date(2017, 9, 23, 21, 44, 51, 0, time.UTC)},
+		{MainnetNetworkMagic, 1, time.Date(2017, 9, 23, 21, 45, 11, 0, time.UTC)},
+		{MainnetNetworkMagic, 4492800, time.Date(2020, 7, 29, 21, 44, 51, 0, time.UTC)},
+		{MainnetNetworkMagic, 100_000_000, time.Unix(1_691_566_291, 0).UTC()},
+		{PreprodNetworkMagic, 86400, time.Date(2022, 6, 21, 0, 0, 0, 0, time.UTC)},
+		{PreprodNetworkMagic, 74683550, time.Unix(74683550+1_655_683_200, 0).UTC()},
+		{PreviewNetworkMagic, 0, time.Date(2022, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{PreviewNetworkMagic, 50_000_000, time.Unix(50_000_000+1_666_656_000, 0).UTC()},
+	} {
+		config, err := GetSlotTimeConfig(tc.networkMagic)
+		require.NoError(t, err)
+
+		converter, err := NewSlotTimeConverter(config)
+		require.NoError(t, err)
+
+		require.Equal(t, tc.expected, converter.SlotToTime(tc.slot))
+		require.Equal(t, tc.slot, converter.TimeToSlot(tc.expected))
+		require.Equal(t, tc.slot, converter.TimeToSlot(tc.expected.Add(time.Millisecond*999)))
+	}
+
+	_, err := GetSlotTimeConfig(42)
+	require.Error(t, err)
+}
+
+func TestSlotTimeConverter_TimeRangeToSlotRange(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	converter, err := NewSlotTimeConverter(NewSlotTimeConfigFromGenesis(start, time.Second*20, 10, 1, time.Second))
+	require.NoError(t, err)
+
+	// byron slots 0-9 last 20 seconds, shelley starts at slot 10 at 200 seconds
+	require.Equal(t, start.Add(time.Second*200), converter.SlotToTime(10))
+	require.Equal(t, uint64(4), converter.TimeToSlot(start.Add(time.Second*99)))
+	require.Equal(t, uint64(0), converter.TimeToSlot(start.Add(-time.Hour)))
+
+	fromSlot, toSlot, ok := converter.TimeRangeToSlotRange(start.Add(time.Second*30), start.Add(time.Second*202))
+	require.True(t, ok)
+	require.Equal(t, uint64(2), fromSlot)
+	require.Equal(t, uint64(12), toSlot)
+
+	_, _, ok = converter.TimeRangeToSlotRange(start.Add(time.Second*21), start.Add(time.Second*39))
+	require.False(t, ok)
+
+	_, _, ok = converter.TimeRangeToSlotRange(start.Add(-time.Hour), start.Add(-time.Minute))
+	require.False(t, ok)
+}
+
+func TestNewSlotTimeConverter_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, config := range []*SlotTimeConfig{
+		{},
+		{Eras: []SlotTimeEra{{StartSlot: 1, SlotLength: time.Second}}},
+		{Eras: []SlotTimeEra{{StartSlot: 0}}},
+		{Eras: []SlotTimeEra{{StartSlot: 0, SlotLength: time.Second}, {StartSlot: 0, SlotLength: time.Second}}},
+	} {
+		_, err := NewSlotTimeConverter(config)
+		require.Error(t, err)
+	}
+}
+
+func TestGetConfirmedTxsInTimeRange(t *testing.T) {
+	t.Parallel()
+
+	config, err := GetSlotTimeConfig(PreviewNetworkMagic)
+	require.NoError(t, err)
+
+	converter, err := NewSlotTimeConverter(config)
+	require.NoError(t, err)
+
+	dbMock := &DatabaseMock{}
+	dbMock.On("GetConfirmedTxsInSlotRange", uint64(10), uint64(20)).Return([]*Tx{{BlockSlot: 15}}, nil)
+	dbMock.On("GetConfirmedBlocksInSlotRange", uint64(10), uint64(20)).
+		Return([]*CardanoBlock{{Slot: 20}}, nil)
+
+	from, to := config.SystemStart.Add(time.Second*10), config.SystemStart.Add(time.Millisecond*20500)
+
+	txs, err := GetConfirmedTxsInTimeRange(dbMock, converter, from, to)
+	require.NoError(t, err)
+	require.Equal(t, []*Tx{{BlockSlot: 15, Timestamp: config.SystemStart.Unix() + 15}}, txs)
+	require.Equal(t, config.SystemStart.Add(time.Second*15), txs[0].Time())
+
+	blocks, err := GetConfirmedBlocksInTimeRange(dbMock, converter, from, to)
+	require.NoError(t, err)
+	require.Equal(t, []*CardanoBlock{{Slot: 20, Timestamp: config.SystemStart.Unix() + 20}}, blocks)
+
+	txs, err = GetConfirmedTxsInTimeRange(dbMock, converter, to, from)
+	require.NoError(t, err)
+	require.Nil(t, txs)
+
+	dbMock.AssertExpectations(t)
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	// version 2 added the trailer to the leveldb payload
+	SnapshotVersion = 2
+
+	maxSnapshotHeaderSize = 1 << 16
+)
+
+// SnapshotHeader is written at the beginning of every database backup.
+// It is followed by the database specific payload
+type SnapshotHeader struct {
+	Version    int         `json:"version"`
+	Database   string      `json:"db"`
+	BlockPoint *BlockPoint `json:"point,omitempty"`
+}
+
+func WriteSnapshotHeader(w io.Writer, header SnapshotHeader) error {
+	bytes, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("could not marshal snapshot header: %w", err)
+	}
+
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(bytes)))
+
+	if _, err := w.Write(size); err != nil {
+		return fmt.Errorf("could not write snapshot header: %w", err)
+	}
+
+	if _, err := w.Write(bytes); err != nil {
+		return fmt.Errorf("could not write snapshot header: %w", err)
+	}
+
+	return nil
+}
+
+// ReadSnapshotHeader reads only the header so the reader is positioned at the beginning of the payload
+func ReadSnapshotHeader(r io.Reader) (SnapshotHeader, error) {
+	var (
+		header SnapshotHeader
+		size   = make([]byte, 4)
+	)
+
+	if _, err := io.ReadFull(r, size); err != nil {
+		return header, fmt.Errorf("could not read snapshot header: %w", err)
+	}
+
+	sizeVal := binary.BigEndian.Uint32(size)
+	if sizeVal > maxSnapshotHeaderSize {
+		return header, fmt.Errorf("invalid snapshot header size: %d", sizeVal)
+	}
+
+	bytes := make([]byte, sizeVal)
+	if _, err := io.ReadFull(r, bytes); err != nil {
+		return header, fmt.Errorf("could not read snapshot header: %w", err)
+	}
+
+	if err := json.Unmarshal(bytes, &header); err != nil {
+		return header, fmt.Errorf("could not unmarshal snapshot header: %w", err)
+	}
+
+	if header.Version != SnapshotVersion {
+		return header, fmt.Errorf("unsupported snapshot version: %d", header.Version)
+	}
+
+	return header, nil
+}
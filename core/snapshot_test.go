@@ -0,0 +1,41 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotHeader(t *testing.T) {
+	t.Parallel()
+
+	header := SnapshotHeader{
+		Version:  SnapshotVersion,
+		Database: "bbolt",
+		BlockPoint: &BlockPoint{
+			BlockSlot:   10,
+			BlockHash:   Hash{1, 2},
+			BlockNumber: 3,
+		},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, WriteSnapshotHeader(&buf, header))
+	buf.WriteString("payload")
+
+	result, err := ReadSnapshotHeader(&buf)
+	require.NoError(t, err)
+	require.Equal(t, header, result)
+	require.Equal(t, "payload", buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteSnapshotHeader(&buf, SnapshotHeader{Version: SnapshotVersion + 1}))
+
+	_, err = ReadSnapshotHeader(&buf)
+	require.ErrorContains(t, err, "unsupported snapshot version")
+
+	_, err = ReadSnapshotHeader(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff}))
+	require.ErrorContains(t, err, "invalid snapshot header size")
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrSubscriberNotFound = errors.New("subscriber is not registered")
+
+// Subscriber is a named consumer of confirmed txs with its own persisted cursor.
+// Subscribers read confirmed txs independently of the default processed/unprocessed txs queue
+type Subscriber struct {
+	Name string `json:"name"`
+	// key of the last processed tx (slot and index in the block). Empty if subscriber has not processed any tx
+	Cursor []byte `json:"cursor,omitempty"`
+}
+
+// SubscriptionsDB stores subscribers and their cursors
+type SubscriptionsDB interface {
+	SubscribersRetriever
+	// RegisterSubscriber creates subscriber which receives txs from slots >= fromSlot.
+	// Already registered subscriber is returned unchanged, so it is safe to register subscriber on every start
+	RegisterSubscriber(name string, fromSlot uint64) (*Subscriber, error)
+	// UnregisterSubscriber deletes subscriber and its cursor. Subscriber which is not used anymore must be
+	// unregistered, otherwise its cursor keeps processed txs from being pruned
+	UnregisterSubscriber(name string) error
+	// MarkSubscriberConfirmedTxsProcessed moves cursor of the subscriber to the tx with the greatest key
+	MarkSubscriberConfirmedTxsProcessed(name string, txs []*Tx) error
+}
+
+// Subscription exposes the same API as the default txs queue for one subscriber
+type Subscription struct {
+	db   SubscriptionsDB
+	name string
+}
+
+// NewSubscription registers subscriber (if not already registered) and returns its subscription
+func NewSubscription(db SubscriptionsDB, name string, fromSlot uint64) (*Subscription, error) {
+	if name == "" {
+		return nil, errors.New("subscriber name is empty")
+	}
+
+	if _, err := db.RegisterSubscriber(name, fromSlot); err != nil {
+		return nil, fmt.Errorf("could not register subscriber %s: %w", name, err)
+	}
+
+	return &Subscription{
+		db:   db,
+		name: name,
+	}, nil
+}
+
+func (s *Subscription) Name() string {
+	return s.name
+}
+
+// GetUnprocessedConfirmedTxs returns at most maxCnt (all if maxCnt is not positive) confirmed txs
+// after the subscriber cursor ordered by slot and index in the block
+func (s *Subscription) GetUnprocessedConfirmedTxs(maxCnt int) ([]*Tx, error) {
+	return s.db.GetSubscriberUnprocessedConfirmedTxs(s.name, maxCnt)
+}
+
+// MarkConfirmedTxsProcessed moves the subscriber cursor after the txs
+func (s *Subscription) MarkConfirmedTxsProcessed(txs []*Tx) error {
+	return s.db.MarkSubscriberConfirmedTxsProcessed(s.name, txs)
+}
+
+// NewSubscriber creates subscriber whose cursor is right before the first tx of the fromSlot
+func NewSubscriber(name string, fromSlot uint64) *Subscriber {
+	subscriber := &Subscriber{
+		Name: name,
+	}
+
+	if fromSlot > 0 {
+		subscriber.Cursor = Tx{BlockSlot: fromSlot - 1, Indx: math.MaxUint32}.Key()
+	}
+
+	return subscriber
+}
+
+func (s Subscriber) Key() []byte {
+	return []byte(s.Name)
+}
+
+// CursorSlot returns slot of the last processed tx. All txs from the lower slots are processed by the subscriber
+func (s Subscriber) CursorSlot() uint64 {
+	if len(s.Cursor) < 8 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(s.Cursor[:8])
+}
+
+// IsAfterCursor returns true if tx with the key is not yet processed by the subscriber
+func (s Subscriber) IsAfterCursor(txKey []byte) bool {
+	return bytes.Compare(txKey, s.Cursor) > 0
+}
+
+// MoveCursor moves cursor to the greatest key of the txs. Cursor is never moved backwards
+func (s *Subscriber) MoveCursor(txs []*Tx) {
+	for _, tx := range txs {
+		if key := tx.Key(); s.IsAfterCursor(key) {
+			s.Cursor = key
+		}
+	}
+}
+
+// SortAndLimitTxs sorts txs by slot and index in the block and keeps at most maxCnt (all if not positive) of them
+func SortAndLimitTxs(txs []*Tx, maxCnt int) []*Tx {
+	txs = SortTxs(txs)
+	if maxCnt > 0 && len(txs) > maxCnt {
+		txs = txs[:maxCnt]
+	}
+
+	return txs
+}
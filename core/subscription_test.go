@@ -0,0 +1,36 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriber(t *testing.T) {
+	t.Parallel()
+
+	subscriber := NewSubscriber("wallet", 0)
+	require.Nil(t, subscriber.Cursor)
+	require.Equal(t, uint64(0), subscriber.CursorSlot())
+	require.True(t, subscriber.IsAfterCursor(Tx{}.Key()))
+
+	subscriber = NewSubscriber("wallet", 100)
+	require.Equal(t, uint64(99), subscriber.CursorSlot())
+	require.False(t, subscriber.IsAfterCursor(Tx{BlockSlot: 99, Indx: 5}.Key()))
+	require.True(t, subscriber.IsAfterCursor(Tx{BlockSlot: 100}.Key()))
+
+	subscriber.MoveCursor([]*Tx{{BlockSlot: 120, Indx: 1}, {BlockSlot: 150}, {BlockSlot: 120, Indx: 3}})
+	require.Equal(t, Tx{BlockSlot: 150}.Key(), subscriber.Cursor)
+
+	subscriber.MoveCursor([]*Tx{{BlockSlot: 110}})
+	require.Equal(t, uint64(150), subscriber.CursorSlot())
+}
+
+func TestSortAndLimitTxs(t *testing.T) {
+	t.Parallel()
+
+	txs := []*Tx{{BlockSlot: 20}, {BlockSlot: 10, Indx: 1}, {BlockSlot: 10}}
+
+	require.Equal(t, []*Tx{{BlockSlot: 10}, {BlockSlot: 10, Indx: 1}}, SortAndLimitTxs(txs, 2))
+	require.Len(t, SortAndLimitTxs(txs, 0), 3)
+}
@@ -0,0 +1,213 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+)
+
+// TentativeBlock is unconfirmed block with its txs of interest processed in memory. It can still be rolled back
+type TentativeBlock struct {
+	Block *CardanoBlock `json:"block"`
+	Txs   []*Tx         `json:"txs"`
+	// number of blocks on top of this block including the block itself (tip has depth 1).
+	// Block is confirmed when there are ConfirmationBlockCount blocks after it
+	Depth uint `json:"depth"`
+}
+
+type TentativeTx struct {
+	Tx    *Tx  `json:"tx"`
+	Depth uint `json:"depth"`
+}
+
+// TentativeRollBackHandler is called with tentative blocks discarded by rollback or reset (ordered by slot)
+type TentativeRollBackHandler func(discarded []*TentativeBlock)
+
+type tentativeBlock struct {
+	block *CardanoBlock
+	txs   []*Tx
+	// all txs of the block are kept so they are not retrieved again when block is confirmed
+	allTxs []ledger.Transaction
+	// txs of the block by hash. Inputs of later unconfirmed blocks are resolved against their outputs
+	txsByHash map[Hash]ledger.Transaction
+}
+
+// SetTentativeRollBackHandler sets handler of discarded tentative blocks. Handler is called while indexer is locked
+func (bi *BlockIndexer) SetTentativeRollBackHandler(handler TentativeRollBackHandler) {
+	bi.mutex.Lock()
+	defer bi.mutex.Unlock()
+
+	bi.tentativeRollBackHandler = handler
+}
+
+// KeepsTentativeTxs returns true if txs of unconfirmed blocks are processed (KeepTentativeTxs is set)
+func (bi *BlockIndexer) KeepsTentativeTxs() bool {
+	return bi.config.KeepTentativeTxs
+}
+
+// GetTentativeBlocks returns unconfirmed blocks ordered by slot. KeepTentativeTxs must be set
+func (bi *BlockIndexer) GetTentativeBlocks() []*TentativeBlock {
+	bi.mutex.Lock()
+	defer bi.mutex.Unlock()
+
+	headers := bi.unconfirmedBlocks.ToList()
+	result := make([]*TentativeBlock, 0, len(headers))
+
+	for i, header := range headers {
+		if tb, exists := bi.tentativeBlocks[header.Hash()]; exists {
+			result = append(result, &TentativeBlock{
+				Block: tb.block,
+				Txs:   tb.txs,
+				Depth: uint(len(headers) - i),
+			})
+		}
+	}
+
+	return result
+}
+
+// GetTentativeTxs returns txs of interest of all unconfirmed blocks ordered by slot and index in the block
+func (bi *BlockIndexer) GetTentativeTxs() []*TentativeTx {
+	var result []*TentativeTx
+
+	for _, block := range bi.GetTentativeBlocks() {
+		for _, tx := range block.Txs {
+			result = append(result, &TentativeTx{
+				Tx:    tx,
+				Depth: block.Depth,
+			})
+		}
+	}
+
+	return result
+}
+
+// newTentativeBlock retrieves and processes txs of the new unconfirmed block.
+// Block is not added to the tentative blocks until its header is in the unconfirmed queue
+func (bi *BlockIndexer) newTentativeBlock(
+	header ledger.BlockHeader, txsRetriever BlockTxsRetriever,
+) (*tentativeBlock, error) {
+	var allTxs []ledger.Transaction
+
+	if !IsEpochBoundaryBlock(header) {
+		blockTxs, err := txsRetriever.GetBlockTransactions(header)
+		if err != nil {
+			return nil, err
+		}
+
+		allTxs = blockTxs
+	}
+
+	txsOfInterest, err := bi.filterTxsOfInterest(allTxs)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*Tx, len(txsOfInterest))
+	for i, ltx := range txsOfInterest {
+		txs[i], err = bi.createTx(header, ltx, uint32(i))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	txsHashes := getTxHashes(txsOfInterest)
+	if bi.config.KeepAllTxsHashesInBlock {
+		txsHashes = getTxHashes(allTxs)
+	}
+
+	txsByHash := make(map[Hash]ledger.Transaction, len(allTxs))
+	for _, tx := range allTxs {
+		txsByHash[NewHashFromHexString(tx.Hash())] = tx
+	}
+
+	block := NewCardanoBlock(header, txsHashes)
+	block.Timestamp = bi.getSlotTimestamp(header.SlotNumber())
+
+	return &tentativeBlock{
+		block:     block,
+		txs:       txs,
+		allTxs:    allTxs,
+		txsByHash: txsByHash,
+	}, nil
+}
+
+// addTentativeBlock adds processed block after its header is pushed to the unconfirmed queue
+func (bi *BlockIndexer) addTentativeBlock(header ledger.BlockHeader, tb *tentativeBlock) {
+	if tb == nil {
+		return
+	}
+
+	bi.tentativeBlocks[header.Hash()] = tb
+}
+
+// getTentativeBlockTxs returns all txs of the tentative block which is being confirmed
+func (bi *BlockIndexer) getTentativeBlockTxs(header ledger.BlockHeader) ([]ledger.Transaction, bool) {
+	tb, exists := bi.tentativeBlocks[header.Hash()]
+	if !exists {
+		return nil, false
+	}
+
+	return tb.allTxs, true
+}
+
+// getTentativeTxOutput returns output created by a tx of the tentative blocks.
+// Such output is not in the database yet because its block is not confirmed
+func (bi *BlockIndexer) getTentativeTxOutput(txInput TxInput) (TxOutput, bool) {
+	for _, tb := range bi.tentativeBlocks {
+		tx, exists := tb.txsByHash[txInput.Hash]
+		if !exists {
+			continue
+		}
+
+		outputs, firstIndex := getProducedOutputs(tx)
+		if txInput.Index < firstIndex || txInput.Index-firstIndex >= uint32(len(outputs)) { //nolint:gosec
+			return TxOutput{}, false
+		}
+
+		out := outputs[txInput.Index-firstIndex]
+
+		return createTxOutput(tb.block.Slot, LedgerAddressToString(out.Address()), out), true
+	}
+
+	return TxOutput{}, false
+}
+
+// discardTentativeBlocks removes tentative blocks which are no longer in the unconfirmed queue
+func (bi *BlockIndexer) discardTentativeBlocks() {
+	if len(bi.tentativeBlocks) == 0 {
+		return
+	}
+
+	headers := bi.unconfirmedBlocks.ToList()
+	unconfirmed := make(map[string]bool, len(headers))
+
+	for _, header := range headers {
+		unconfirmed[header.Hash()] = true
+	}
+
+	var discarded []*TentativeBlock
+
+	for hash, tb := range bi.tentativeBlocks {
+		if unconfirmed[hash] {
+			continue
+		}
+
+		delete(bi.tentativeBlocks, hash)
+
+		discarded = append(discarded, &TentativeBlock{
+			Block: tb.block,
+			Txs:   tb.txs,
+		})
+	}
+
+	if len(discarded) == 0 || bi.tentativeRollBackHandler == nil {
+		return
+	}
+
+	sort.Slice(discarded, func(i, j int) bool {
+		return discarded[i].Block.Slot < discarded[j].Block.Slot
+	})
+
+	bi.tentativeRollBackHandler(discarded)
+}
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockIndexer_TentativeBlocks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		retrieved []uint64
+		discarded []*TentativeBlock
+		confirmed []*Tx
+	)
+
+	getTxsMock := &BlockTxsRetrieverMock{
+		RetrieveFn: func(blockHeader ledger.BlockHeader) ([]ledger.Transaction, error) {
+			retrieved = append(retrieved, blockHeader.SlotNumber())
+
+			return []ledger.Transaction{
+				&LedgerTransactionMock{
+					HashVal: bytes2HashString([]byte{byte(blockHeader.SlotNumber())}),
+					OutputsVal: []ledger.TransactionOutput{
+						NewLedgerTransactionOutputMock(t, addresses[1], blockHeader.SlotNumber()*100),
+					},
+				},
+				&LedgerTransactionMock{
+					HashVal: "ff",
+					OutputsVal: []ledger.TransactionOutput{
+						NewLedgerTransactionOutputMock(t, addresses[2], 1),
+					},
+				},
+			}, nil
+		},
+	}
+	blockHeaders := []*LedgerBlockHeaderMock{
+		{SlotNumberVal: 1, HashVal: bytes2HashString([]byte{1})},
+		{SlotNumberVal: 2, HashVal: bytes2HashString([]byte{2})},
+		{SlotNumberVal: 3, HashVal: bytes2HashString([]byte{3})},
+		{SlotNumberVal: 4, HashVal: bytes2HashString([]byte{4})},
+	}
+	dbMock := &DatabaseMock{
+		Writter: &DBTransactionWriterMock{},
+	}
+	blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+		AddressCheck:           AddressCheckOutputs,
+		AddressesOfInterest:    []string{addresses[1]},
+		ConfirmationBlockCount: 2,
+		KeepTentativeTxs:       true,
+	}, func(cb *CardanoBlock, txs []*Tx) error {
+		confirmed = append(confirmed, txs...)
+
+		return nil
+	}, dbMock, hclog.NewNullLogger())
+
+	blockIndexer.SetTentativeRollBackHandler(func(blocks []*TentativeBlock) {
+		discarded = append(discarded, blocks...)
+	})
+
+	dbMock.On("GetLatestBlockPoint").Return((*BlockPoint)(nil), error(nil)).Once()
+
+	_, err := blockIndexer.Reset()
+	require.NoError(t, err)
+
+	require.NoError(t, blockIndexer.RollForwardFunc(blockHeaders[0], getTxsMock))
+	require.NoError(t, blockIndexer.RollForwardFunc(blockHeaders[1], getTxsMock))
+
+	tentativeBlocks := blockIndexer.GetTentativeBlocks()
+	require.Len(t, tentativeBlocks, 2)
+
+	for i, tb := range tentativeBlocks {
+		require.Equal(t, blockHeaders[i].SlotNumberVal, tb.Block.Slot)
+		require.Equal(t, uint(2-i), tb.Depth)
+		require.Len(t, tb.Txs, 1)
+		require.Equal(t, addresses[1], tb.Txs[0].Outputs[0].Address)
+		require.Equal(t, blockHeaders[i].SlotNumberVal*100, tb.Txs[0].Outputs[0].Amount)
+	}
+
+	tentativeTxs := blockIndexer.GetTentativeTxs()
+	require.Len(t, tentativeTxs, 2)
+	require.Equal(t, uint(2), tentativeTxs[0].Depth)
+	require.Equal(t, uint(1), tentativeTxs[1].Depth)
+
+	// the second block is rolled back
+	firstBlockHash := NewHashFromHexString(blockHeaders[0].HashVal)
+
+	require.NoError(t, blockIndexer.RollBackwardFunc(common.Point{
+		Slot: blockHeaders[0].SlotNumberVal, Hash: firstBlockHash[:],
+	}))
+	require.Len(t, discarded, 1)
+	require.Equal(t, tentativeBlocks[1].Block, discarded[0].Block)
+	require.Len(t, blockIndexer.GetTentativeBlocks(), 1)
+
+	// the first block is confirmed with already retrieved txs
+	dbMock.On("OpenTx").Once()
+	dbMock.Writter.On("Execute").Return(error(nil)).Once()
+	dbMock.Writter.On("AddTxOutputs", mock.Anything).Once()
+	dbMock.Writter.On("RemoveTxOutputs", []*TxInput(nil), false).Once()
+	dbMock.Writter.On("AddConfirmedTxs", mock.Anything).Once()
+	dbMock.Writter.On("AddConfirmedBlock", tentativeBlocks[0].Block).Once()
+	dbMock.Writter.On("SetLatestBlockPoint", mock.Anything).Once()
+
+	require.NoError(t, blockIndexer.RollForwardFunc(blockHeaders[2], getTxsMock))
+	require.NoError(t, blockIndexer.RollForwardFunc(blockHeaders[3], getTxsMock))
+	require.Equal(t, []uint64{1, 2, 3, 4}, retrieved)
+	require.Equal(t, tentativeBlocks[0].Txs, confirmed)
+
+	tentativeBlocks = blockIndexer.GetTentativeBlocks()
+	require.Len(t, tentativeBlocks, 2)
+	require.Equal(t, blockHeaders[2].SlotNumberVal, tentativeBlocks[0].Block.Slot)
+	require.Equal(t, blockHeaders[3].SlotNumberVal, tentativeBlocks[1].Block.Slot)
+
+	dbMock.AssertExpectations(t)
+	dbMock.Writter.AssertExpectations(t)
+}
+
+func TestBlockIndexer_TentativeBlocksChainedTxs(t *testing.T) {
+	t.Parallel()
+
+	parentTxHash := Hash{7}
+	blocksTxs := map[uint64][]ledger.Transaction{
+		1: {
+			&LedgerTransactionMock{
+				HashVal: parentTxHash.String(),
+				OutputsVal: []ledger.TransactionOutput{
+					NewLedgerTransactionOutputMock(t, addresses[1], 100),
+				},
+			},
+		},
+		// spends output of the unconfirmed parent tx, so it is of interest because of its input
+		2: {
+			&LedgerTransactionMock{
+				HashVal: Hash{8}.String(),
+				InputsVal: []ledger.TransactionInput{
+					NewLedgerTransactionInputMock(t, parentTxHash[:], 0),
+				},
+				OutputsVal: []ledger.TransactionOutput{
+					NewLedgerTransactionOutputMock(t, addresses[2], 90),
+				},
+			},
+		},
+	}
+	getTxsMock := &BlockTxsRetrieverMock{
+		RetrieveFn: func(blockHeader ledger.BlockHeader) ([]ledger.Transaction, error) {
+			return blocksTxs[blockHeader.SlotNumber()], nil
+		},
+	}
+	blockHeaders := []*LedgerBlockHeaderMock{
+		{SlotNumberVal: 1, HashVal: bytes2HashString([]byte{1})},
+		{SlotNumberVal: 2, HashVal: bytes2HashString([]byte{2})},
+		{SlotNumberVal: 3, HashVal: bytes2HashString([]byte{3})},
+	}
+	dbMock := &DatabaseMock{
+		Writter: &DBTransactionWriterMock{},
+	}
+	blockIndexer := NewBlockIndexer(&BlockIndexerConfig{
+		AddressCheck:           AddressCheckAll,
+		AddressesOfInterest:    []string{addresses[1]},
+		ConfirmationBlockCount: 2,
+		KeepTentativeTxs:       true,
+	}, func(cb *CardanoBlock, txs []*Tx) error {
+		return nil
+	}, dbMock, hclog.NewNullLogger())
+
+	dbMock.On("GetLatestBlockPoint").Return((*BlockPoint)(nil), error(nil)).Once()
+
+	_, err := blockIndexer.Reset()
+	require.NoError(t, err)
+
+	require.NoError(t, blockIndexer.RollForwardFunc(blockHeaders[0], getTxsMock))
+	require.NoError(t, blockIndexer.RollForwardFunc(blockHeaders[1], getTxsMock))
+
+	tentativeBlocks := blockIndexer.GetTentativeBlocks()
+	require.Len(t, tentativeBlocks, 2)
+	require.Len(t, tentativeBlocks[1].Txs, 1)
+	require.Equal(t, TxInput{Hash: parentTxHash}, tentativeBlocks[1].Txs[0].Inputs[0].Input)
+	require.Equal(t, addresses[1], tentativeBlocks[1].Txs[0].Inputs[0].Output.Address)
+	require.Equal(t, uint64(100), tentativeBlocks[1].Txs[0].Inputs[0].Output.Amount)
+
+	// saving of the confirmed block fails, so the new block is not added
+	dbMock.On("OpenTx").Once()
+	dbMock.Writter.On("AddTxOutputs", mock.Anything).Once()
+	dbMock.Writter.On("RemoveTxOutputs", mock.Anything, false).Once()
+	dbMock.Writter.On("AddConfirmedTxs", mock.Anything).Once()
+	dbMock.Writter.On("AddConfirmedBlock", mock.Anything).Once()
+	dbMock.Writter.On("SetLatestBlockPoint", mock.Anything).Once()
+	dbMock.Writter.On("Execute").Return(errors.New("db error")).Once()
+
+	require.ErrorContains(t, blockIndexer.RollForwardFunc(blockHeaders[2], getTxsMock), "db error")
+	require.Equal(t, tentativeBlocks, blockIndexer.GetTentativeBlocks())
+	require.Len(t, blockIndexer.tentativeBlocks, 2)
+
+	dbMock.AssertExpectations(t)
+	dbMock.Writter.AssertExpectations(t)
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+	"io"
 	"testing"
 
 	"github.com/blinklabs-io/gouroboros/cbor"
@@ -97,6 +99,11 @@ func (m *DatabaseMock) Init(filepath string) error {
 	return args.Error(0)
 }
 
+// InitReadOnly implements Database.
+func (m *DatabaseMock) InitReadOnly(filepath string) error {
+	return m.Called(filepath).Error(0)
+}
+
 // MarkConfirmedTxsProcessed implements Database.
 func (m *DatabaseMock) MarkConfirmedTxsProcessed(txs []*Tx) error {
 	return m.Called(txs).Error(0)
@@ -139,6 +146,117 @@ func (m *DatabaseMock) GetAllTxOutputs(address string, onlyNotUser bool) ([]*TxI
 	return args.Get(0).([]*TxInputOutput), args.Error(1)
 }
 
+func (m *DatabaseMock) PruneConfirmedBlocks(isExpired func(block *CardanoBlock) bool, maxCnt int) (int, error) {
+	args := m.Called(isExpired, maxCnt)
+
+	return args.Int(0), args.Error(1)
+}
+
+func (m *DatabaseMock) PruneProcessedTxs(slotNumber uint64, maxCnt int) (int, error) {
+	args := m.Called(slotNumber, maxCnt)
+
+	return args.Int(0), args.Error(1)
+}
+
+func (m *DatabaseMock) Backup(w io.Writer) error {
+	return m.Called(w).Error(0)
+}
+
+func (m *DatabaseMock) IterateTxOutputs(handler func(txInputOutput *TxInputOutput) error) error {
+	return m.Called(handler).Error(0)
+}
+
+func (m *DatabaseMock) IterateConfirmedBlocks(handler func(block *CardanoBlock) error) error {
+	return m.Called(handler).Error(0)
+}
+
+func (m *DatabaseMock) IterateConfirmedTxs(processed bool, handler func(tx *Tx) error) error {
+	return m.Called(processed, handler).Error(0)
+}
+
+func (m *DatabaseMock) IterateMintEvents(handler func(event *MintEvent) error) error {
+	return m.Called(handler).Error(0)
+}
+
+func (m *DatabaseMock) GetMintEvents(policyID string) ([]*MintEvent, error) {
+	args := m.Called(policyID)
+
+	//nolint:forcetypeassert
+	return args.Get(0).([]*MintEvent), args.Error(1)
+}
+
+func (m *DatabaseMock) GetConfirmedBlocksInSlotRange(fromSlot uint64, toSlot uint64) ([]*CardanoBlock, error) {
+	args := m.Called(fromSlot, toSlot)
+
+	//nolint:forcetypeassert
+	return args.Get(0).([]*CardanoBlock), args.Error(1)
+}
+
+func (m *DatabaseMock) GetConfirmedTxsInSlotRange(fromSlot uint64, toSlot uint64) ([]*Tx, error) {
+	args := m.Called(fromSlot, toSlot)
+
+	//nolint:forcetypeassert
+	return args.Get(0).([]*Tx), args.Error(1)
+}
+
+func (m *DatabaseMock) IterateGovProposals(handler func(event *GovProposalEvent) error) error {
+	return m.Called(handler).Error(0)
+}
+
+func (m *DatabaseMock) IterateGovVotes(handler func(event *GovVoteEvent) error) error {
+	return m.Called(handler).Error(0)
+}
+
+func (m *DatabaseMock) GetGovProposal(actionID GovActionID) (*GovProposalEvent, error) {
+	args := m.Called(actionID)
+
+	//nolint:forcetypeassert
+	return args.Get(0).(*GovProposalEvent), args.Error(1)
+}
+
+func (m *DatabaseMock) GetGovVotes(voter Voter) ([]*GovVoteEvent, error) {
+	args := m.Called(voter)
+
+	//nolint:forcetypeassert
+	return args.Get(0).([]*GovVoteEvent), args.Error(1)
+}
+
+func (m *DatabaseMock) GetSubscribers() ([]*Subscriber, error) {
+	args := m.Called()
+
+	//nolint:forcetypeassert
+	return args.Get(0).([]*Subscriber), args.Error(1)
+}
+
+func (m *DatabaseMock) GetSubscriberUnprocessedConfirmedTxs(name string, maxCnt int) ([]*Tx, error) {
+	args := m.Called(name, maxCnt)
+
+	//nolint:forcetypeassert
+	return args.Get(0).([]*Tx), args.Error(1)
+}
+
+func (m *DatabaseMock) RegisterSubscriber(name string, fromSlot uint64) (*Subscriber, error) {
+	args := m.Called(name, fromSlot)
+
+	//nolint:forcetypeassert
+	return args.Get(0).(*Subscriber), args.Error(1)
+}
+
+func (m *DatabaseMock) UnregisterSubscriber(name string) error {
+	return m.Called(name).Error(0)
+}
+
+func (m *DatabaseMock) MarkSubscriberConfirmedTxsProcessed(name string, txs []*Tx) error {
+	return m.Called(name, txs).Error(0)
+}
+
+func (m *DatabaseMock) GetDeadLetterTxs(maxCnt int) ([]*DeadLetterTx, error) {
+	args := m.Called(maxCnt)
+
+	//nolint:forcetypeassert
+	return args.Get(0).([]*DeadLetterTx), args.Error(1)
+}
+
 var _ Database = (*DatabaseMock)(nil)
 
 type DBTransactionWriterMock struct {
@@ -161,6 +279,36 @@ func (m *DBTransactionWriterMock) AddConfirmedTxs(txs []*Tx) DBTransactionWriter
 	return m
 }
 
+func (m *DBTransactionWriterMock) AddMintEvents(events []*MintEvent) DBTransactionWriter {
+	m.Called(events)
+
+	return m
+}
+
+func (m *DBTransactionWriterMock) AddGovProposals(events []*GovProposalEvent) DBTransactionWriter {
+	m.Called(events)
+
+	return m
+}
+
+func (m *DBTransactionWriterMock) AddGovVotes(events []*GovVoteEvent) DBTransactionWriter {
+	m.Called(events)
+
+	return m
+}
+
+func (m *DBTransactionWriterMock) DeleteConfirmedTxs(txs []*Tx) DBTransactionWriter {
+	m.Called(txs)
+
+	return m
+}
+
+func (m *DBTransactionWriterMock) MarkConfirmedTxsProcessed(txs []*Tx) DBTransactionWriter {
+	m.Called(txs)
+
+	return m
+}
+
 func (m *DBTransactionWriterMock) AddConfirmedBlock(block *CardanoBlock) DBTransactionWriter {
 	m.Called(block)
 
@@ -215,8 +363,42 @@ func (m *DBTransactionWriterMock) DeleteAllTxOutputsPhysically() DBTransactionWr
 	return m
 }
 
+func (m *DBTransactionWriterMock) SetSubscriber(subscriber *Subscriber) DBTransactionWriter {
+	m.Called(subscriber)
+
+	return m
+}
+
+func (m *DBTransactionWriterMock) AddDeadLetterTxs(txs []*DeadLetterTx) DBTransactionWriter {
+	m.Called(txs)
+
+	return m
+}
+
+func (m *DBTransactionWriterMock) DeleteDeadLetterTxs(txs []*DeadLetterTx) DBTransactionWriter {
+	m.Called(txs)
+
+	return m
+}
+
 var _ DBTransactionWriter = (*DBTransactionWriterMock)(nil)
 
+type BrokerPublisherMock struct {
+	mock.Mock
+}
+
+// Publish implements BrokerPublisher.
+func (m *BrokerPublisherMock) Publish(ctx context.Context, messages []*BrokerMessage) error {
+	return m.Called(messages).Error(0)
+}
+
+// Close implements BrokerPublisher.
+func (m *BrokerPublisherMock) Close() error {
+	return m.Called().Error(0)
+}
+
+var _ BrokerPublisher = (*BrokerPublisherMock)(nil)
+
 type LedgerBlockHeaderMock struct {
 	BlockNumberVal uint64
 	SlotNumberVal  uint64
@@ -317,19 +499,28 @@ func (m *LedgerBlockMock) Utxorpc() *utxorpc.Block {
 var _ ledger.Block = (*LedgerBlockMock)(nil)
 
 type LedgerTransactionMock struct {
-	FeeVal             uint64
-	HashVal            string
-	InputsVal          []ledger.TransactionInput
-	OutputsVal         []ledger.TransactionOutput
-	MetadataVal        *cbor.LazyValue
-	TTLVal             uint64
-	IsInvalid          bool
-	ReferenceInputsVal []ledger.TransactionInput
+	FeeVal              uint64
+	HashVal             string
+	InputsVal           []ledger.TransactionInput
+	OutputsVal          []ledger.TransactionOutput
+	MetadataVal         *cbor.LazyValue
+	TTLVal              uint64
+	IsInvalid           bool
+	ReferenceInputsVal  []ledger.TransactionInput
+	CertificatesVal     []common.Certificate
+	WithdrawalsVal      map[*common.Address]uint64
+	AssetMintVal        *common.MultiAsset[int64]
+	CollateralVal       []ledger.TransactionInput
+	CollateralReturnVal ledger.TransactionOutput
+	TotalCollateralVal  uint64
+
+	VotingProceduresVal   common.VotingProcedures
+	ProposalProceduresVal []common.ProposalProcedure
 }
 
 // AssetMint implements common.Transaction.
 func (m *LedgerTransactionMock) AssetMint() *common.MultiAsset[int64] {
-	panic("unimplemented") //nolint
+	return m.AssetMintVal
 }
 
 // AuxDataHash implements common.Transaction.
@@ -339,17 +530,17 @@ func (m *LedgerTransactionMock) AuxDataHash() *common.Blake2b256 {
 
 // Certificates implements common.Transaction.
 func (m *LedgerTransactionMock) Certificates() []common.Certificate {
-	panic("unimplemented") //nolint
+	return m.CertificatesVal
 }
 
 // Collateral implements common.Transaction.
 func (m *LedgerTransactionMock) Collateral() []common.TransactionInput {
-	panic("unimplemented") //nolint
+	return m.CollateralVal
 }
 
 // CollateralReturn implements common.Transaction.
 func (m *LedgerTransactionMock) CollateralReturn() common.TransactionOutput {
-	panic("unimplemented") //nolint
+	return m.CollateralReturnVal
 }
 
 // Consumed implements common.Transaction.
@@ -374,7 +565,7 @@ func (m *LedgerTransactionMock) Produced() []common.Utxo {
 
 // ProposalProcedures implements common.Transaction.
 func (m *LedgerTransactionMock) ProposalProcedures() []common.ProposalProcedure {
-	panic("unimplemented") //nolint
+	return m.ProposalProceduresVal
 }
 
 // ProtocolParameterUpdates implements common.Transaction.
@@ -396,7 +587,7 @@ func (m *LedgerTransactionMock) ScriptDataHash() *common.Blake2b256 {
 
 // TotalCollateral implements common.Transaction.
 func (m *LedgerTransactionMock) TotalCollateral() uint64 {
-	panic("unimplemented") //nolint
+	return m.TotalCollateralVal
 }
 
 // Type implements common.Transaction.
@@ -411,12 +602,12 @@ func (m *LedgerTransactionMock) ValidityIntervalStart() uint64 {
 
 // VotingProcedures implements common.Transaction.
 func (m *LedgerTransactionMock) VotingProcedures() common.VotingProcedures {
-	panic("unimplemented") //nolint
+	return m.VotingProceduresVal
 }
 
 // Withdrawals implements common.Transaction.
 func (m *LedgerTransactionMock) Withdrawals() map[*common.Address]uint64 {
-	panic("unimplemented") //nolint
+	return m.WithdrawalsVal
 }
 
 // Cbor implements ledger.Transaction.
@@ -0,0 +1,391 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+type VerifyIssueType string
+
+const (
+	VerifyIssueLatestBlockPoint VerifyIssueType = "latestBlockPoint"
+	VerifyIssueMissingBlock     VerifyIssueType = "missingBlock"
+	VerifyIssueTxNotInBlock     VerifyIssueType = "txNotInBlock"
+	VerifyIssueTxInBothQueues   VerifyIssueType = "txInBothQueues"
+	VerifyIssueTxOutputSlot     VerifyIssueType = "txOutputSlot"
+	VerifyIssueMintEventIndex   VerifyIssueType = "mintEventIndex"
+	VerifyIssueGovProposalIndex VerifyIssueType = "govProposalIndex"
+	VerifyIssueGovVoteIndex     VerifyIssueType = "govVoteIndex"
+)
+
+type VerifyIssue struct {
+	Type     VerifyIssueType `json:"type"`
+	Message  string          `json:"msg"`
+	Repaired bool            `json:"repaired"`
+}
+
+type VerifyReport struct {
+	Issues []*VerifyIssue `json:"issues"`
+}
+
+func (vr VerifyReport) IsValid() bool {
+	return len(vr.Issues) == 0
+}
+
+func (vr *VerifyReport) add(issueType VerifyIssueType, repaired bool, format string, args ...interface{}) {
+	vr.Issues = append(vr.Issues, &VerifyIssue{
+		Type:     issueType,
+		Message:  fmt.Sprintf(format, args...),
+		Repaired: repaired,
+	})
+}
+
+// VerifyDatabase checks consistency of the database:
+// latest block point must match the last confirmed block (or byron EBB after it), every confirmed tx must belong to a stored block,
+// stored block must contain the hash of the tx, tx outputs must not be newer than the latest block point
+// and mint events, governance proposals and votes of every confirmed tx must be indexed.
+// If repair is true mismatches which can be fixed safely are fixed.
+// Database must not be written to while verification is in progress
+func VerifyDatabase(db Database, repair bool) (*VerifyReport, error) {
+	report := &VerifyReport{}
+	dbTx := db.OpenTx()
+
+	latestPoint, err := verifyLatestBlockPoint(db, dbTx, report, repair)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyConfirmedTxs(db, dbTx, report, repair); err != nil {
+		return nil, err
+	}
+
+	if err := verifyEventIndexes(db, dbTx, report, repair); err != nil {
+		return nil, err
+	}
+
+	if latestPoint != nil {
+		if err := db.IterateTxOutputs(func(txInputOutput *TxInputOutput) error {
+			if txInputOutput.Output.Slot > latestPoint.BlockSlot {
+				report.add(VerifyIssueTxOutputSlot, false,
+					"tx output %s has slot %d which is after the latest block point slot %d",
+					txInputOutput.Input, txInputOutput.Output.Slot, latestPoint.BlockSlot)
+			}
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if repair {
+		if err := dbTx.Execute(); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func verifyLatestBlockPoint(
+	db Database, dbTx DBTransactionWriter, report *VerifyReport, repair bool,
+) (*BlockPoint, error) {
+	latestPoint, err := db.GetLatestBlockPoint()
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlocks, err := db.GetLatestConfirmedBlocks(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(latestBlocks) == 0 {
+		// genesis EBB is the only block which can be confirmed before any stored block
+		if latestPoint != nil && latestPoint.BlockNumber != 0 {
+			report.add(VerifyIssueLatestBlockPoint, false,
+				"latest block point (%s) exists but there are no confirmed blocks", latestPoint)
+		}
+
+		return latestPoint, nil
+	}
+
+	lastBlock := latestBlocks[0]
+	lastBlockPoint := &BlockPoint{
+		BlockSlot:   lastBlock.Slot,
+		BlockHash:   lastBlock.Hash,
+		BlockNumber: lastBlock.Number,
+	}
+
+	if latestPoint == nil || (*latestPoint != *lastBlockPoint && !isEpochBoundaryPoint(latestPoint, lastBlock)) {
+		report.add(VerifyIssueLatestBlockPoint, repair,
+			"latest block point (%v) does not match the last confirmed block (%s)", latestPoint, lastBlockPoint)
+
+		if repair {
+			dbTx.SetLatestBlockPoint(lastBlockPoint)
+
+			return lastBlockPoint, nil
+		}
+	}
+
+	return latestPoint, nil
+}
+
+// isEpochBoundaryPoint returns true if point can be byron EBB confirmed after the block.
+// EBBs are not stored, they have the same number as the block before them and the first slot of the epoch
+func isEpochBoundaryPoint(point *BlockPoint, block *CardanoBlock) bool {
+	return point.BlockNumber == block.Number && point.BlockSlot > block.Slot
+}
+
+func verifyConfirmedTxs(db Database, dbTx DBTransactionWriter, report *VerifyReport, repair bool) error {
+	oldestBlocks, err := db.GetConfirmedBlocksFrom(0, 1)
+	if err != nil {
+		return err
+	}
+
+	var (
+		txs             []*Tx
+		duplicateTxs    []*Tx
+		unprocessedKeys = map[string]bool{}
+	)
+
+	// only keys and hashes are collected so the database is not read while iterating
+	collectTx := func(tx *Tx) {
+		txs = append(txs, &Tx{
+			BlockSlot: tx.BlockSlot,
+			BlockHash: tx.BlockHash,
+			Indx:      tx.Indx,
+			Hash:      tx.Hash,
+		})
+	}
+
+	if err := db.IterateConfirmedTxs(false, func(tx *Tx) error {
+		collectTx(tx)
+		unprocessedKeys[string(tx.Key())] = true
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := db.IterateConfirmedTxs(true, func(tx *Tx) error {
+		collectTx(tx)
+
+		if unprocessedKeys[string(tx.Key())] {
+			report.add(VerifyIssueTxInBothQueues, repair, "tx %s is both processed and unprocessed", tx.Hash)
+
+			duplicateTxs = append(duplicateTxs, tx)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// marking already processed txs once again removes them from unprocessed txs
+	if repair && len(duplicateTxs) > 0 {
+		dbTx.MarkConfirmedTxsProcessed(duplicateTxs)
+	}
+
+	// txs of the same block must be next to each other
+	slices.SortFunc(txs, func(a, b *Tx) int {
+		return bytes.Compare(a.Key(), b.Key())
+	})
+
+	txs = slices.CompactFunc(txs, func(a, b *Tx) bool {
+		return a.BlockSlot == b.BlockSlot && a.Indx == b.Indx
+	})
+
+	var (
+		block           *CardanoBlock
+		orphanTxs       []*Tx
+		blocksToRepair  []*CardanoBlock
+		blockNeedRepair bool
+	)
+
+	for _, tx := range txs {
+		// blocks older than the oldest stored block could have been pruned
+		if len(oldestBlocks) > 0 && tx.BlockSlot < oldestBlocks[0].Slot {
+			continue
+		}
+
+		if block == nil || block.Slot != tx.BlockSlot {
+			if blockNeedRepair {
+				blocksToRepair = append(blocksToRepair, block)
+			}
+
+			blocks, err := db.GetConfirmedBlocksFrom(tx.BlockSlot, 1)
+			if err != nil {
+				return err
+			}
+
+			block, blockNeedRepair = nil, false
+
+			if len(blocks) > 0 && blocks[0].Slot == tx.BlockSlot {
+				block = blocks[0]
+			}
+		}
+
+		if block == nil || block.Hash != tx.BlockHash {
+			report.add(VerifyIssueMissingBlock, repair,
+				"tx %s refers to block (%d, %s) which is not stored", tx.Hash, tx.BlockSlot, tx.BlockHash)
+
+			orphanTxs = append(orphanTxs, tx)
+
+			continue
+		}
+
+		if !slices.Contains(block.Txs, tx.Hash) {
+			report.add(VerifyIssueTxNotInBlock, repair,
+				"tx %s is not in the tx list of block (%d, %s)", tx.Hash, block.Slot, block.Hash)
+
+			block.Txs = append(block.Txs, tx.Hash)
+			blockNeedRepair = true
+		}
+	}
+
+	if blockNeedRepair {
+		blocksToRepair = append(blocksToRepair, block)
+	}
+
+	if repair {
+		dbTx.DeleteConfirmedTxs(orphanTxs)
+
+		for _, block := range blocksToRepair {
+			dbTx.AddConfirmedBlock(block)
+		}
+	}
+
+	return nil
+}
+
+// verifyEventIndexes checks that events of every confirmed tx are indexed and match the tx.
+// Events of pruned txs stay in the indexes, so indexed events without a tx are not reported
+func verifyEventIndexes(db Database, dbTx DBTransactionWriter, report *VerifyReport, repair bool) error {
+	indexed := map[string]bool{}
+
+	addIndexed := func(prefix string, key []byte, event any) error {
+		bytes, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		indexed[prefix+string(key)+string(bytes)] = true
+
+		return nil
+	}
+
+	isIndexed := func(prefix string, key []byte, event any) (bool, error) {
+		bytes, err := json.Marshal(event)
+		if err != nil {
+			return false, err
+		}
+
+		return indexed[prefix+string(key)+string(bytes)], nil
+	}
+
+	if err := db.IterateMintEvents(func(event *MintEvent) error {
+		return addIndexed("m", event.Key(), event)
+	}); err != nil {
+		return err
+	}
+
+	if err := db.IterateGovProposals(func(event *GovProposalEvent) error {
+		return addIndexed("p", event.Key(), event)
+	}); err != nil {
+		return err
+	}
+
+	if err := db.IterateGovVotes(func(event *GovVoteEvent) error {
+		key, err := event.Key()
+		if err != nil {
+			return err
+		}
+
+		return addIndexed("v", key, event)
+	}); err != nil {
+		return err
+	}
+
+	var (
+		mintEvents []*MintEvent
+		proposals  []*GovProposalEvent
+		votes      []*GovVoteEvent
+		checkedTxs = map[string]bool{}
+	)
+
+	checkTx := func(tx *Tx) error {
+		// tx can be both processed and unprocessed
+		if checkedTxs[string(tx.Key())] {
+			return nil
+		}
+
+		checkedTxs[string(tx.Key())] = true
+
+		for _, event := range NewMintEvents([]*Tx{tx}) {
+			if ok, err := isIndexed("m", event.Key(), event); err != nil {
+				return err
+			} else if !ok {
+				report.add(VerifyIssueMintEventIndex, repair,
+					"mint event of tx %s (policy %s, name %s) is not indexed", tx.Hash, event.PolicyID, event.Name)
+
+				mintEvents = append(mintEvents, event)
+			}
+		}
+
+		txProposals, txVotes := NewGovEvents([]*Tx{tx})
+
+		for _, event := range txProposals {
+			if ok, err := isIndexed("p", event.Key(), event); err != nil {
+				return err
+			} else if !ok {
+				report.add(VerifyIssueGovProposalIndex, repair,
+					"proposal %s of tx %s is not indexed", event.Proposal.ActionID, tx.Hash)
+
+				proposals = append(proposals, event)
+			}
+		}
+
+		for _, event := range txVotes {
+			key, err := event.Key()
+			if err != nil {
+				return err
+			}
+
+			if ok, err := isIndexed("v", key, event); err != nil {
+				return err
+			} else if !ok {
+				report.add(VerifyIssueGovVoteIndex, repair,
+					"vote of tx %s for %s is not indexed", tx.Hash, event.Vote.ActionID)
+
+				votes = append(votes, event)
+			}
+		}
+
+		return nil
+	}
+
+	for _, processed := range []bool{false, true} {
+		if err := db.IterateConfirmedTxs(processed, checkTx); err != nil {
+			return err
+		}
+	}
+
+	// indexes are keyed by the event, so adding the events again overwrites mismatched entries
+	if repair {
+		if len(mintEvents) > 0 {
+			dbTx.AddMintEvents(mintEvents)
+		}
+
+		if len(proposals) > 0 {
+			dbTx.AddGovProposals(proposals)
+		}
+
+		if len(votes) > 0 {
+			dbTx.AddGovVotes(votes)
+		}
+	}
+
+	return nil
+}
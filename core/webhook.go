@@ -0,0 +1,303 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTxHashHeader    = "X-Webhook-Tx-Hash"
+
+	webhookTimeoutDefault       = 10 * time.Second
+	webhookPollIntervalDefault  = 5 * time.Second
+	webhookBatchSizeDefault     = 100
+	webhookSubscriberNamePrefix = "webhook:"
+)
+
+// WebhookSubscription receives HTTP POST for every confirmed tx which sends to its addresses
+// (or spends from them if MatchInputs is set)
+type WebhookSubscription struct {
+	// name of the subscriber whose persisted cursor tracks delivered txs. Default is "webhook:" + URL
+	Name string `json:"name"`
+	// txs are delivered from this slot when subscriber is registered for the first time
+	FromSlot uint64 `json:"fromSlot"`
+	URL      string `json:"url"`
+	// key of the HMAC-SHA256 signature of the request body. Request is not signed if secret is empty
+	Secret string `json:"secret"`
+	// all txs are posted if there are no addresses
+	Addresses []string `json:"addresses"`
+	// txs which spend from the addresses are posted as well
+	MatchInputs bool `json:"matchInputs"`
+}
+
+type WebhookConfig struct {
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+	// retry policy of one delivery. Tx is not marked as processed for the subscription until it is delivered
+	Retry RetryConfig `json:"retry"`
+	// timeout of one HTTP request
+	Timeout time.Duration `json:"timeout"`
+	// how often unprocessed confirmed txs are checked
+	PollInterval time.Duration `json:"pollInterval"`
+	// maximum number of txs read at once
+	BatchSize int `json:"batchSize"`
+}
+
+// WebhookPayload is JSON body of the webhook request
+type WebhookPayload struct {
+	// addresses of the subscription which are used in the tx
+	Addresses []string `json:"addresses"`
+	Tx        *Tx      `json:"tx"`
+}
+
+// ConfirmedTxsQueue is implemented by the database (default queue) and by Subscription
+type ConfirmedTxsQueue interface {
+	GetUnprocessedConfirmedTxs(maxCnt int) ([]*Tx, error)
+	MarkConfirmedTxsProcessed(txs []*Tx) error
+}
+
+// WebhookSink posts unprocessed confirmed txs to the webhook subscriptions.
+// Every webhook subscription has its own Subscription and is delivered concurrently,
+// so failing endpoint does not stop or delay delivery to the others.
+// Tx is marked as processed for the subscription only after endpoint responded with 2xx, so delivery is at least once
+type WebhookSink struct {
+	config *WebhookConfig
+	// queue of every webhook subscription (in the same order)
+	queues []*Subscription
+	client *http.Client
+	logger hclog.Logger
+
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewWebhookSink registers subscriber of every webhook subscription (if not already registered)
+func NewWebhookSink(config *WebhookConfig, db SubscriptionsDB, logger hclog.Logger) (*WebhookSink, error) {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = webhookTimeoutDefault
+	}
+
+	queues := make([]*Subscription, len(config.Subscriptions))
+
+	for i, subscription := range config.Subscriptions {
+		queue, err := NewSubscription(db, subscription.subscriberName(), subscription.FromSlot)
+		if err != nil {
+			return nil, err
+		}
+
+		queues[i] = queue
+	}
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+
+	return &WebhookSink{
+		config:    config,
+		queues:    queues,
+		client:    &http.Client{Timeout: timeout},
+		logger:    logger,
+		ctx:       ctx,
+		cancelCtx: cancelCtx,
+	}, nil
+}
+
+// Start delivers txs of every subscription periodically in its own routine until Close is called,
+// so retries of unreachable endpoint do not delay the other subscriptions
+func (ws *WebhookSink) Start() {
+	interval := ws.config.PollInterval
+	if interval <= 0 {
+		interval = webhookPollIntervalDefault
+	}
+
+	for i, subscription := range ws.config.Subscriptions {
+		ws.wg.Add(1)
+
+		go func(queue *Subscription) {
+			defer ws.wg.Done()
+
+			for {
+				cnt, err := ws.deliverSubscription(subscription, queue)
+				if err != nil && ws.ctx.Err() == nil {
+					ws.logger.Error("Error while delivering webhooks", "subscriber", queue.Name(), "err", err)
+				}
+
+				// full batch has been delivered, there may be more txs
+				if err == nil && cnt > 0 && cnt == ws.batchSize() {
+					continue
+				}
+
+				select {
+				case <-ws.ctx.Done():
+					return
+				case <-time.After(interval):
+				}
+			}
+		}(ws.queues[i])
+	}
+}
+
+func (ws *WebhookSink) Close() error {
+	ws.cancelCtx()
+	ws.wg.Wait()
+
+	return nil
+}
+
+// Deliver posts one batch of unprocessed confirmed txs of every subscription concurrently.
+// Returns the greatest number of txs processed by one subscription and errors of all failed subscriptions
+func (ws *WebhookSink) Deliver() (int, error) {
+	var (
+		wg     sync.WaitGroup
+		counts = make([]int, len(ws.queues))
+		errs   = make([]error, len(ws.queues))
+	)
+
+	for i, subscription := range ws.config.Subscriptions {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			counts[i], errs[i] = ws.deliverSubscription(subscription, ws.queues[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	return slices.Max(append(counts, 0)), errors.Join(errs...)
+}
+
+// deliverSubscription posts one batch of unprocessed confirmed txs of the subscription.
+// Delivery stops at the first tx which could not be delivered, so txs are always processed in order
+func (ws *WebhookSink) deliverSubscription(subscription WebhookSubscription, queue *Subscription) (int, error) {
+	txs, err := queue.GetUnprocessedConfirmedTxs(ws.batchSize())
+	if err != nil {
+		return 0, fmt.Errorf("could not get txs of %s: %w", queue.Name(), err)
+	}
+
+	for i, tx := range txs {
+		if addresses, ok := getWebhookAddresses(subscription, tx); ok {
+			attempts, err := Retry(ws.config.Retry, ws.ctx.Done(), func() error {
+				return ws.post(subscription, &WebhookPayload{Addresses: addresses, Tx: tx})
+			})
+			if err != nil {
+				return i, fmt.Errorf("could not deliver tx %s to %s after %d attempts: %w",
+					tx.Hash, subscription.URL, attempts, err)
+			}
+		}
+
+		if err := queue.MarkConfirmedTxsProcessed([]*Tx{tx}); err != nil {
+			return i, fmt.Errorf("could not mark tx %s as processed for %s: %w", tx.Hash, queue.Name(), err)
+		}
+	}
+
+	return len(txs), nil
+}
+
+func (ws *WebhookSink) post(subscription WebhookSubscription, payload *WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ws.ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookTxHashHeader, payload.Tx.Hash.String())
+
+	if subscription.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload([]byte(subscription.Secret), body))
+	}
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (ws *WebhookSink) batchSize() int {
+	if ws.config.BatchSize <= 0 {
+		return webhookBatchSizeDefault
+	}
+
+	return ws.config.BatchSize
+}
+
+// SignWebhookPayload returns hex encoded HMAC-SHA256 of the body prefixed with "sha256="
+func SignWebhookPayload(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks signature created by SignWebhookPayload in constant time
+func VerifyWebhookSignature(secret []byte, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(signature))
+}
+
+func (s WebhookSubscription) subscriberName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+
+	return webhookSubscriberNamePrefix + s.URL
+}
+
+// getWebhookAddresses returns addresses of the subscription used in outputs (and inputs if enabled) of the tx.
+// Collateral is used instead of inputs and outputs of phase-2 invalid tx
+func getWebhookAddresses(subscription WebhookSubscription, tx *Tx) ([]string, bool) {
+	if len(subscription.Addresses) == 0 {
+		return nil, true
+	}
+
+	inputs, outputs := tx.ConsumedInputs(), tx.ProducedOutputs()
+	used := make(map[string]bool, len(inputs)+len(outputs))
+
+	if subscription.MatchInputs {
+		for _, input := range inputs {
+			used[input.Output.Address] = true
+		}
+	}
+
+	for _, output := range outputs {
+		used[output.Address] = true
+	}
+
+	var result []string
+
+	for _, addr := range subscription.Addresses {
+		if used[addr] {
+			result = append(result, addr)
+		}
+	}
+
+	return result, len(result) > 0
+}
@@ -0,0 +1,235 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSink_Deliver(t *testing.T) {
+	t.Parallel()
+
+	const secret = "secret"
+
+	var (
+		lock     sync.Mutex
+		payloads []WebhookPayload
+		calls    int
+		failed   bool
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.True(t, VerifyWebhookSignature([]byte(secret), body, r.Header.Get(WebhookSignatureHeader)))
+
+		// the first request of the first subscription fails
+		calls++
+		if r.URL.Path == "/" && !failed {
+			failed = true
+
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		var payload WebhookPayload
+
+		require.NoError(t, json.Unmarshal(body, &payload))
+		require.Equal(t, payload.Tx.Hash.String(), r.Header.Get(WebhookTxHashHeader))
+
+		payloads = append(payloads, payload)
+	}))
+	defer server.Close()
+
+	txs := []*Tx{
+		{BlockSlot: 10, Hash: Hash{1}, Valid: true, Outputs: []*TxOutput{{Address: addresses[0], Amount: 100}}},
+		{BlockSlot: 11, Hash: Hash{2}, Valid: true, Outputs: []*TxOutput{{Address: addresses[2], Amount: 100}}},
+		{BlockSlot: 12, Hash: Hash{3}, Valid: true, Inputs: []*TxInputOutput{{Output: TxOutput{Address: addresses[1]}}}},
+	}
+	dbMock := &DatabaseMock{}
+
+	dbMock.On("RegisterSubscriber", "outputs", uint64(10)).Return(&Subscriber{Name: "outputs"}, error(nil)).Once()
+	dbMock.On("RegisterSubscriber", webhookSubscriberNamePrefix+server.URL+"/inputs", uint64(0)).
+		Return(&Subscriber{}, error(nil)).Once()
+
+	sink, err := NewWebhookSink(&WebhookConfig{
+		Subscriptions: []WebhookSubscription{
+			{Name: "outputs", FromSlot: 10, URL: server.URL, Secret: secret, Addresses: addresses[:2]},
+			{URL: server.URL + "/inputs", Secret: secret, Addresses: addresses[1:2], MatchInputs: true},
+		},
+		Retry: RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	}, dbMock, hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	defer sink.Close()
+
+	inputsName := webhookSubscriberNamePrefix + server.URL + "/inputs"
+
+	dbMock.On("GetSubscriberUnprocessedConfirmedTxs", "outputs", webhookBatchSizeDefault).
+		Return(txs, error(nil)).Once()
+	dbMock.On("GetSubscriberUnprocessedConfirmedTxs", inputsName, webhookBatchSizeDefault).
+		Return(txs[1:], error(nil)).Once()
+
+	for _, tx := range txs {
+		dbMock.On("MarkSubscriberConfirmedTxsProcessed", "outputs", []*Tx{tx}).Return(error(nil)).Once()
+	}
+
+	for _, tx := range txs[1:] {
+		dbMock.On("MarkSubscriberConfirmedTxsProcessed", inputsName, []*Tx{tx}).Return(error(nil)).Once()
+	}
+
+	cnt, err := sink.Deliver()
+	require.NoError(t, err)
+	require.Equal(t, 3, cnt)
+	require.Equal(t, 3, calls)
+	// input address is matched only by the subscription which opted in. Subscriptions are delivered concurrently
+	require.ElementsMatch(t, []WebhookPayload{
+		{Addresses: addresses[:1], Tx: txs[0]},
+		{Addresses: addresses[1:2], Tx: txs[2]},
+	}, payloads)
+	dbMock.AssertExpectations(t)
+}
+
+func TestWebhookSink_DeliverFailed(t *testing.T) {
+	t.Parallel()
+
+	var delivered []Hash
+
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failingServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered = append(delivered, NewHashFromHexString(r.Header.Get(WebhookTxHashHeader)))
+	}))
+	defer server.Close()
+
+	txs := []*Tx{{BlockSlot: 10, Hash: Hash{1}}}
+	dbMock := &DatabaseMock{}
+
+	dbMock.On("RegisterSubscriber", "failing", uint64(0)).Return(&Subscriber{}, error(nil)).Once()
+	dbMock.On("RegisterSubscriber", "working", uint64(0)).Return(&Subscriber{}, error(nil)).Once()
+
+	sink, err := NewWebhookSink(&WebhookConfig{
+		Subscriptions: []WebhookSubscription{
+			{Name: "failing", URL: failingServer.URL},
+			{Name: "working", URL: server.URL},
+		},
+		Retry:     RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+		BatchSize: 1,
+	}, dbMock, hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	defer sink.Close()
+
+	dbMock.On("GetSubscriberUnprocessedConfirmedTxs", "failing", 1).Return(txs, error(nil)).Once()
+	dbMock.On("GetSubscriberUnprocessedConfirmedTxs", "working", 1).Return(txs, error(nil)).Once()
+	dbMock.On("MarkSubscriberConfirmedTxsProcessed", "working", txs).Return(error(nil)).Once()
+
+	// failing endpoint does not stop delivery to the other one
+	cnt, err := sink.Deliver()
+	require.ErrorContains(t, err, "after 3 attempts: unexpected status code: 500")
+	require.Equal(t, 1, cnt)
+	require.Equal(t, []Hash{txs[0].Hash}, delivered)
+	dbMock.AssertExpectations(t)
+	dbMock.AssertNotCalled(t, "MarkSubscriberConfirmedTxsProcessed", "failing", mock.Anything)
+
+	// subscriber could not be registered
+	dbMock.On("RegisterSubscriber", "failing", uint64(0)).Return((*Subscriber)(nil), errors.New("db error")).Once()
+
+	_, err = NewWebhookSink(&WebhookConfig{
+		Subscriptions: []WebhookSubscription{{Name: "failing", URL: failingServer.URL}},
+	}, dbMock, hclog.NewNullLogger())
+	require.ErrorContains(t, err, "db error")
+}
+
+func TestGetWebhookAddresses_InvalidTx(t *testing.T) {
+	t.Parallel()
+
+	subscription := WebhookSubscription{Addresses: addresses[:3], MatchInputs: true}
+	tx := &Tx{
+		Valid:            false,
+		Inputs:           []*TxInputOutput{{Output: TxOutput{Address: addresses[0]}}},
+		Outputs:          []*TxOutput{{Address: addresses[0]}},
+		CollateralInputs: []*TxInputOutput{{Output: TxOutput{Address: addresses[1]}}},
+	}
+
+	// regular inputs and outputs of invalid tx are not used on chain
+	result, ok := getWebhookAddresses(subscription, tx)
+	require.True(t, ok)
+	require.Equal(t, addresses[1:2], result)
+
+	tx.CollateralReturn = &TxOutput{Address: addresses[2]}
+
+	result, ok = getWebhookAddresses(WebhookSubscription{Addresses: addresses[:3]}, tx)
+	require.True(t, ok)
+	require.Equal(t, addresses[2:3], result)
+
+	tx.CollateralInputs = nil
+	tx.CollateralReturn = nil
+
+	_, ok = getWebhookAddresses(subscription, tx)
+	require.False(t, ok)
+}
+
+func TestWebhookSink_StartUnreachableEndpoint(t *testing.T) {
+	t.Parallel()
+
+	deliveredCh := make(chan Hash, 1)
+
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failingServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deliveredCh <- NewHashFromHexString(r.Header.Get(WebhookTxHashHeader))
+	}))
+	defer server.Close()
+
+	txs := []*Tx{{BlockSlot: 10, Hash: Hash{1}}}
+	dbMock := &DatabaseMock{}
+
+	dbMock.On("RegisterSubscriber", "failing", uint64(0)).Return(&Subscriber{}, error(nil)).Once()
+	dbMock.On("RegisterSubscriber", "working", uint64(0)).Return(&Subscriber{}, error(nil)).Once()
+	dbMock.On("GetSubscriberUnprocessedConfirmedTxs", "failing", webhookBatchSizeDefault).Return(txs, error(nil))
+	dbMock.On("GetSubscriberUnprocessedConfirmedTxs", "working", webhookBatchSizeDefault).Return(txs, error(nil)).Once()
+	dbMock.On("GetSubscriberUnprocessedConfirmedTxs", "working", webhookBatchSizeDefault).Return([]*Tx(nil), error(nil))
+	dbMock.On("MarkSubscriberConfirmedTxsProcessed", "working", txs).Return(error(nil)).Once()
+
+	sink, err := NewWebhookSink(&WebhookConfig{
+		Subscriptions: []WebhookSubscription{
+			{Name: "failing", URL: failingServer.URL},
+			{Name: "working", URL: server.URL},
+		},
+		// the failing endpoint is retried until the sink is closed
+		Retry: RetryConfig{MaxAttempts: 10, InitialBackoff: time.Hour},
+	}, dbMock, hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	sink.Start()
+
+	select {
+	case hash := <-deliveredCh:
+		require.Equal(t, txs[0].Hash, hash)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "delivery is blocked by the failing endpoint")
+	}
+
+	require.NoError(t, sink.Close())
+	dbMock.AssertNotCalled(t, "MarkSubscriberConfirmedTxsProcessed", "failing", mock.Anything)
+}
@@ -0,0 +1,453 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	webSocketBufferSizeDefault     = 1024
+	webSocketWriteTimeoutDefault   = 10 * time.Second
+	webSocketMaxMessageSizeDefault = 64 * 1024
+	webSocketReplayBatchSize       = 100
+
+	// EventError is sent to websocket client when its request is invalid or resume failed
+	EventError EventType = "error"
+)
+
+var errWebSocketClosed = errors.New("websocket connection is closed")
+
+type WebSocketConfig struct {
+	// how many events are buffered per client. Client which falls behind is disconnected
+	// and it can reconnect with fromSlot
+	BufferSize int `json:"bufferSize"`
+	// timeout of writing one message to the client
+	WriteTimeout time.Duration `json:"writeTimeout"`
+	// maximum size of the client request
+	MaxMessageSize int `json:"maxMessageSize"`
+}
+
+// WebSocketRequest is sent by client to (re)subscribe. Every request replaces previous subscription
+type WebSocketRequest struct {
+	// receive all confirmed blocks
+	Blocks bool `json:"blocks"`
+	// receive tip updates and rollbacks
+	Tip bool `json:"tip"`
+	// receive confirmed txs which spend from or send to these addresses
+	Addresses []string `json:"addresses"`
+	// receive confirmed txs which mint, burn or transfer these assets.
+	// Asset is either policy id (all assets of the policy) or policy id followed by hex asset name
+	Assets []string `json:"assets"`
+	// if set, confirmed blocks and txs from this slot are sent before the live events
+	FromSlot *uint64 `json:"fromSlot"`
+}
+
+type WebSocketDB interface {
+	GetConfirmedBlocksFrom(slotNumber uint64, maxCnt int) ([]*CardanoBlock, error)
+	GetConfirmedTxsInSlotRange(fromSlot uint64, toSlot uint64) ([]*Tx, error)
+}
+
+type webSocketError struct {
+	Type  EventType `json:"type"`
+	Error string    `json:"error"`
+}
+
+// WebSocketServer streams events of the event bus to websocket clients as JSON messages
+type WebSocketServer struct {
+	config   WebSocketConfig
+	db       WebSocketDB
+	events   <-chan Event
+	upgrader websocket.Upgrader
+	logger   hclog.Logger
+
+	clients  map[*webSocketClient]bool
+	lock     sync.Mutex
+	isClosed bool
+}
+
+var _ http.Handler = (*WebSocketServer)(nil)
+
+// NewWebSocketServer creates server which streams events (usually channel of EventBus.Subscribe)
+func NewWebSocketServer(
+	config WebSocketConfig, db WebSocketDB, events <-chan Event, logger hclog.Logger,
+) *WebSocketServer {
+	if config.BufferSize <= 0 {
+		config.BufferSize = webSocketBufferSizeDefault
+	}
+
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = webSocketWriteTimeoutDefault
+	}
+
+	if config.MaxMessageSize <= 0 {
+		config.MaxMessageSize = webSocketMaxMessageSizeDefault
+	}
+
+	return &WebSocketServer{
+		config: config,
+		db:     db,
+		events: events,
+		upgrader: websocket.Upgrader{
+			// events are public and the connection carries no credentials, so clients of any origin are accepted
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+		logger:  logger,
+		clients: map[*webSocketClient]bool{},
+	}
+}
+
+// Start dispatches events to clients in a separate routine until events channel is closed
+func (s *WebSocketServer) Start() {
+	go func() {
+		for event := range s.events {
+			s.dispatch(event)
+		}
+	}()
+}
+
+// Close disconnects all clients. Events channel should be closed by its owner
+func (s *WebSocketServer) Close() error {
+	s.lock.Lock()
+	s.isClosed = true
+
+	clients := make([]*webSocketClient, 0, len(s.clients))
+	for client := range s.clients {
+		clients = append(clients, client)
+	}
+
+	s.lock.Unlock()
+
+	for _, client := range clients {
+		client.close()
+	}
+
+	return nil
+}
+
+// ServeHTTP upgrades request to websocket connection and serves the client until it disconnects.
+// Unmasked client frames, unknown opcodes and too large messages are rejected by the websocket library
+func (s *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Debug("Websocket upgrade failed", "remote", r.RemoteAddr, "err", err)
+
+		return
+	}
+
+	conn.SetReadLimit(int64(s.config.MaxMessageSize))
+
+	client := &webSocketClient{
+		conn:         conn,
+		writeTimeout: s.config.WriteTimeout,
+		events:       make(chan Event, s.config.BufferSize),
+		requestCh:    make(chan *WebSocketRequest, 1),
+		closeCh:      make(chan struct{}),
+	}
+
+	s.lock.Lock()
+	if s.isClosed {
+		s.lock.Unlock()
+
+		_ = conn.Close()
+
+		return
+	}
+
+	s.clients[client] = true
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.clients, client)
+		s.lock.Unlock()
+
+		client.close()
+	}()
+
+	go s.readRequests(client)
+
+	if err := s.writeEvents(client); err != nil {
+		s.logger.Debug("Websocket client disconnected", "remote", r.RemoteAddr, "err", err)
+	}
+}
+
+func (s *WebSocketServer) dispatch(event Event) {
+	var slowClients []*webSocketClient
+
+	s.lock.Lock()
+
+	for client := range s.clients {
+		if !client.isInterested(event) {
+			continue
+		}
+
+		select {
+		case client.events <- event:
+		default:
+			slowClients = append(slowClients, client)
+		}
+	}
+
+	s.lock.Unlock()
+
+	// slow client is evicted outside of the lock so it never blocks other clients
+	for _, client := range slowClients {
+		s.logger.Warn("Websocket client is too slow, disconnecting", "remote", client.conn.RemoteAddr())
+
+		client.evict()
+	}
+}
+
+func (s *WebSocketServer) readRequests(client *webSocketClient) {
+	defer client.close()
+
+	for {
+		_, data, err := client.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var request *WebSocketRequest
+
+		if err := json.Unmarshal(data, &request); err != nil || request == nil {
+			_ = client.writeJSON(webSocketError{Type: EventError, Error: "invalid request"})
+
+			continue
+		}
+
+		// the latest request replaces the pending one
+		select {
+		case <-client.requestCh:
+		default:
+		}
+
+		client.requestCh <- request
+	}
+}
+
+func (s *WebSocketServer) writeEvents(client *webSocketClient) error {
+	// slot of the last replayed block. Live confirmed events which are already replayed are skipped
+	var replayedSlot *uint64
+
+	for {
+		select {
+		case <-client.closeCh:
+			return errWebSocketClosed
+		case request := <-client.requestCh:
+			client.setRequest(request)
+
+			replayedSlot = nil
+
+			if request.FromSlot != nil {
+				slot, err := s.replay(client, *request.FromSlot)
+				if err != nil {
+					_ = client.writeJSON(webSocketError{Type: EventError, Error: "resume failed"})
+
+					return err
+				}
+
+				replayedSlot = slot
+			}
+		case event := <-client.events:
+			if replayedSlot != nil && isReplayedEvent(event, *replayedSlot) {
+				continue
+			}
+
+			if err := client.writeJSON(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// replay sends confirmed blocks and txs from the slot and returns slot of the last sent block
+func (s *WebSocketServer) replay(client *webSocketClient, slot uint64) (*uint64, error) {
+	var lastSlot *uint64
+
+	for {
+		blocks, err := s.db.GetConfirmedBlocksFrom(slot, webSocketReplayBatchSize)
+		if err != nil || len(blocks) == 0 {
+			return lastSlot, err
+		}
+
+		txs, err := s.db.GetConfirmedTxsInSlotRange(blocks[0].Slot, blocks[len(blocks)-1].Slot)
+		if err != nil {
+			return lastSlot, err
+		}
+
+		for _, block := range blocks {
+			events := []Event{{Type: EventBlockConfirmed, Block: block}}
+
+			for ; len(txs) > 0 && txs[0].BlockSlot <= block.Slot; txs = txs[1:] {
+				events = append(events, Event{Type: EventTxConfirmed, Tx: txs[0]})
+			}
+
+			for _, event := range events {
+				if !client.isInterested(event) {
+					continue
+				}
+
+				if err := client.writeJSON(event); err != nil {
+					return lastSlot, err
+				}
+			}
+
+			lastSlot = &block.Slot
+		}
+
+		if len(blocks) < webSocketReplayBatchSize {
+			return lastSlot, nil
+		}
+
+		slot = *lastSlot + 1
+	}
+}
+
+type webSocketClient struct {
+	conn         *websocket.Conn
+	writeTimeout time.Duration
+	// websocket connection supports one concurrent writer
+	writeLock sync.Mutex
+	events    chan Event
+	requestCh chan *WebSocketRequest
+	closeCh   chan struct{}
+	closeOnce sync.Once
+
+	request   *WebSocketRequest
+	addresses map[string]bool
+	assets    map[string]bool
+	lock      sync.RWMutex
+}
+
+func (c *webSocketClient) setRequest(request *WebSocketRequest) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.request = request
+	c.addresses = make(map[string]bool, len(request.Addresses))
+	c.assets = make(map[string]bool, len(request.Assets))
+
+	for _, x := range request.Addresses {
+		c.addresses[x] = true
+	}
+
+	for _, x := range request.Assets {
+		c.assets[x] = true
+	}
+}
+
+func (c *webSocketClient) isInterested(event Event) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.request == nil {
+		return false
+	}
+
+	switch event.Type {
+	case EventBlockConfirmed:
+		return c.request.Blocks
+	case EventTipUpdated, EventRollBack:
+		return c.request.Tip
+	case EventTxConfirmed:
+		return c.isTxOfInterest(event.Tx)
+	default:
+		return false
+	}
+}
+
+// isTxOfInterest checks inputs and outputs which are used on chain. Phase-2 invalid tx uses only its collateral
+// and does not mint
+func (c *webSocketClient) isTxOfInterest(tx *Tx) bool {
+	inputs, outputs := tx.ConsumedInputs(), tx.ProducedOutputs()
+
+	if len(c.addresses) > 0 {
+		for _, input := range inputs {
+			if c.addresses[input.Output.Address] {
+				return true
+			}
+		}
+
+		for _, output := range outputs {
+			if c.addresses[output.Address] {
+				return true
+			}
+		}
+	}
+
+	if len(c.assets) > 0 {
+		isAsset := func(policyID string, name string) bool {
+			return c.assets[policyID] || c.assets[policyID+hex.EncodeToString([]byte(name))]
+		}
+
+		for _, mint := range tx.Mint {
+			if tx.Valid && isAsset(mint.PolicyID, mint.Name) {
+				return true
+			}
+		}
+
+		for _, output := range outputs {
+			for _, token := range output.Tokens {
+				if isAsset(token.PolicyID, token.Name) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func (c *webSocketClient) writeJSON(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+		return err
+	}
+
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// close sends close frame and closes the connection
+func (c *webSocketClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.closeCh)
+
+		_ = c.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(c.writeTimeout))
+		_ = c.conn.Close()
+	})
+}
+
+// evict closes underlying connection without writing anything, which also unblocks pending write
+func (c *webSocketClient) evict() {
+	c.closeOnce.Do(func() {
+		close(c.closeCh)
+
+		_ = c.conn.NetConn().Close()
+	})
+}
+
+func isReplayedEvent(event Event, replayedSlot uint64) bool {
+	switch event.Type {
+	case EventBlockConfirmed:
+		return event.Block.Slot <= replayedSlot
+	case EventTxConfirmed:
+		return event.Tx.BlockSlot <= replayedSlot
+	default:
+		return false
+	}
+}
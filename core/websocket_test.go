@@ -0,0 +1,251 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func dialWebSocket(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http"), nil)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	return conn
+}
+
+func readWebSocketEvent(t *testing.T, conn *websocket.Conn) Event {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var event Event
+
+	require.NoError(t, json.Unmarshal(data, &event))
+
+	return event
+}
+
+func TestWebSocketServer_UnmaskedFrame(t *testing.T) {
+	t.Parallel()
+
+	eventsCh := make(chan Event)
+	wsServer := NewWebSocketServer(WebSocketConfig{}, &DatabaseMock{}, eventsCh, hclog.NewNullLogger())
+
+	wsServer.Start()
+
+	httpServer := httptest.NewServer(wsServer)
+	defer httpServer.Close()
+
+	defer close(eventsCh)
+	defer wsServer.Close()
+
+	address := strings.TrimPrefix(httpServer.URL, "http://")
+
+	conn, err := net.Dial("tcp", address)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	_, err = fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n", address)
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	// text frame without mask bit
+	request := []byte(`{"blocks":true}`)
+	_, err = conn.Write(append([]byte{0x81, byte(len(request))}, request...))
+	require.NoError(t, err)
+
+	// server closes the connection instead of subscribing the client
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	_, err = io.Copy(io.Discard, reader)
+	require.NoError(t, err)
+}
+
+func TestWebSocketServer_SlowClient(t *testing.T) {
+	t.Parallel()
+
+	eventsCh := make(chan Event)
+	wsServer := NewWebSocketServer(WebSocketConfig{
+		BufferSize:   1,
+		WriteTimeout: time.Minute,
+	}, &DatabaseMock{}, eventsCh, hclog.NewNullLogger())
+
+	wsServer.Start()
+
+	httpServer := httptest.NewServer(wsServer)
+	defer httpServer.Close()
+
+	defer close(eventsCh)
+	defer wsServer.Close()
+
+	// client subscribes and never reads, so writing to it blocks once socket buffers are full
+	conn := dialWebSocket(t, httpServer.URL)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(WebSocketRequest{Blocks: true}))
+
+	require.Eventually(t, func() bool {
+		wsServer.lock.Lock()
+		defer wsServer.lock.Unlock()
+
+		for client := range wsServer.clients {
+			client.lock.RLock()
+			defer client.lock.RUnlock()
+
+			return client.request != nil && client.request.Blocks
+		}
+
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	event := Event{Type: EventBlockConfirmed, Block: &CardanoBlock{Txs: make([]Hash, 20_000)}}
+	doneCh := make(chan struct{})
+
+	go func() {
+		defer close(doneCh)
+
+		for i := 0; i < 100; i++ {
+			eventsCh <- event
+		}
+	}()
+
+	// dispatching is not blocked by the pending write to the slow client
+	select {
+	case <-doneCh:
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "dispatching events is blocked")
+	}
+
+	require.Eventually(t, func() bool {
+		wsServer.lock.Lock()
+		defer wsServer.lock.Unlock()
+
+		return len(wsServer.clients) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWebSocketServer(t *testing.T) {
+	t.Parallel()
+
+	const policyID = "0102"
+
+	blocks := []*CardanoBlock{
+		{Slot: 10, Hash: Hash{1}, Txs: []Hash{{11}, {12}}},
+		{Slot: 20, Hash: Hash{2}},
+		{Slot: 30, Hash: Hash{3}, Txs: []Hash{{31}}},
+	}
+	txs := []*Tx{
+		{BlockSlot: 10, Hash: Hash{11}, Valid: true, Outputs: []*TxOutput{{Address: addresses[0]}}},
+		{BlockSlot: 10, Indx: 1, Hash: Hash{12}, Valid: true, Outputs: []*TxOutput{{Address: addresses[1]}}},
+		{BlockSlot: 30, Hash: Hash{31}, Valid: true, Mint: []MintAmount{{PolicyID: policyID, Name: "nft", Amount: 1}}},
+	}
+	dbMock := &DatabaseMock{}
+	eventsCh := make(chan Event)
+	wsServer := NewWebSocketServer(WebSocketConfig{}, dbMock, eventsCh, hclog.NewNullLogger())
+
+	wsServer.Start()
+
+	httpServer := httptest.NewServer(wsServer)
+	defer httpServer.Close()
+
+	defer close(eventsCh)
+	defer wsServer.Close()
+
+	dbMock.On("GetConfirmedBlocksFrom", uint64(10), webSocketReplayBatchSize).Return(blocks[:2], error(nil)).Once()
+	dbMock.On("GetConfirmedTxsInSlotRange", uint64(10), uint64(20)).Return(txs[:2], error(nil)).Once()
+
+	conn := dialWebSocket(t, httpServer.URL)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(WebSocketRequest{
+		Blocks:    true,
+		Addresses: []string{addresses[0]},
+		Assets:    []string{policyID + "6e6674"},
+		FromSlot:  &blocks[0].Slot,
+	}))
+
+	// resumed from slot
+	require.Equal(t, Event{Type: EventBlockConfirmed, Block: blocks[0]}, readWebSocketEvent(t, conn))
+	require.Equal(t, Event{Type: EventTxConfirmed, Tx: txs[0]}, readWebSocketEvent(t, conn))
+	require.Equal(t, Event{Type: EventBlockConfirmed, Block: blocks[1]}, readWebSocketEvent(t, conn))
+
+	// already replayed, not subscribed and new events
+	for _, event := range []Event{
+		{Type: EventBlockConfirmed, Block: blocks[1]},
+		{Type: EventTipUpdated, Point: &BlockPoint{BlockSlot: 40}},
+		{Type: EventBlockConfirmed, Block: blocks[2]},
+		{Type: EventTxConfirmed, Tx: txs[2]},
+	} {
+		eventsCh <- event
+	}
+
+	require.Equal(t, Event{Type: EventBlockConfirmed, Block: blocks[2]}, readWebSocketEvent(t, conn))
+	require.Equal(t, Event{Type: EventTxConfirmed, Tx: txs[2]}, readWebSocketEvent(t, conn))
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("invalid")))
+	require.Equal(t, EventError, readWebSocketEvent(t, conn).Type)
+
+	resp, err := http.Get(httpServer.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	dbMock.AssertExpectations(t)
+}
+
+func TestWebSocketClient_IsTxOfInterestInvalidTx(t *testing.T) {
+	t.Parallel()
+
+	const policyID = "0102"
+
+	client := &webSocketClient{}
+	client.setRequest(&WebSocketRequest{Addresses: addresses[:2], Assets: []string{policyID}})
+
+	tx := &Tx{
+		Valid:   true,
+		Inputs:  []*TxInputOutput{{Output: TxOutput{Address: addresses[0]}}},
+		Outputs: []*TxOutput{{Address: addresses[2], Tokens: []TokenAmount{{PolicyID: policyID, Amount: 1}}}},
+	}
+
+	require.True(t, client.isTxOfInterest(tx))
+
+	// regular inputs and outputs of invalid tx are not used on chain
+	tx.Valid = false
+	tx.Mint = []MintAmount{{PolicyID: policyID, Amount: 1}}
+
+	require.False(t, client.isTxOfInterest(tx))
+
+	tx.CollateralInputs = []*TxInputOutput{{Output: TxOutput{Address: addresses[1]}}}
+
+	require.True(t, client.isTxOfInterest(tx))
+
+	tx.CollateralInputs = nil
+	tx.CollateralReturn = &TxOutput{Address: addresses[1]}
+
+	require.True(t, client.isTxOfInterest(tx))
+}
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/ledger/allegra"
+	"github.com/blinklabs-io/gouroboros/ledger/alonzo"
+	"github.com/blinklabs-io/gouroboros/ledger/babbage"
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/blinklabs-io/gouroboros/ledger/conway"
+	"github.com/blinklabs-io/gouroboros/ledger/mary"
+	"github.com/blinklabs-io/gouroboros/ledger/shelley"
+)
+
+type RedeemerPurpose string
+
+const (
+	RedeemerPurposeSpend     RedeemerPurpose = "spend"
+	RedeemerPurposeMint      RedeemerPurpose = "mint"
+	RedeemerPurposeCert      RedeemerPurpose = "cert"
+	RedeemerPurposeReward    RedeemerPurpose = "reward"
+	RedeemerPurposeVoting    RedeemerPurpose = "voting"
+	RedeemerPurposeProposing RedeemerPurpose = "proposing"
+)
+
+var redeemerPurposes = []RedeemerPurpose{
+	RedeemerPurposeSpend, RedeemerPurposeMint, RedeemerPurposeCert,
+	RedeemerPurposeReward, RedeemerPurposeVoting, RedeemerPurposeProposing,
+}
+
+type ExUnits struct {
+	Memory uint64 `json:"mem"`
+	Steps  uint64 `json:"steps"`
+}
+
+type Redeemer struct {
+	Purpose RedeemerPurpose `json:"purpose"`
+	// index of the input, policy, certificate, withdrawal... (depends on purpose) the redeemer is for
+	Index   uint32  `json:"ind"`
+	Data    []byte  `json:"data"`
+	ExUnits ExUnits `json:"exUnits"`
+}
+
+type Datum struct {
+	Hash Hash   `json:"hash"`
+	Data []byte `json:"data"`
+}
+
+type TxWitnessSet struct {
+	VKeyWitnesses []Witness   `json:"vkeys,omitempty"`
+	Redeemers     []*Redeemer `json:"redeemers,omitempty"`
+	Scripts       []*Script   `json:"scripts,omitempty"`
+	Datums        []*Datum    `json:"datums,omitempty"`
+}
+
+// witnessSetRaw keeps original cbor of scripts and datums because their hashes are calculated from it
+type witnessSetRaw struct {
+	VKeyWitnesses   []interface{}     `cbor:"0,keyasint,omitempty"`
+	NativeScripts   []cbor.RawMessage `cbor:"1,keyasint,omitempty"`
+	PlutusV1Scripts []cbor.RawMessage `cbor:"3,keyasint,omitempty"`
+	PlutusData      []cbor.RawMessage `cbor:"4,keyasint,omitempty"`
+	Redeemers       cbor.RawMessage   `cbor:"5,keyasint,omitempty"`
+	PlutusV2Scripts []cbor.RawMessage `cbor:"6,keyasint,omitempty"`
+	PlutusV3Scripts []cbor.RawMessage `cbor:"7,keyasint,omitempty"`
+}
+
+// NewTxWitnessSet decodes cbor of the tx witness set
+func NewTxWitnessSet(data []byte) (*TxWitnessSet, error) {
+	var (
+		raw    witnessSetRaw
+		result = &TxWitnessSet{}
+	)
+
+	if _, err := cbor.Decode(data, &raw); err != nil {
+		return nil, fmt.Errorf("could not decode witness set: %w", err)
+	}
+
+	if len(raw.VKeyWitnesses) > 0 {
+		result.VKeyWitnesses = NewWitnesses(raw.VKeyWitnesses)
+	}
+
+	for _, script := range raw.NativeScripts {
+		result.Scripts = append(result.Scripts, NewScript(ScriptTypeNative, script))
+	}
+
+	for scriptType, scripts := range map[ScriptType][]cbor.RawMessage{
+		ScriptTypePlutusV1: raw.PlutusV1Scripts,
+		ScriptTypePlutusV2: raw.PlutusV2Scripts,
+		ScriptTypePlutusV3: raw.PlutusV3Scripts,
+	} {
+		for _, script := range scripts {
+			var bytes []byte
+
+			if _, err := cbor.Decode(script, &bytes); err != nil {
+				return nil, fmt.Errorf("could not decode plutus script: %w", err)
+			}
+
+			result.Scripts = append(result.Scripts, NewScript(scriptType, bytes))
+		}
+	}
+
+	// native scripts first, then plutus scripts by version
+	sort.SliceStable(result.Scripts, func(i, j int) bool {
+		return result.Scripts[i].Type < result.Scripts[j].Type
+	})
+
+	for _, datum := range raw.PlutusData {
+		result.Datums = append(result.Datums, &Datum{
+			Hash: Hash(ledgerCommon.Blake2b256Hash(datum)),
+			Data: datum,
+		})
+	}
+
+	if len(raw.Redeemers) > 0 {
+		redeemers, err := newRedeemers(raw.Redeemers)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Redeemers = redeemers
+	}
+
+	return result, nil
+}
+
+// GetDatum returns datum with the given hash from the witness set or nil if it is not there
+func (ws TxWitnessSet) GetDatum(hash Hash) *Datum {
+	for _, datum := range ws.Datums {
+		if datum.Hash == hash {
+			return datum
+		}
+	}
+
+	return nil
+}
+
+// newRedeemers decodes both redeemers list (before conway) and redeemers map (since conway)
+func newRedeemers(data []byte) ([]*Redeemer, error) {
+	var redeemersRaw conway.ConwayRedeemers
+
+	if err := redeemersRaw.UnmarshalCBOR(data); err != nil {
+		return nil, fmt.Errorf("could not decode redeemers: %w", err)
+	}
+
+	keys := make([]conway.ConwayRedeemerKey, 0, len(redeemersRaw.Redeemers))
+	for key := range redeemersRaw.Redeemers {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Tag < keys[j].Tag || keys[i].Tag == keys[j].Tag && keys[i].Index < keys[j].Index
+	})
+
+	result := make([]*Redeemer, len(keys))
+
+	for i, key := range keys {
+		if int(key.Tag) >= len(redeemerPurposes) {
+			return nil, fmt.Errorf("unknown redeemer tag: %d", key.Tag)
+		}
+
+		value := redeemersRaw.Redeemers[key]
+		result[i] = &Redeemer{
+			Purpose: redeemerPurposes[key.Tag],
+			Index:   key.Index,
+			Data:    value.Data,
+			ExUnits: ExUnits{
+				Memory: value.ExUnits.Memory,
+				Steps:  value.ExUnits.Steps,
+			},
+		}
+	}
+
+	return result, nil
+}
+
+// getTxWitnessSet returns decoded witness set of the tx. Witness set of byron tx is empty
+func getTxWitnessSet(tx ledger.Transaction) (*TxWitnessSet, error) {
+	witnessSetCbor := getTxWitnessSetCbor(tx)
+	if len(witnessSetCbor) == 0 {
+		return &TxWitnessSet{}, nil
+	}
+
+	witnessSet, err := NewTxWitnessSet(witnessSetCbor)
+	if err != nil {
+		return nil, fmt.Errorf("tx %s: %w", tx.Hash(), err)
+	}
+
+	return witnessSet, nil
+}
+
+// getTxWitnessSetCbor returns original cbor of the tx witness set. nil for byron txs
+func getTxWitnessSetCbor(tx ledger.Transaction) []byte {
+	switch t := tx.(type) {
+	case *shelley.ShelleyTransaction:
+		return t.WitnessSet.Cbor()
+	case *allegra.AllegraTransaction:
+		return t.WitnessSet.Cbor()
+	case *mary.MaryTransaction:
+		return t.WitnessSet.Cbor()
+	case *alonzo.AlonzoTransaction:
+		return t.WitnessSet.Cbor()
+	case *babbage.BabbageTransaction:
+		return t.WitnessSet.Cbor()
+	case *conway.ConwayTransaction:
+		return t.WitnessSet.Cbor()
+	default:
+		return nil
+	}
+}
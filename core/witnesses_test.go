@@ -0,0 +1,96 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	ledgerCommon "github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/blinklabs-io/gouroboros/ledger/conway"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTxWitnessSet(t *testing.T) {
+	t.Parallel()
+
+	vkey, signature := []byte{1, 2, 3}, []byte{4, 5, 6}
+	nativeScript := []interface{}{0, make([]byte, 28)}
+	plutusScript := []byte{7, 8, 9}
+	datum := []interface{}{uint64(42), []byte("hello")}
+
+	nativeScriptBytes, err := cbor.Encode(nativeScript)
+	require.NoError(t, err)
+
+	datumBytes, err := cbor.Encode(datum)
+	require.NoError(t, err)
+
+	encodeWitnessSet := func(t *testing.T, redeemers interface{}) []byte {
+		t.Helper()
+
+		bytes, err := cbor.Encode(map[uint]interface{}{
+			0: []interface{}{[]interface{}{vkey, signature}},
+			1: []interface{}{nativeScript},
+			4: []interface{}{datum},
+			5: redeemers,
+			6: []interface{}{plutusScript},
+		})
+		require.NoError(t, err)
+
+		return bytes
+	}
+
+	expectedRedeemers := []*Redeemer{
+		{Purpose: RedeemerPurposeSpend, Index: 0, Data: datumBytes, ExUnits: ExUnits{Memory: 10, Steps: 20}},
+		{Purpose: RedeemerPurposeSpend, Index: 2, Data: datumBytes, ExUnits: ExUnits{Memory: 30, Steps: 40}},
+		{Purpose: RedeemerPurposeMint, Index: 1, Data: datumBytes, ExUnits: ExUnits{Memory: 50, Steps: 60}},
+	}
+
+	checkWitnessSet := func(t *testing.T, witnessSet *TxWitnessSet) {
+		t.Helper()
+
+		require.Equal(t, []Witness{{VKey: vkey, Signature: signature}}, witnessSet.VKeyWitnesses)
+		require.Equal(t, []*Script{
+			NewScript(ScriptTypeNative, nativeScriptBytes),
+			NewScript(ScriptTypePlutusV2, plutusScript),
+		}, witnessSet.Scripts)
+		require.Len(t, witnessSet.Datums, 1)
+		require.Equal(t, datumBytes, witnessSet.Datums[0].Data)
+		require.Equal(t, witnessSet.Datums[0], witnessSet.GetDatum(witnessSet.Datums[0].Hash))
+		require.Nil(t, witnessSet.GetDatum(Hash{1}))
+		require.Equal(t, expectedRedeemers, witnessSet.Redeemers)
+	}
+
+	t.Run("redeemers list", func(t *testing.T) {
+		witnessSet, err := NewTxWitnessSet(encodeWitnessSet(t, []interface{}{
+			[]interface{}{1, 1, datum, []interface{}{50, 60}},
+			[]interface{}{0, 2, datum, []interface{}{30, 40}},
+			[]interface{}{0, 0, datum, []interface{}{10, 20}},
+		}))
+
+		require.NoError(t, err)
+		checkWitnessSet(t, witnessSet)
+	})
+
+	t.Run("redeemers map", func(t *testing.T) {
+		witnessSetBytes := encodeWitnessSet(t, map[conway.ConwayRedeemerKey]conway.ConwayRedeemerValue{
+			{Tag: 1, Index: 1}: {Data: datumBytes, ExUnits: ledgerCommon.RedeemerExUnits{Memory: 50, Steps: 60}},
+			{Tag: 0, Index: 2}: {Data: datumBytes, ExUnits: ledgerCommon.RedeemerExUnits{Memory: 30, Steps: 40}},
+			{Tag: 0, Index: 0}: {Data: datumBytes, ExUnits: ledgerCommon.RedeemerExUnits{Memory: 10, Steps: 20}},
+		})
+
+		// witness set is taken from the ledger tx
+		tx := &conway.ConwayTransaction{}
+		require.NoError(t, tx.WitnessSet.UnmarshalCBOR(witnessSetBytes))
+
+		witnessSet, err := NewTxWitnessSet(getTxWitnessSetCbor(tx))
+
+		require.NoError(t, err)
+		checkWitnessSet(t, witnessSet)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewTxWitnessSet([]byte{1, 2, 3})
+		require.Error(t, err)
+
+		require.Nil(t, getTxWitnessSetCbor(&LedgerTransactionMock{}))
+	})
+}
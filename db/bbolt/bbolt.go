@@ -1,25 +1,45 @@
 package bbolt
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/igorcrevar/cardano-go-indexer/core"
 	"go.etcd.io/bbolt"
 )
 
 type BBoltDatabase struct {
-	db *bbolt.DB
+	db       *bbolt.DB
+	readOnly bool
 }
 
+// readOnlyOpenTimeout is how long read-only open waits for the file lock held by a writer
+const readOnlyOpenTimeout = time.Second * 5
+
 var (
 	txOutputsBucket        = []byte("TXOuts")
 	latestBlockPointBucket = []byte("LatestBlockPoint")
 	processedTxsBucket     = []byte("ProcessedTxs")
 	unprocessedTxsBucket   = []byte("UnprocessedTxs")
 	confirmedBlocks        = []byte("confirmedBlocks")
+	mintEventsBucket       = []byte("MintEvents")
+	govProposalsBucket     = []byte("GovProposals")
+	govVotesBucket         = []byte("GovVotes")
+	subscribersBucket      = []byte("Subscribers")
+	deadLetterTxsBucket    = []byte("DeadLetterTxs")
 
 	defaultKey = []byte("default")
+
+	allBuckets = [][]byte{
+		txOutputsBucket, latestBlockPointBucket, processedTxsBucket, unprocessedTxsBucket, confirmedBlocks,
+		mintEventsBucket, govProposalsBucket, govVotesBucket, subscribersBucket,
+		deadLetterTxsBucket,
+	}
 )
 
 var _ core.Database = (*BBoltDatabase)(nil)
@@ -33,9 +53,7 @@ func (bd *BBoltDatabase) Init(filePath string) error {
 	bd.db = db
 
 	return db.Update(func(tx *bbolt.Tx) error {
-		for _, bn := range [][]byte{
-			txOutputsBucket, latestBlockPointBucket, processedTxsBucket, unprocessedTxsBucket, confirmedBlocks,
-		} {
+		for _, bn := range allBuckets {
 			_, err := tx.CreateBucketIfNotExists(bn)
 			if err != nil {
 				return fmt.Errorf("could not create bucket %s: %w", string(bn), err)
@@ -46,6 +64,32 @@ func (bd *BBoltDatabase) Init(filePath string) error {
 	})
 }
 
+// InitReadOnly opens existing database file with a shared lock.
+// bbolt writer holds an exclusive file lock until it is closed, so opening fails after a timeout
+// if the file is opened for writing by any process. Buckets which do not exist in the file are read as empty
+func (bd *BBoltDatabase) InitReadOnly(filePath string) error {
+	if _, err := os.Stat(filePath); err != nil {
+		return fmt.Errorf("could not open db: %w", err)
+	}
+
+	db, err := bbolt.Open(filePath, 0660, &bbolt.Options{
+		ReadOnly: true,
+		Timeout:  readOnlyOpenTimeout,
+	})
+	if err != nil {
+		if errors.Is(err, bbolt.ErrTimeout) {
+			return fmt.Errorf("could not open db, it is probably opened for writing by another process: %w", err)
+		}
+
+		return fmt.Errorf("could not open db: %w", err)
+	}
+
+	bd.db = db
+	bd.readOnly = true
+
+	return nil
+}
+
 func (bd *BBoltDatabase) Close() error {
 	return bd.db.Close()
 }
@@ -53,8 +97,8 @@ func (bd *BBoltDatabase) Close() error {
 func (bd *BBoltDatabase) GetLatestBlockPoint() (*core.BlockPoint, error) {
 	var result *core.BlockPoint
 
-	if err := bd.db.View(func(tx *bbolt.Tx) error {
-		if data := tx.Bucket(latestBlockPointBucket).Get(defaultKey); len(data) > 0 {
+	if err := bd.viewBucket(latestBlockPointBucket, func(bucket *bbolt.Bucket) error {
+		if data := bucket.Get(defaultKey); len(data) > 0 {
 			return json.Unmarshal(data, &result)
 		}
 
@@ -67,8 +111,8 @@ func (bd *BBoltDatabase) GetLatestBlockPoint() (*core.BlockPoint, error) {
 }
 
 func (bd *BBoltDatabase) GetTxOutput(txInput core.TxInput) (result core.TxOutput, err error) {
-	err = bd.db.View(func(tx *bbolt.Tx) error {
-		if data := tx.Bucket(txOutputsBucket).Get(txInput.Key()); len(data) > 0 {
+	err = bd.viewBucket(txOutputsBucket, func(bucket *bbolt.Bucket) error {
+		if data := bucket.Get(txInput.Key()); len(data) > 0 {
 			return json.Unmarshal(data, &result)
 		}
 
@@ -79,6 +123,10 @@ func (bd *BBoltDatabase) GetTxOutput(txInput core.TxInput) (result core.TxOutput
 }
 
 func (bd *BBoltDatabase) MarkConfirmedTxsProcessed(txs []*core.Tx) error {
+	if bd.readOnly {
+		return core.ErrReadOnlyDatabase
+	}
+
 	return bd.db.Update(func(tx *bbolt.Tx) error {
 		for _, cardTx := range txs {
 			if err := tx.Bucket(unprocessedTxsBucket).Delete(cardTx.Key()); err != nil {
@@ -102,8 +150,8 @@ func (bd *BBoltDatabase) MarkConfirmedTxsProcessed(txs []*core.Tx) error {
 func (bd *BBoltDatabase) GetUnprocessedConfirmedTxs(maxCnt int) ([]*core.Tx, error) {
 	var result []*core.Tx
 
-	err := bd.db.View(func(tx *bbolt.Tx) error {
-		cursor := tx.Bucket(unprocessedTxsBucket).Cursor()
+	err := bd.viewBucket(unprocessedTxsBucket, func(bucket *bbolt.Bucket) error {
+		cursor := bucket.Cursor()
 
 		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
 			var cardTx *core.Tx
@@ -130,8 +178,8 @@ func (bd *BBoltDatabase) GetUnprocessedConfirmedTxs(maxCnt int) ([]*core.Tx, err
 func (bd *BBoltDatabase) GetLatestConfirmedBlocks(maxCnt int) ([]*core.CardanoBlock, error) {
 	var result []*core.CardanoBlock
 
-	err := bd.db.View(func(tx *bbolt.Tx) error {
-		cursor := tx.Bucket(confirmedBlocks).Cursor()
+	err := bd.viewBucket(confirmedBlocks, func(bucket *bbolt.Bucket) error {
+		cursor := bucket.Cursor()
 
 		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
 			var block *core.CardanoBlock
@@ -158,8 +206,8 @@ func (bd *BBoltDatabase) GetLatestConfirmedBlocks(maxCnt int) ([]*core.CardanoBl
 func (bd *BBoltDatabase) GetConfirmedBlocksFrom(slotNumber uint64, maxCnt int) ([]*core.CardanoBlock, error) {
 	var result []*core.CardanoBlock
 
-	err := bd.db.View(func(tx *bbolt.Tx) error {
-		cursor := tx.Bucket(confirmedBlocks).Cursor()
+	err := bd.viewBucket(confirmedBlocks, func(bucket *bbolt.Bucket) error {
+		cursor := bucket.Cursor()
 
 		for k, v := cursor.Seek(core.SlotNumberToKey(slotNumber)); k != nil; k, v = cursor.Next() {
 			var block *core.CardanoBlock
@@ -183,11 +231,77 @@ func (bd *BBoltDatabase) GetConfirmedBlocksFrom(slotNumber uint64, maxCnt int) (
 	return result, nil
 }
 
+func (bd *BBoltDatabase) GetConfirmedBlocksInSlotRange(fromSlot uint64, toSlot uint64) ([]*core.CardanoBlock, error) {
+	var result []*core.CardanoBlock
+
+	err := bd.viewBucket(confirmedBlocks, func(bucket *bbolt.Bucket) error {
+		cursor := bucket.Cursor()
+		toKey := core.SlotNumberToKey(toSlot)
+
+		for k, v := cursor.Seek(core.SlotNumberToKey(fromSlot)); k != nil; k, v = cursor.Next() {
+			if bytes.Compare(k, toKey) > 0 {
+				break
+			}
+
+			var block *core.CardanoBlock
+
+			if err := json.Unmarshal(v, &block); err != nil {
+				return err
+			}
+
+			result = append(result, block)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (bd *BBoltDatabase) GetConfirmedTxsInSlotRange(fromSlot uint64, toSlot uint64) ([]*core.Tx, error) {
+	var result []*core.Tx
+
+	err := bd.db.View(func(tx *bbolt.Tx) error {
+		for _, bucketName := range [][]byte{unprocessedTxsBucket, processedTxsBucket} {
+			bucket := tx.Bucket(bucketName)
+			if bucket == nil {
+				continue
+			}
+
+			cursor := bucket.Cursor()
+
+			for k, v := cursor.Seek(core.SlotNumberToKey(fromSlot)); k != nil; k, v = cursor.Next() {
+				if binary.BigEndian.Uint64(k[:8]) > toSlot {
+					break
+				}
+
+				var cardTx *core.Tx
+
+				if err := json.Unmarshal(v, &cardTx); err != nil {
+					return err
+				}
+
+				result = append(result, cardTx)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return core.SortTxs(result), nil
+}
+
 func (bd *BBoltDatabase) GetAllTxOutputs(address string, onlyNotUsed bool) ([]*core.TxInputOutput, error) {
 	var result []*core.TxInputOutput
 
-	err := bd.db.View(func(tx *bbolt.Tx) error {
-		cursor := tx.Bucket(txOutputsBucket).Cursor()
+	err := bd.viewBucket(txOutputsBucket, func(bucket *bbolt.Bucket) error {
+		cursor := bucket.Cursor()
 
 		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
 			var output core.TxOutput
@@ -220,8 +334,459 @@ func (bd *BBoltDatabase) GetAllTxOutputs(address string, onlyNotUsed bool) ([]*c
 	return core.SortTxInputOutputs(result), nil
 }
 
+func (bd *BBoltDatabase) GetMintEvents(policyID string) ([]*core.MintEvent, error) {
+	var result []*core.MintEvent
+
+	prefix, err := core.PolicyIDToKey(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bd.viewBucket(mintEventsBucket, func(bucket *bbolt.Bucket) error {
+		cursor := bucket.Cursor()
+
+		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			var event *core.MintEvent
+
+			if err := json.Unmarshal(v, &event); err != nil {
+				return err
+			}
+
+			result = append(result, event)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (bd *BBoltDatabase) GetGovProposal(actionID core.GovActionID) (result *core.GovProposalEvent, err error) {
+	err = bd.viewBucket(govProposalsBucket, func(bucket *bbolt.Bucket) error {
+		if data := bucket.Get(actionID.Key()); len(data) > 0 {
+			return json.Unmarshal(data, &result)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func (bd *BBoltDatabase) GetGovVotes(voter core.Voter) ([]*core.GovVoteEvent, error) {
+	var result []*core.GovVoteEvent
+
+	prefix, err := core.VoterToKey(voter)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bd.viewBucket(govVotesBucket, func(bucket *bbolt.Bucket) error {
+		cursor := bucket.Cursor()
+
+		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			var event *core.GovVoteEvent
+
+			if err := json.Unmarshal(v, &event); err != nil {
+				return err
+			}
+
+			result = append(result, event)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (bd *BBoltDatabase) RegisterSubscriber(name string, fromSlot uint64) (*core.Subscriber, error) {
+	var result *core.Subscriber
+
+	if bd.readOnly {
+		return nil, core.ErrReadOnlyDatabase
+	}
+
+	err := bd.db.Update(func(tx *bbolt.Tx) error {
+		subscriber, err := getSubscriber(tx, name)
+		if err == nil {
+			result = subscriber
+
+			return nil
+		} else if !errors.Is(err, core.ErrSubscriberNotFound) {
+			return err
+		}
+
+		result = core.NewSubscriber(name, fromSlot)
+
+		return putSubscriber(tx, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (bd *BBoltDatabase) UnregisterSubscriber(name string) error {
+	if bd.readOnly {
+		return core.ErrReadOnlyDatabase
+	}
+
+	return bd.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(subscribersBucket).Delete([]byte(name)); err != nil {
+			return fmt.Errorf("could not delete subscriber: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (bd *BBoltDatabase) GetSubscribers() ([]*core.Subscriber, error) {
+	var result []*core.Subscriber
+
+	err := bd.viewBucket(subscribersBucket, func(bucket *bbolt.Bucket) error {
+		return bucket.ForEach(func(k, v []byte) error {
+			var subscriber *core.Subscriber
+
+			if err := json.Unmarshal(v, &subscriber); err != nil {
+				return err
+			}
+
+			result = append(result, subscriber)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (bd *BBoltDatabase) GetSubscriberUnprocessedConfirmedTxs(name string, maxCnt int) ([]*core.Tx, error) {
+	var result []*core.Tx
+
+	err := bd.db.View(func(tx *bbolt.Tx) error {
+		subscriber, err := getSubscriber(tx, name)
+		if err != nil {
+			return err
+		}
+
+		// tx is either processed or unprocessed, so at most maxCnt txs are needed from each bucket
+		for _, bucketName := range [][]byte{unprocessedTxsBucket, processedTxsBucket} {
+			bucket := tx.Bucket(bucketName)
+			if bucket == nil {
+				continue
+			}
+
+			cursor := bucket.Cursor()
+			cnt := 0
+
+			for k, v := cursor.Seek(subscriber.Cursor); k != nil; k, v = cursor.Next() {
+				if !subscriber.IsAfterCursor(k) {
+					continue
+				}
+
+				var cardTx *core.Tx
+
+				if err := json.Unmarshal(v, &cardTx); err != nil {
+					return err
+				}
+
+				result = append(result, cardTx)
+				if cnt++; maxCnt > 0 && cnt == maxCnt {
+					break
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return core.SortAndLimitTxs(result, maxCnt), nil
+}
+
+func (bd *BBoltDatabase) MarkSubscriberConfirmedTxsProcessed(name string, txs []*core.Tx) error {
+	if bd.readOnly {
+		return core.ErrReadOnlyDatabase
+	}
+
+	return bd.db.Update(func(tx *bbolt.Tx) error {
+		subscriber, err := getSubscriber(tx, name)
+		if err != nil {
+			return err
+		}
+
+		subscriber.MoveCursor(txs)
+
+		return putSubscriber(tx, subscriber)
+	})
+}
+
+func (bd *BBoltDatabase) GetDeadLetterTxs(maxCnt int) ([]*core.DeadLetterTx, error) {
+	var result []*core.DeadLetterTx
+
+	err := bd.viewBucket(deadLetterTxsBucket, func(bucket *bbolt.Bucket) error {
+		cursor := bucket.Cursor()
+
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var deadLetterTx *core.DeadLetterTx
+
+			if err := json.Unmarshal(v, &deadLetterTx); err != nil {
+				return err
+			}
+
+			result = append(result, deadLetterTx)
+			if maxCnt > 0 && len(result) == maxCnt {
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (bd *BBoltDatabase) PruneConfirmedBlocks(
+	isExpired func(block *core.CardanoBlock) bool, maxCnt int,
+) (int, error) {
+	var cnt int
+
+	if bd.readOnly {
+		return 0, core.ErrReadOnlyDatabase
+	}
+
+	err := bd.db.Update(func(tx *bbolt.Tx) error {
+		var keys [][]byte
+
+		bucket := tx.Bucket(confirmedBlocks)
+		cursor := bucket.Cursor()
+
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var block *core.CardanoBlock
+
+			if err := json.Unmarshal(v, &block); err != nil {
+				return err
+			}
+
+			if !isExpired(block) {
+				break
+			}
+
+			keys = append(keys, bytes.Clone(k))
+			if maxCnt > 0 && len(keys) == maxCnt {
+				break
+			}
+		}
+
+		return deleteKeys(bucket, keys, &cnt)
+	})
+
+	return cnt, err
+}
+
+func (bd *BBoltDatabase) PruneProcessedTxs(slotNumber uint64, maxCnt int) (int, error) {
+	var cnt int
+
+	if bd.readOnly {
+		return 0, core.ErrReadOnlyDatabase
+	}
+
+	err := bd.db.Update(func(tx *bbolt.Tx) error {
+		var keys [][]byte
+
+		bucket := tx.Bucket(processedTxsBucket)
+		cursor := bucket.Cursor()
+		slotKey := core.SlotNumberToKey(slotNumber)
+
+		// tx key starts with the slot number, so keys are ordered by slot
+		for k, _ := cursor.First(); k != nil && bytes.Compare(k, slotKey) < 0; k, _ = cursor.Next() {
+			keys = append(keys, bytes.Clone(k))
+			if maxCnt > 0 && len(keys) == maxCnt {
+				break
+			}
+		}
+
+		return deleteKeys(bucket, keys, &cnt)
+	})
+
+	return cnt, err
+}
+
+func (bd *BBoltDatabase) IterateTxOutputs(handler func(txInputOutput *core.TxInputOutput) error) error {
+	return bd.viewBucket(txOutputsBucket, func(bucket *bbolt.Bucket) error {
+		return bucket.ForEach(func(k, v []byte) error {
+			var output core.TxOutput
+
+			if err := json.Unmarshal(v, &output); err != nil {
+				return err
+			}
+
+			input, err := core.NewTxInputFromBytes(k)
+			if err != nil {
+				return err
+			}
+
+			return handler(&core.TxInputOutput{
+				Input:  input,
+				Output: output,
+			})
+		})
+	})
+}
+
+func (bd *BBoltDatabase) IterateConfirmedBlocks(handler func(block *core.CardanoBlock) error) error {
+	return bd.viewBucket(confirmedBlocks, func(bucket *bbolt.Bucket) error {
+		return bucket.ForEach(func(k, v []byte) error {
+			var block *core.CardanoBlock
+
+			if err := json.Unmarshal(v, &block); err != nil {
+				return err
+			}
+
+			return handler(block)
+		})
+	})
+}
+
+func (bd *BBoltDatabase) IterateConfirmedTxs(processed bool, handler func(tx *core.Tx) error) error {
+	bucketName := unprocessedTxsBucket
+	if processed {
+		bucketName = processedTxsBucket
+	}
+
+	return bd.viewBucket(bucketName, func(bucket *bbolt.Bucket) error {
+		return bucket.ForEach(func(k, v []byte) error {
+			var cardTx *core.Tx
+
+			if err := json.Unmarshal(v, &cardTx); err != nil {
+				return err
+			}
+
+			return handler(cardTx)
+		})
+	})
+}
+
+func (bd *BBoltDatabase) IterateMintEvents(handler func(event *core.MintEvent) error) error {
+	return bd.viewBucket(mintEventsBucket, func(bucket *bbolt.Bucket) error {
+		return bucket.ForEach(func(k, v []byte) error {
+			var event *core.MintEvent
+
+			if err := json.Unmarshal(v, &event); err != nil {
+				return err
+			}
+
+			return handler(event)
+		})
+	})
+}
+
+func (bd *BBoltDatabase) IterateGovProposals(handler func(event *core.GovProposalEvent) error) error {
+	return bd.viewBucket(govProposalsBucket, func(bucket *bbolt.Bucket) error {
+		return bucket.ForEach(func(k, v []byte) error {
+			var event *core.GovProposalEvent
+
+			if err := json.Unmarshal(v, &event); err != nil {
+				return err
+			}
+
+			return handler(event)
+		})
+	})
+}
+
+func (bd *BBoltDatabase) IterateGovVotes(handler func(event *core.GovVoteEvent) error) error {
+	return bd.viewBucket(govVotesBucket, func(bucket *bbolt.Bucket) error {
+		return bucket.ForEach(func(k, v []byte) error {
+			var event *core.GovVoteEvent
+
+			if err := json.Unmarshal(v, &event); err != nil {
+				return err
+			}
+
+			return handler(event)
+		})
+	})
+}
+
+// viewBucket calls handler with the bucket within a read transaction.
+// Database opened in read-only mode can miss buckets (e.g. created by an older version), these are read as empty
+func (bd *BBoltDatabase) viewBucket(name []byte, handler func(bucket *bbolt.Bucket) error) error {
+	return bd.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(name)
+		if bucket == nil {
+			return nil
+		}
+
+		return handler(bucket)
+	})
+}
+
 func (bd *BBoltDatabase) OpenTx() core.DBTransactionWriter {
 	return &BBoltTransactionWriter{
-		db: bd.db,
+		db:       bd.db,
+		readOnly: bd.readOnly,
 	}
 }
+
+func getSubscriber(tx *bbolt.Tx, name string) (*core.Subscriber, error) {
+	var subscriber *core.Subscriber
+
+	var data []byte
+
+	if bucket := tx.Bucket(subscribersBucket); bucket != nil {
+		data = bucket.Get([]byte(name))
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: %s", core.ErrSubscriberNotFound, name)
+	}
+
+	if err := json.Unmarshal(data, &subscriber); err != nil {
+		return nil, err
+	}
+
+	return subscriber, nil
+}
+
+func putSubscriber(tx *bbolt.Tx, subscriber *core.Subscriber) error {
+	bytes, err := json.Marshal(subscriber)
+	if err != nil {
+		return fmt.Errorf("could not marshal subscriber: %w", err)
+	}
+
+	if err := tx.Bucket(subscribersBucket).Put(subscriber.Key(), bytes); err != nil {
+		return fmt.Errorf("subscriber write error: %w", err)
+	}
+
+	return nil
+}
+
+func deleteKeys(bucket *bbolt.Bucket, keys [][]byte, cnt *int) error {
+	// deleting while iterating with cursor skips items, so keys are collected first
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return fmt.Errorf("could not delete key %x: %w", k, err)
+		}
+	}
+
+	*cnt = len(keys)
+
+	return nil
+}
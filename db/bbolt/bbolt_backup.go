@@ -0,0 +1,79 @@
+package bbolt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/igorcrevar/cardano-go-indexer/core"
+	"go.etcd.io/bbolt"
+)
+
+const DatabaseName = "bbolt"
+
+// Backup writes the snapshot header followed by the whole bbolt file as seen by a single read transaction
+func (bd *BBoltDatabase) Backup(w io.Writer) error {
+	return bd.db.View(func(tx *bbolt.Tx) error {
+		var point *core.BlockPoint
+
+		if bucket := tx.Bucket(latestBlockPointBucket); bucket != nil {
+			if data := bucket.Get(defaultKey); len(data) > 0 {
+				if err := json.Unmarshal(data, &point); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := core.WriteSnapshotHeader(w, core.SnapshotHeader{
+			Version:    core.SnapshotVersion,
+			Database:   DatabaseName,
+			BlockPoint: point,
+		}); err != nil {
+			return err
+		}
+
+		if _, err := tx.WriteTo(w); err != nil {
+			return fmt.Errorf("could not write backup: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Restore creates a new database file from the snapshot payload (snapshot header must be already read)
+func Restore(r io.Reader, filePath string) (err error) {
+	if _, err := os.Stat(filePath); err == nil {
+		return fmt.Errorf("database file already exists: %s", filePath)
+	}
+
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0660)
+	if err != nil {
+		return fmt.Errorf("could not create db file: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			os.Remove(filePath) //nolint:errcheck
+		}
+	}()
+
+	if _, err = io.Copy(file, r); err != nil {
+		file.Close() //nolint:errcheck
+
+		return fmt.Errorf("could not restore db file: %w", err)
+	}
+
+	if err = errors.Join(file.Sync(), file.Close()); err != nil {
+		return fmt.Errorf("could not restore db file: %w", err)
+	}
+
+	// open restored file to ensure it is a valid database
+	db, err := bbolt.Open(filePath, 0660, &bbolt.Options{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("restored db is not valid: %w", err)
+	}
+
+	return db.Close()
+}
@@ -6,6 +6,7 @@ import (
 
 	indexer "github.com/igorcrevar/cardano-go-indexer/core"
 	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
 )
 
 func TestDatabase(t *testing.T) {
@@ -411,6 +412,107 @@ func TestDatabase(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, []*indexer.TxInputOutput{good1, good2, good3, good4}, result)
 	})
+
+	t.Run("PruneConfirmedBlocks", func(t *testing.T) {
+		t.Cleanup(dbCleanup)
+
+		db := &BBoltDatabase{}
+		require.NoError(t, db.Init(filePath))
+
+		dbTx := db.OpenTx()
+		for i := uint64(1); i <= 6; i++ {
+			dbTx.AddConfirmedBlock(&indexer.CardanoBlock{Slot: i * 10, Number: i})
+		}
+
+		require.NoError(t, dbTx.Execute())
+
+		isExpired := func(block *indexer.CardanoBlock) bool {
+			return block.Number <= 4
+		}
+
+		cnt, err := db.PruneConfirmedBlocks(isExpired, 3)
+		require.NoError(t, err)
+		require.Equal(t, 3, cnt)
+
+		cnt, err = db.PruneConfirmedBlocks(isExpired, 3)
+		require.NoError(t, err)
+		require.Equal(t, 1, cnt)
+
+		cnt, err = db.PruneConfirmedBlocks(isExpired, 3)
+		require.NoError(t, err)
+		require.Equal(t, 0, cnt)
+
+		blocks, err := db.GetConfirmedBlocksFrom(0, 0)
+		require.NoError(t, err)
+		require.Equal(t, []*indexer.CardanoBlock{{Slot: 50, Number: 5}, {Slot: 60, Number: 6}}, blocks)
+	})
+
+	t.Run("PruneProcessedTxs", func(t *testing.T) {
+		t.Cleanup(dbCleanup)
+
+		txs := []*indexer.Tx{
+			{BlockSlot: 5, Indx: 0},
+			{BlockSlot: 5, Indx: 1},
+			{BlockSlot: 8, Indx: 0},
+			{BlockSlot: 10, Indx: 0},
+			{BlockSlot: 12, Indx: 2},
+		}
+
+		db := &BBoltDatabase{}
+		require.NoError(t, db.Init(filePath))
+		require.NoError(t, db.MarkConfirmedTxsProcessed(txs))
+
+		cnt, err := db.PruneProcessedTxs(10, 2)
+		require.NoError(t, err)
+		require.Equal(t, 2, cnt)
+
+		cnt, err = db.PruneProcessedTxs(10, 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, cnt)
+
+		cnt, err = db.PruneProcessedTxs(10, 0)
+		require.NoError(t, err)
+		require.Equal(t, 0, cnt)
+
+		require.NoError(t, db.db.View(func(tx *bbolt.Tx) error {
+			require.Equal(t, 2, tx.Bucket(processedTxsBucket).Stats().KeyN)
+
+			return nil
+		}))
+	})
+
+	t.Run("InitReadOnlyMissingBuckets", func(t *testing.T) {
+		t.Cleanup(dbCleanup)
+
+		rawDB, err := bbolt.Open(filePath, 0660, nil)
+		require.NoError(t, err)
+		require.NoError(t, rawDB.Update(func(tx *bbolt.Tx) error {
+			_, err := tx.CreateBucket(confirmedBlocks)
+
+			return err
+		}))
+		require.NoError(t, rawDB.Close())
+
+		db := &BBoltDatabase{}
+		require.NoError(t, db.InitReadOnly(filePath))
+
+		defer db.Close()
+
+		point, err := db.GetLatestBlockPoint()
+		require.NoError(t, err)
+		require.Nil(t, point)
+
+		txs, err := db.GetConfirmedTxsInSlotRange(0, 100)
+		require.NoError(t, err)
+		require.Empty(t, txs)
+
+		_, err = db.GetSubscriberUnprocessedConfirmedTxs("a", 0)
+		require.ErrorIs(t, err, indexer.ErrSubscriberNotFound)
+
+		blocks, err := db.GetLatestConfirmedBlocks(0)
+		require.NoError(t, err)
+		require.Empty(t, blocks)
+	})
 }
 
 func removeDirOrFilePathIfExists(dirOrFilePath string) (err error) {
@@ -14,6 +14,7 @@ type txOperation func(tx *bbolt.Tx) error
 type BBoltTransactionWriter struct {
 	db         *bbolt.DB
 	operations []txOperation
+	readOnly   bool
 }
 
 var _ core.DBTransactionWriter = (*BBoltTransactionWriter)(nil)
@@ -96,6 +97,186 @@ func (tw *BBoltTransactionWriter) AddConfirmedTxs(txs []*core.Tx) core.DBTransac
 	return tw
 }
 
+func (tw *BBoltTransactionWriter) AddMintEvents(events []*core.MintEvent) core.DBTransactionWriter {
+	if len(events) == 0 {
+		return tw
+	}
+
+	tw.operations = append(tw.operations, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(mintEventsBucket)
+
+		for _, event := range events {
+			bytes, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("could not marshal mint event: %w", err)
+			}
+
+			if err = bucket.Put(event.Key(), bytes); err != nil {
+				return fmt.Errorf("mint event write error: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *BBoltTransactionWriter) AddGovProposals(events []*core.GovProposalEvent) core.DBTransactionWriter {
+	if len(events) == 0 {
+		return tw
+	}
+
+	tw.operations = append(tw.operations, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(govProposalsBucket)
+
+		for _, event := range events {
+			bytes, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("could not marshal governance proposal: %w", err)
+			}
+
+			if err = bucket.Put(event.Key(), bytes); err != nil {
+				return fmt.Errorf("governance proposal write error: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *BBoltTransactionWriter) AddGovVotes(events []*core.GovVoteEvent) core.DBTransactionWriter {
+	if len(events) == 0 {
+		return tw
+	}
+
+	tw.operations = append(tw.operations, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(govVotesBucket)
+
+		for _, event := range events {
+			bytes, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("could not marshal governance vote: %w", err)
+			}
+
+			key, err := event.Key()
+			if err != nil {
+				return fmt.Errorf("invalid governance vote: %w", err)
+			}
+
+			if err = bucket.Put(key, bytes); err != nil {
+				return fmt.Errorf("governance vote write error: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *BBoltTransactionWriter) SetSubscriber(subscriber *core.Subscriber) core.DBTransactionWriter {
+	tw.operations = append(tw.operations, func(tx *bbolt.Tx) error {
+		return putSubscriber(tx, subscriber)
+	})
+
+	return tw
+}
+
+func (tw *BBoltTransactionWriter) AddDeadLetterTxs(txs []*core.DeadLetterTx) core.DBTransactionWriter {
+	if len(txs) == 0 {
+		return tw
+	}
+
+	tw.operations = append(tw.operations, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(deadLetterTxsBucket)
+
+		for _, deadLetterTx := range txs {
+			bytes, err := json.Marshal(deadLetterTx)
+			if err != nil {
+				return fmt.Errorf("could not marshal dead-letter tx: %w", err)
+			}
+
+			if err = bucket.Put(deadLetterTx.Key(), bytes); err != nil {
+				return fmt.Errorf("dead-letter tx write error: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *BBoltTransactionWriter) DeleteDeadLetterTxs(txs []*core.DeadLetterTx) core.DBTransactionWriter {
+	if len(txs) == 0 {
+		return tw
+	}
+
+	tw.operations = append(tw.operations, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(deadLetterTxsBucket)
+
+		for _, deadLetterTx := range txs {
+			if err := bucket.Delete(deadLetterTx.Key()); err != nil {
+				return fmt.Errorf("delete dead-letter tx error: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *BBoltTransactionWriter) DeleteConfirmedTxs(txs []*core.Tx) core.DBTransactionWriter {
+	if len(txs) == 0 {
+		return tw
+	}
+
+	tw.operations = append(tw.operations, func(tx *bbolt.Tx) error {
+		for _, cardTx := range txs {
+			for _, bucketName := range [][]byte{unprocessedTxsBucket, processedTxsBucket} {
+				if err := tx.Bucket(bucketName).Delete(cardTx.Key()); err != nil {
+					return fmt.Errorf("delete confirmed tx error: %w", err)
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *BBoltTransactionWriter) MarkConfirmedTxsProcessed(txs []*core.Tx) core.DBTransactionWriter {
+	if len(txs) == 0 {
+		return tw
+	}
+
+	tw.operations = append(tw.operations, func(tx *bbolt.Tx) error {
+		for _, cardTx := range txs {
+			if err := tx.Bucket(unprocessedTxsBucket).Delete(cardTx.Key()); err != nil {
+				return fmt.Errorf("could not remove from unprocessed txs: %w", err)
+			}
+
+			bytes, err := json.Marshal(cardTx)
+			if err != nil {
+				return fmt.Errorf("could not marshal tx: %w", err)
+			}
+
+			if err := tx.Bucket(processedTxsBucket).Put(cardTx.Key(), bytes); err != nil {
+				return fmt.Errorf("could not move to processed txs: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
 func (tw *BBoltTransactionWriter) RemoveTxOutputs(txInputs []*core.TxInput, softDelete bool) core.DBTransactionWriter {
 	if len(txInputs) == 0 {
 		return tw
@@ -156,6 +337,10 @@ func (tw *BBoltTransactionWriter) Execute() error {
 		tw.operations = nil
 	}()
 
+	if tw.readOnly {
+		return core.ErrReadOnlyDatabase
+	}
+
 	return tw.db.Update(func(tx *bbolt.Tx) error {
 		for _, op := range tw.operations {
 			if err := op(tx); err != nil {
@@ -1,6 +1,10 @@
 package db
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/igorcrevar/cardano-go-indexer/core"
@@ -10,18 +14,148 @@ import (
 
 func NewDatabase(name string) core.Database {
 	switch strings.ToLower(name) {
-	case "leveldb":
+	case leveldb.DatabaseName:
 		return &leveldb.LevelDBDatabase{}
 	default:
 		return &bbolt.BBoltDatabase{}
 	}
 }
 
-func NewDatabaseInit(name string, filePath string) (core.Database, error) {
+type initOptions struct {
+	readOnly bool
+}
+
+type InitOption func(*initOptions)
+
+// ReadOnly opens existing database for reading only, e.g. for reporting jobs. Every write returns an error.
+// Both bbolt and leveldb keep an exclusive file lock while the database is opened for writing, so the database
+// can not be opened (not even for reading) by another process while the indexer runs. Such process should read
+// a snapshot written by the indexer (BackupToFile and NewSnapshotReader) instead
+func ReadOnly() InitOption {
+	return func(o *initOptions) {
+		o.readOnly = true
+	}
+}
+
+func NewDatabaseInit(name string, filePath string, options ...InitOption) (core.Database, error) {
+	var opts initOptions
+
+	for _, option := range options {
+		option(&opts)
+	}
+
 	db := NewDatabase(name)
-	if err := db.Init(filePath); err != nil {
+
+	initFn := db.Init
+	if opts.readOnly {
+		initFn = db.InitReadOnly
+	}
+
+	if err := initFn(filePath); err != nil {
 		return nil, err
 	}
 
 	return db, nil
 }
+
+// NewSnapshotReader restores the snapshot created with Backup into a new database at filePath
+// and opens it for reading only. Backup is online, so the snapshot can be taken while the database is being written
+func NewSnapshotReader(r io.Reader, filePath string) (core.DatabaseReader, error) {
+	name, _, err := Restore(r, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDatabaseInit(name, filePath, ReadOnly())
+}
+
+// NewDatabaseSnapshotReader creates a snapshot reader of the database which stays opened for writing.
+// It needs the writable database, so it can be used only in the process which opened it
+func NewDatabaseSnapshotReader(db core.Database, filePath string) (core.DatabaseReader, error) {
+	pr, pw := io.Pipe()
+	backupErrCh := make(chan error, 1)
+
+	go func() {
+		err := db.Backup(pw)
+		pw.CloseWithError(err) //nolint:errcheck
+
+		backupErrCh <- err
+	}()
+
+	reader, err := NewSnapshotReader(pr, filePath)
+	// unblocks the backup if restore stopped reading
+	pr.Close() //nolint:errcheck
+
+	if backupErr := <-backupErrCh; backupErr != nil {
+		if reader != nil {
+			reader.Close() //nolint:errcheck
+		}
+
+		return nil, fmt.Errorf("could not backup db: %w", backupErr)
+	}
+
+	return reader, err
+}
+
+// BackupToFile writes the database snapshot into a temporary file which is renamed to filePath when done
+func BackupToFile(db core.Database, filePath string) (err error) {
+	tmpFilePath := filePath + ".tmp"
+
+	file, err := os.Create(tmpFilePath)
+	if err != nil {
+		return fmt.Errorf("could not create backup file: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			os.Remove(tmpFilePath) //nolint:errcheck
+		}
+	}()
+
+	if err = db.Backup(file); err != nil {
+		file.Close() //nolint:errcheck
+
+		return err
+	}
+
+	if err = errors.Join(file.Sync(), file.Close()); err != nil {
+		return fmt.Errorf("could not write backup file: %w", err)
+	}
+
+	return os.Rename(tmpFilePath, filePath)
+}
+
+// Restore creates a new database at filePath from the snapshot.
+// Returns the name of the database and the latest block point from which syncing can be resumed
+func Restore(r io.Reader, filePath string) (string, *core.BlockPoint, error) {
+	header, err := core.ReadSnapshotHeader(r)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch header.Database {
+	case leveldb.DatabaseName:
+		err = leveldb.Restore(r, filePath)
+	case bbolt.DatabaseName:
+		err = bbolt.Restore(r, filePath)
+	default:
+		err = fmt.Errorf("unsupported snapshot database: %s", header.Database)
+	}
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return header.Database, header.BlockPoint, nil
+}
+
+func RestoreFromFile(snapshotFilePath string, filePath string) (string, *core.BlockPoint, error) {
+	file, err := os.Open(snapshotFilePath)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not open backup file: %w", err)
+	}
+
+	defer file.Close()
+
+	return Restore(file, filePath)
+}
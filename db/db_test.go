@@ -0,0 +1,976 @@
+package db
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/igorcrevar/cardano-go-indexer/core"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicyID = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b"
+
+var testDatabases = []string{"bbolt", "leveldb"}
+
+// forEachDB runs test in parallel against a new database of each supported type
+func forEachDB(t *testing.T, test func(t *testing.T, dbs core.Database)) {
+	t.Helper()
+
+	for _, name := range testDatabases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbs, err := NewDatabaseInit(name, filepath.Join(t.TempDir(), "test.db"))
+			require.NoError(t, err)
+
+			defer dbs.Close()
+
+			test(t, dbs)
+		})
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	t.Parallel()
+
+	blockPoint := &core.BlockPoint{
+		BlockSlot:   20,
+		BlockHash:   core.Hash{1, 2, 3},
+		BlockNumber: 2,
+	}
+	txOutput := &core.TxInputOutput{
+		Input: core.TxInput{Hash: core.Hash{4, 5}, Index: 1},
+		Output: core.TxOutput{
+			Address: "addr1",
+			Amount:  100,
+			Slot:    20,
+		},
+	}
+	txs := []*core.Tx{{BlockSlot: 20, Hash: core.Hash{4, 5}}}
+
+	for _, name := range testDatabases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			dbs, err := NewDatabaseInit(name, filepath.Join(dir, "source.db"))
+			require.NoError(t, err)
+
+			defer dbs.Close()
+
+			require.NoError(t, dbs.OpenTx().
+				AddConfirmedBlock(&core.CardanoBlock{Slot: 20, Number: 2}).
+				AddTxOutputs([]*core.TxInputOutput{txOutput}).
+				AddConfirmedTxs(txs).
+				SetLatestBlockPoint(blockPoint).
+				Execute())
+
+			var buf bytes.Buffer
+
+			require.NoError(t, dbs.Backup(&buf))
+
+			// writes after the backup must not be visible in the restored database
+			require.NoError(t, dbs.OpenTx().SetLatestBlockPoint(&core.BlockPoint{BlockSlot: 30}).Execute())
+
+			restoredPath := filepath.Join(dir, "restored.db")
+
+			dbName, point, err := Restore(&buf, restoredPath)
+			require.NoError(t, err)
+			require.Equal(t, name, dbName)
+			require.Equal(t, blockPoint, point)
+
+			_, _, err = Restore(bytes.NewReader(nil), restoredPath)
+			require.Error(t, err)
+
+			restored, err := NewDatabaseInit(dbName, restoredPath)
+			require.NoError(t, err)
+
+			defer restored.Close()
+
+			point, err = restored.GetLatestBlockPoint()
+			require.NoError(t, err)
+			require.Equal(t, blockPoint, point)
+
+			output, err := restored.GetTxOutput(txOutput.Input)
+			require.NoError(t, err)
+			require.Equal(t, txOutput.Output, output)
+
+			unprocessedTxs, err := restored.GetUnprocessedConfirmedTxs(0)
+			require.NoError(t, err)
+			require.Equal(t, txs, unprocessedTxs)
+		})
+	}
+}
+
+func TestBackupToFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	backupPath := filepath.Join(dir, "backup.snapshot")
+
+	dbs, err := NewDatabaseInit("", filepath.Join(dir, "source.db"))
+	require.NoError(t, err)
+
+	defer dbs.Close()
+
+	require.NoError(t, BackupToFile(dbs, backupPath))
+
+	_, err = os.Stat(backupPath + ".tmp")
+	require.True(t, os.IsNotExist(err))
+
+	dbName, point, err := RestoreFromFile(backupPath, filepath.Join(dir, "restored.db"))
+	require.NoError(t, err)
+	require.Equal(t, "bbolt", dbName)
+	require.Nil(t, point)
+}
+
+func TestRestore_CorruptedLevelDBBackup(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, core.WriteSnapshotHeader(&buf, core.SnapshotHeader{
+		Version:  core.SnapshotVersion,
+		Database: "leveldb",
+	}))
+
+	// key size which would need huge allocation
+	buf.Write(binary.AppendUvarint(nil, math.MaxUint64))
+
+	_, _, err := Restore(&buf, filepath.Join(t.TempDir(), "restored.db"))
+	require.ErrorContains(t, err, "invalid backup item size")
+}
+
+func TestRestore_TruncatedLevelDBBackup(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	dbs, err := NewDatabaseInit("leveldb", filepath.Join(dir, "source.db"))
+	require.NoError(t, err)
+
+	defer dbs.Close()
+
+	require.NoError(t, dbs.OpenTx().
+		AddConfirmedBlock(&core.CardanoBlock{Slot: 20, Number: 2}).
+		AddConfirmedTxs([]*core.Tx{{BlockSlot: 20, Hash: core.Hash{4, 5}}}).
+		SetLatestBlockPoint(&core.BlockPoint{BlockSlot: 20}).
+		Execute())
+
+	var buf bytes.Buffer
+
+	require.NoError(t, dbs.Backup(&buf))
+
+	backup := buf.Bytes()
+
+	// stream cut at any point, including item boundaries, is not restored
+	for size := range len(backup) {
+		_, _, err := Restore(bytes.NewReader(backup[:size]), filepath.Join(dir, fmt.Sprintf("truncated%d.db", size)))
+		require.Error(t, err, "size %d", size)
+	}
+
+	corrupted := bytes.Clone(backup)
+	corrupted[len(corrupted)-6]++
+
+	_, _, err = Restore(bytes.NewReader(corrupted), filepath.Join(dir, "corrupted.db"))
+	require.Error(t, err)
+
+	_, _, err = Restore(bytes.NewReader(backup), filepath.Join(dir, "restored.db"))
+	require.NoError(t, err)
+}
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	blockPoint := &core.BlockPoint{
+		BlockSlot:   30,
+		BlockHash:   core.Hash{3},
+		BlockNumber: 3,
+	}
+	txOutputs := []*core.TxInputOutput{
+		{
+			Input:  core.TxInput{Hash: core.Hash{1}, Index: 0},
+			Output: core.TxOutput{Address: "addr1", Amount: 10, Slot: 10},
+		},
+		{
+			Input: core.TxInput{Hash: core.Hash{2}, Index: 3},
+			Output: core.TxOutput{Address: "addr1", Amount: 20, Slot: 20, Tokens: []core.TokenAmount{
+				{PolicyID: "ab", Name: "token", Amount: 5},
+			}},
+		},
+	}
+	blocks := []*core.CardanoBlock{
+		{Slot: 10, Number: 1, Txs: []core.Hash{{1}}},
+		{Slot: 20, Number: 2, Txs: []core.Hash{{2}}},
+		{Slot: 30, Number: 3},
+	}
+	processedTxs := []*core.Tx{{BlockSlot: 10, Hash: core.Hash{1}}}
+	unprocessedTxs := []*core.Tx{{BlockSlot: 20, Hash: core.Hash{2}, Metadata: []byte{1, 2}, Valid: true}}
+	mintEvents := []*core.MintEvent{
+		{PolicyID: testPolicyID, Name: "token", Amount: 5, BlockSlot: 20, TxHash: core.Hash{2}},
+	}
+	govProposals := []*core.GovProposalEvent{
+		{Proposal: &core.Proposal{ActionID: core.GovActionID{TxHash: core.Hash{2}}, Type: core.GovActionInfo}},
+	}
+	govVotes := []*core.GovVoteEvent{
+		{Vote: &core.Vote{
+			Voter:    core.Voter{Type: core.VoterTypeDRepKeyHash, Hash: testPolicyID},
+			ActionID: govProposals[0].Proposal.ActionID,
+			Vote:     core.VoteYes,
+		}, BlockSlot: 20, TxHash: core.Hash{2}},
+	}
+
+	source, err := NewDatabaseInit("bbolt", filepath.Join(dir, "source.db"))
+	require.NoError(t, err)
+
+	defer source.Close()
+
+	dbTx := source.OpenTx().
+		SetLatestBlockPoint(blockPoint).
+		AddTxOutputs(txOutputs).
+		AddConfirmedTxs(append(processedTxs, unprocessedTxs...)).
+		AddMintEvents(mintEvents).
+		AddGovProposals(govProposals).
+		AddGovVotes(govVotes)
+	for _, block := range blocks {
+		dbTx.AddConfirmedBlock(block)
+	}
+
+	require.NoError(t, dbTx.Execute())
+	require.NoError(t, source.MarkConfirmedTxsProcessed(processedTxs))
+
+	subscription, err := core.NewSubscription(source, "wallet", 0)
+	require.NoError(t, err)
+	require.NoError(t, subscription.MarkConfirmedTxsProcessed(processedTxs))
+
+	deadLetterTxs := core.NewDeadLetterTxs(
+		blocks[0], unprocessedTxs, errors.New("handler failed"), 3, time.Unix(1000, 0))
+	require.NoError(t, source.OpenTx().AddDeadLetterTxs(deadLetterTxs).Execute())
+
+	var buf bytes.Buffer
+
+	require.NoError(t, core.ExportJSONLines(source, &buf))
+
+	target, err := NewDatabaseInit("leveldb", filepath.Join(dir, "target.db"))
+	require.NoError(t, err)
+
+	defer target.Close()
+
+	require.NoError(t, core.ImportJSONLines(target, &buf, 2))
+
+	point, err := target.GetLatestBlockPoint()
+	require.NoError(t, err)
+	require.Equal(t, blockPoint, point)
+
+	resultOutputs, err := target.GetAllTxOutputs("addr1", false)
+	require.NoError(t, err)
+	require.Equal(t, txOutputs, resultOutputs)
+
+	resultBlocks, err := target.GetConfirmedBlocksFrom(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, blocks, resultBlocks)
+
+	resultTxs, err := target.GetUnprocessedConfirmedTxs(0)
+	require.NoError(t, err)
+	require.Equal(t, unprocessedTxs, resultTxs)
+
+	resultTxs = nil
+
+	require.NoError(t, target.IterateConfirmedTxs(true, func(tx *core.Tx) error {
+		resultTxs = append(resultTxs, tx)
+
+		return nil
+	}))
+	require.Equal(t, processedTxs, resultTxs)
+
+	resultEvents, err := target.GetMintEvents(testPolicyID)
+	require.NoError(t, err)
+	require.Equal(t, mintEvents, resultEvents)
+
+	resultProposal, err := target.GetGovProposal(govProposals[0].Proposal.ActionID)
+	require.NoError(t, err)
+	require.Equal(t, govProposals[0], resultProposal)
+
+	resultVotes, err := target.GetGovVotes(govVotes[0].Vote.Voter)
+	require.NoError(t, err)
+	require.Equal(t, govVotes, resultVotes)
+
+	resultTxs, err = target.GetSubscriberUnprocessedConfirmedTxs("wallet", 0)
+	require.NoError(t, err)
+	require.Equal(t, unprocessedTxs, resultTxs)
+
+	resultDeadLetterTxs, err := target.GetDeadLetterTxs(0)
+	require.NoError(t, err)
+	require.Equal(t, deadLetterTxs, resultDeadLetterTxs)
+
+	require.ErrorContains(t, core.ImportJSONLines(target, bytes.NewReader([]byte(`{"type":"utxo","data":{}}`)), 0),
+		"export header is missing")
+	require.ErrorContains(t, core.ImportJSONLines(target, bytes.NewReader([]byte(
+		`{"type":"header","data":{"version":1}}`+"\n"+`{"type":"unknown","data":{}}`)), 0),
+		"unknown export item type")
+}
+
+func TestMintEvents(t *testing.T) {
+	t.Parallel()
+
+	const otherPolicyID = "ff0102030405060708090a0b0c0d0e0f101112131415161718191a1b"
+
+	forEachDB(t, func(t *testing.T, dbs core.Database) {
+		events := []*core.MintEvent{
+			{PolicyID: testPolicyID, Name: "a", Amount: 100, BlockSlot: 10, TxIndx: 1, TxHash: core.Hash{1}},
+			{PolicyID: testPolicyID, Name: "b", Amount: 7, BlockSlot: 10, TxIndx: 1, TxHash: core.Hash{1}},
+			{PolicyID: testPolicyID, Name: "a", Amount: -30, BlockSlot: 20, TxHash: core.Hash{2}},
+			{PolicyID: testPolicyID, Name: "a", Amount: 5, BlockSlot: 30, TxHash: core.Hash{3}},
+		}
+
+		// written out of order on purpose
+		require.NoError(t, dbs.OpenTx().
+			AddMintEvents(events[2:]).
+			AddMintEvents([]*core.MintEvent{
+				{PolicyID: otherPolicyID, Name: "a", Amount: 1, BlockSlot: 15, TxHash: core.Hash{4}},
+			}).
+			AddMintEvents(events[:2]).
+			Execute())
+
+		result, err := dbs.GetMintEvents(testPolicyID)
+		require.NoError(t, err)
+		require.Equal(t, events, result)
+
+		_, err = dbs.GetMintEvents("xyz")
+		require.Error(t, err)
+
+		history, err := core.GetAssetSupplyHistory(dbs, testPolicyID, "a")
+		require.NoError(t, err)
+		require.Equal(t, []*core.AssetSupply{
+			{Event: events[0], Supply: 100},
+			{Event: events[2], Supply: 70},
+			{Event: events[3], Supply: 75},
+		}, history)
+
+		cnt := 0
+
+		require.NoError(t, dbs.IterateMintEvents(func(event *core.MintEvent) error {
+			cnt++
+
+			return nil
+		}))
+		require.Equal(t, 5, cnt)
+	})
+}
+
+func TestGovernanceEvents(t *testing.T) {
+	t.Parallel()
+
+	drep := core.Voter{Type: core.VoterTypeDRepKeyHash, Hash: testPolicyID}
+	pool := core.Voter{Type: core.VoterTypePoolKeyHash, Hash: testPolicyID}
+
+	forEachDB(t, func(t *testing.T, dbs core.Database) {
+		proposal := &core.GovProposalEvent{
+			Proposal: &core.Proposal{
+				ActionID: core.GovActionID{TxHash: core.Hash{1}, Index: 1},
+				Type:     core.GovActionInfo,
+				Deposit:  100,
+			},
+			BlockSlot: 10,
+		}
+		votes := []*core.GovVoteEvent{
+			{
+				Vote:      &core.Vote{Voter: drep, ActionID: proposal.Proposal.ActionID, Vote: core.VoteNo},
+				BlockSlot: 20,
+				TxHash:    core.Hash{2},
+			},
+			{
+				Vote:      &core.Vote{Voter: drep, ActionID: proposal.Proposal.ActionID, Vote: core.VoteYes},
+				BlockSlot: 30,
+				TxHash:    core.Hash{3},
+			},
+		}
+
+		require.NoError(t, dbs.OpenTx().
+			AddGovProposals([]*core.GovProposalEvent{proposal}).
+			AddGovVotes(votes[1:]).
+			AddGovVotes([]*core.GovVoteEvent{
+				{Vote: &core.Vote{Voter: pool, ActionID: proposal.Proposal.ActionID}, BlockSlot: 25},
+			}).
+			AddGovVotes(votes[:1]).
+			Execute())
+
+		result, err := dbs.GetGovProposal(proposal.Proposal.ActionID)
+		require.NoError(t, err)
+		require.Equal(t, proposal, result)
+
+		result, err = dbs.GetGovProposal(core.GovActionID{TxHash: core.Hash{1}})
+		require.NoError(t, err)
+		require.Nil(t, result)
+
+		resultVotes, err := dbs.GetGovVotes(drep)
+		require.NoError(t, err)
+		require.Equal(t, votes, resultVotes)
+
+		_, err = dbs.GetGovVotes(core.Voter{Type: "unknown", Hash: testPolicyID})
+		require.Error(t, err)
+
+		require.Error(t, dbs.OpenTx().AddGovVotes([]*core.GovVoteEvent{
+			{Vote: &core.Vote{Voter: core.Voter{Type: "unknown", Hash: testPolicyID}}},
+		}).Execute())
+
+		cnt := 0
+
+		require.NoError(t, dbs.IterateGovVotes(func(event *core.GovVoteEvent) error {
+			cnt++
+
+			return nil
+		}))
+		require.NoError(t, dbs.IterateGovProposals(func(event *core.GovProposalEvent) error {
+			cnt++
+
+			return nil
+		}))
+		require.Equal(t, 4, cnt)
+	})
+}
+
+func TestSlotRangeQueries(t *testing.T) {
+	t.Parallel()
+
+	forEachDB(t, func(t *testing.T, dbs core.Database) {
+		blocks := []*core.CardanoBlock{
+			{Slot: 5, Hash: core.Hash{1}},
+			{Slot: 10, Hash: core.Hash{2}},
+			{Slot: 20, Hash: core.Hash{3}},
+			{Slot: 30, Hash: core.Hash{4}},
+		}
+		txs := []*core.Tx{
+			{BlockSlot: 10, Indx: 0, Hash: core.Hash{1}},
+			{BlockSlot: 10, Indx: 1, Hash: core.Hash{2}},
+			{BlockSlot: 20, Indx: 0, Hash: core.Hash{3}},
+			{BlockSlot: 30, Indx: 0, Hash: core.Hash{4}},
+		}
+
+		dbTx := dbs.OpenTx()
+		for _, block := range blocks {
+			dbTx.AddConfirmedBlock(block)
+		}
+
+		require.NoError(t, dbTx.AddConfirmedTxs(txs).Execute())
+		require.NoError(t, dbs.MarkConfirmedTxsProcessed([]*core.Tx{txs[1], txs[3]}))
+
+		resultBlocks, err := dbs.GetConfirmedBlocksInSlotRange(10, 20)
+		require.NoError(t, err)
+		require.Equal(t, blocks[1:3], resultBlocks)
+
+		resultTxs, err := dbs.GetConfirmedTxsInSlotRange(6, 20)
+		require.NoError(t, err)
+		require.Equal(t, txs[:3], resultTxs)
+
+		resultTxs, err = dbs.GetConfirmedTxsInSlotRange(30, math.MaxUint64)
+		require.NoError(t, err)
+		require.Equal(t, txs[3:], resultTxs)
+
+		resultBlocks, err = dbs.GetConfirmedBlocksInSlotRange(21, 29)
+		require.NoError(t, err)
+		require.Empty(t, resultBlocks)
+	})
+}
+
+func TestSubscribers(t *testing.T) {
+	t.Parallel()
+
+	forEachDB(t, func(t *testing.T, dbs core.Database) {
+		txs := []*core.Tx{
+			{BlockSlot: 10, Indx: 0, Hash: core.Hash{1}},
+			{BlockSlot: 10, Indx: 1, Hash: core.Hash{2}},
+			{BlockSlot: 20, Indx: 0, Hash: core.Hash{3}},
+			{BlockSlot: 30, Indx: 0, Hash: core.Hash{4}},
+		}
+
+		require.NoError(t, dbs.OpenTx().AddConfirmedTxs(txs).Execute())
+		// the default consumer does not affect subscribers
+		require.NoError(t, dbs.MarkConfirmedTxsProcessed(txs[:3]))
+
+		wallet, err := core.NewSubscription(dbs, "wallet", 0)
+		require.NoError(t, err)
+
+		exchange, err := core.NewSubscription(dbs, "exchange", 20)
+		require.NoError(t, err)
+
+		resultTxs, err := wallet.GetUnprocessedConfirmedTxs(3)
+		require.NoError(t, err)
+		require.Equal(t, txs[:3], resultTxs)
+
+		resultTxs, err = exchange.GetUnprocessedConfirmedTxs(0)
+		require.NoError(t, err)
+		require.Equal(t, txs[2:], resultTxs)
+
+		require.NoError(t, wallet.MarkConfirmedTxsProcessed(txs[:2]))
+		require.NoError(t, exchange.MarkConfirmedTxsProcessed(txs[2:]))
+		// cursor is never moved backwards
+		require.NoError(t, wallet.MarkConfirmedTxsProcessed(txs[:1]))
+
+		resultTxs, err = wallet.GetUnprocessedConfirmedTxs(0)
+		require.NoError(t, err)
+		require.Equal(t, txs[2:], resultTxs)
+
+		resultTxs, err = exchange.GetUnprocessedConfirmedTxs(0)
+		require.NoError(t, err)
+		require.Empty(t, resultTxs)
+
+		// registering existing subscriber keeps its cursor
+		wallet, err = core.NewSubscription(dbs, "wallet", 0)
+		require.NoError(t, err)
+
+		resultTxs, err = wallet.GetUnprocessedConfirmedTxs(1)
+		require.NoError(t, err)
+		require.Equal(t, txs[2:3], resultTxs)
+
+		subscribers, err := dbs.GetSubscribers()
+		require.NoError(t, err)
+		require.Equal(t, []*core.Subscriber{
+			{Name: "exchange", Cursor: txs[3].Key()},
+			{Name: "wallet", Cursor: txs[1].Key()},
+		}, subscribers)
+
+		require.NoError(t, dbs.UnregisterSubscriber("exchange"))
+
+		_, err = exchange.GetUnprocessedConfirmedTxs(0)
+		require.ErrorIs(t, err, core.ErrSubscriberNotFound)
+		require.ErrorIs(t, exchange.MarkConfirmedTxsProcessed(txs), core.ErrSubscriberNotFound)
+
+		_, err = core.NewSubscription(dbs, "", 0)
+		require.Error(t, err)
+	})
+}
+
+func TestDeadLetterTxs(t *testing.T) {
+	t.Parallel()
+
+	forEachDB(t, func(t *testing.T, dbs core.Database) {
+		blocks := []*core.CardanoBlock{
+			{Slot: 10, Hash: core.Hash{10}},
+			{Slot: 15, Hash: core.Hash{15}},
+			{Slot: 20, Hash: core.Hash{20}},
+		}
+		txs := []*core.Tx{
+			{BlockSlot: 20, Indx: 0, Hash: core.Hash{3}},
+			{BlockSlot: 10, Indx: 1, Hash: core.Hash{2}},
+			{BlockSlot: 10, Indx: 0, Hash: core.Hash{1}},
+		}
+		handlerErr := errors.New("handler failed")
+
+		require.NoError(t, dbs.OpenTx().
+			AddDeadLetterTxs(core.NewDeadLetterTxs(blocks[2], txs[:1], handlerErr, 3, time.Unix(1000, 0))).
+			AddDeadLetterTxs(core.NewDeadLetterTxs(blocks[0], txs[1:], handlerErr, 3, time.Unix(1000, 0))).
+			AddDeadLetterTxs(core.NewDeadLetterTxs(blocks[1], nil, handlerErr, 3, time.Unix(1000, 0))).
+			Execute())
+
+		deadLetterTxs, err := dbs.GetDeadLetterTxs(2)
+		require.NoError(t, err)
+		require.Equal(t, []*core.DeadLetterTx{
+			{Block: blocks[0], Tx: txs[2], Error: handlerErr.Error(), Attempts: 3, FailedAt: 1000},
+			{Block: blocks[0], Tx: txs[1], Error: handlerErr.Error(), Attempts: 3, FailedAt: 1000},
+		}, deadLetterTxs)
+
+		var (
+			handledBlocks []*core.CardanoBlock
+			handledTxs    [][]*core.Tx
+		)
+
+		cnt, err := core.ReplayDeadLetterTxs(dbs, func(block *core.CardanoBlock, txs []*core.Tx) error {
+			if block.Slot == 20 {
+				return errors.New("still failing")
+			}
+
+			handledBlocks = append(handledBlocks, block)
+			handledTxs = append(handledTxs, txs)
+
+			return nil
+		}, 0)
+		require.NoError(t, err)
+		require.Equal(t, 3, cnt)
+		require.Equal(t, []*core.CardanoBlock{blocks[0], blocks[1]}, handledBlocks)
+		require.Equal(t, [][]*core.Tx{{txs[2], txs[1]}, nil}, handledTxs)
+
+		deadLetterTxs, err = dbs.GetDeadLetterTxs(0)
+		require.NoError(t, err)
+		require.Len(t, deadLetterTxs, 1)
+		require.Equal(t, txs[0], deadLetterTxs[0].Tx)
+		require.Equal(t, "still failing", deadLetterTxs[0].Error)
+		require.Equal(t, 4, deadLetterTxs[0].Attempts)
+
+		cnt, err = core.ReplayDeadLetterTxs(dbs, func(block *core.CardanoBlock, txs []*core.Tx) error {
+			return nil
+		}, 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, cnt)
+
+		deadLetterTxs, err = dbs.GetDeadLetterTxs(0)
+		require.NoError(t, err)
+		require.Empty(t, deadLetterTxs)
+	})
+}
+
+func TestEventBus(t *testing.T) {
+	t.Parallel()
+
+	forEachDB(t, func(t *testing.T, dbs core.Database) {
+		blocks := []*core.CardanoBlock{
+			{Slot: 10, Hash: core.Hash{1}, Number: 1, Txs: []core.Hash{{11}}},
+			{Slot: 20, Hash: core.Hash{2}, Number: 2},
+			{Slot: 30, Hash: core.Hash{3}, Number: 3, Txs: []core.Hash{{31}}},
+		}
+		utxo := &core.TxInputOutput{Input: core.TxInput{Hash: core.Hash{11}}, Output: core.TxOutput{Amount: 100}}
+		txs := []*core.Tx{
+			{BlockSlot: 10, Indx: 0, Hash: core.Hash{11}, Valid: true},
+			{BlockSlot: 30, Indx: 0, Hash: core.Hash{31}, Valid: true, Inputs: []*core.TxInputOutput{utxo}},
+		}
+
+		addBlock := func(block *core.CardanoBlock, txs []*core.Tx) {
+			require.NoError(t, dbs.OpenTx().AddConfirmedBlock(block).AddConfirmedTxs(txs).Execute())
+		}
+
+		readEvents := func(ch <-chan core.Event, cnt int) (result []core.Event) {
+			for len(result) < cnt {
+				select {
+				case event := <-ch:
+					result = append(result, event)
+				case <-time.After(5 * time.Second):
+					require.Fail(t, "events not received")
+				}
+			}
+
+			return result
+		}
+
+		addBlock(blocks[0], txs[:1])
+		addBlock(blocks[1], nil)
+
+		bus := core.NewEventBus(core.EventBusConfig{BatchSize: 1}, dbs, hclog.NewNullLogger())
+		ch := bus.Subscribe()
+
+		require.NoError(t, bus.Start())
+		require.Equal(t, []core.Event{
+			{Type: core.EventBlockConfirmed, Block: blocks[0]},
+			{Type: core.EventTxConfirmed, Tx: txs[0]},
+			{Type: core.EventBlockConfirmed, Block: blocks[1]},
+		}, readEvents(ch, 3))
+		require.NoError(t, bus.Close())
+
+		// after restart only events of the new block are published
+		addBlock(blocks[2], txs[1:])
+
+		bus = core.NewEventBus(core.EventBusConfig{}, dbs, hclog.NewNullLogger())
+		ch = bus.Subscribe()
+
+		require.NoError(t, bus.Start())
+		require.Equal(t, []core.Event{
+			{Type: core.EventBlockConfirmed, Block: blocks[2]},
+			{Type: core.EventTxConfirmed, Tx: txs[1]},
+			{Type: core.EventUtxoSpent, Tx: txs[1], Utxo: utxo},
+		}, readEvents(ch, 3))
+		require.NoError(t, bus.Close())
+
+		subscribers, err := dbs.GetSubscribers()
+		require.NoError(t, err)
+		require.Len(t, subscribers, 1)
+		require.Equal(t, uint64(30), subscribers[0].CursorSlot())
+	})
+}
+
+func TestVerifyDatabase(t *testing.T) {
+	t.Parallel()
+
+	forEachDB(t, func(t *testing.T, dbs core.Database) {
+		report, err := core.VerifyDatabase(dbs, false)
+		require.NoError(t, err)
+		require.True(t, report.IsValid())
+
+		block1 := &core.CardanoBlock{Slot: 10, Number: 1, Hash: core.Hash{1}, Txs: []core.Hash{{11}}}
+		block2 := &core.CardanoBlock{Slot: 20, Number: 2, Hash: core.Hash{2}}
+		validTx := &core.Tx{BlockSlot: 10, BlockHash: core.Hash{1}, Hash: core.Hash{11}}
+		notInBlockTx := &core.Tx{BlockSlot: 20, BlockHash: core.Hash{2}, Hash: core.Hash{22}}
+		orphanTx := &core.Tx{BlockSlot: 15, BlockHash: core.Hash{15}, Hash: core.Hash{33}}
+
+		require.NoError(t, dbs.OpenTx().
+			AddConfirmedBlock(block1).
+			AddConfirmedBlock(block2).
+			SetLatestBlockPoint(&core.BlockPoint{BlockSlot: 10, BlockHash: core.Hash{1}, BlockNumber: 1}).
+			AddConfirmedTxs([]*core.Tx{validTx, notInBlockTx, orphanTx}).
+			AddTxOutputs([]*core.TxInputOutput{
+				{Input: core.TxInput{Hash: core.Hash{22}}, Output: core.TxOutput{Slot: 20}},
+			}).
+			Execute())
+		require.NoError(t, dbs.MarkConfirmedTxsProcessed([]*core.Tx{validTx}))
+		require.NoError(t, dbs.OpenTx().AddConfirmedTxs([]*core.Tx{validTx}).Execute())
+
+		report, err = core.VerifyDatabase(dbs, false)
+		require.NoError(t, err)
+		require.False(t, report.IsValid())
+
+		issueTypes := make([]core.VerifyIssueType, len(report.Issues))
+		for i, issue := range report.Issues {
+			require.False(t, issue.Repaired)
+
+			issueTypes[i] = issue.Type
+		}
+
+		require.Equal(t, []core.VerifyIssueType{
+			core.VerifyIssueLatestBlockPoint,
+			core.VerifyIssueTxInBothQueues,
+			core.VerifyIssueMissingBlock,
+			core.VerifyIssueTxNotInBlock,
+			core.VerifyIssueTxOutputSlot,
+		}, issueTypes)
+
+		report, err = core.VerifyDatabase(dbs, true)
+		require.NoError(t, err)
+		require.Len(t, report.Issues, 4)
+
+		for _, issue := range report.Issues {
+			require.True(t, issue.Repaired)
+		}
+
+		report, err = core.VerifyDatabase(dbs, false)
+		require.NoError(t, err)
+		require.True(t, report.IsValid(), report.Issues)
+
+		unprocessedTxs, err := dbs.GetUnprocessedConfirmedTxs(0)
+		require.NoError(t, err)
+		require.Equal(t, []*core.Tx{notInBlockTx}, unprocessedTxs)
+
+		blocks, err := dbs.GetLatestConfirmedBlocks(1)
+		require.NoError(t, err)
+		require.Equal(t, []core.Hash{{22}}, blocks[0].Txs)
+	})
+}
+
+func TestVerifyDatabase_EpochBoundaryBlock(t *testing.T) {
+	t.Parallel()
+
+	forEachDB(t, func(t *testing.T, dbs core.Database) {
+		// genesis EBB is confirmed before any block is stored
+		require.NoError(t, dbs.OpenTx().SetLatestBlockPoint(&core.BlockPoint{BlockHash: core.Hash{1}}).Execute())
+
+		report, err := core.VerifyDatabase(dbs, false)
+		require.NoError(t, err)
+		require.True(t, report.IsValid(), report.Issues)
+
+		// EBB is not stored and has the same number as the block before it
+		ebbPoint := &core.BlockPoint{BlockSlot: 21600, BlockHash: core.Hash{3}, BlockNumber: 2}
+
+		require.NoError(t, dbs.OpenTx().
+			AddConfirmedBlock(&core.CardanoBlock{Slot: 21599, Number: 2, Hash: core.Hash{2}}).
+			SetLatestBlockPoint(ebbPoint).
+			Execute())
+
+		report, err = core.VerifyDatabase(dbs, true)
+		require.NoError(t, err)
+		require.True(t, report.IsValid(), report.Issues)
+
+		point, err := dbs.GetLatestBlockPoint()
+		require.NoError(t, err)
+		require.Equal(t, ebbPoint, point)
+	})
+}
+
+func TestVerifyDatabase_EventIndexes(t *testing.T) {
+	t.Parallel()
+
+	drep := core.Voter{Type: core.VoterTypeDRepKeyHash, Hash: testPolicyID}
+	actionID := core.GovActionID{TxHash: core.Hash{11}, Index: 0}
+
+	forEachDB(t, func(t *testing.T, dbs core.Database) {
+		block := &core.CardanoBlock{Slot: 10, Number: 1, Hash: core.Hash{1}, Txs: []core.Hash{{11}}}
+		tx := &core.Tx{
+			BlockSlot: 10,
+			BlockHash: core.Hash{1},
+			Hash:      core.Hash{11},
+			Valid:     true,
+			Mint:      []core.MintAmount{{PolicyID: testPolicyID, Name: "a", Amount: 10}},
+			Proposals: []*core.Proposal{{ActionID: actionID, Type: core.GovActionInfo, Deposit: 100}},
+			Votes:     []*core.Vote{{Voter: drep, ActionID: actionID, Vote: core.VoteYes}},
+		}
+		mintEvents := core.NewMintEvents([]*core.Tx{tx})
+		_, votes := core.NewGovEvents([]*core.Tx{tx})
+
+		require.NoError(t, dbs.OpenTx().
+			AddConfirmedBlock(block).
+			SetLatestBlockPoint(&core.BlockPoint{BlockSlot: 10, BlockHash: core.Hash{1}, BlockNumber: 1}).
+			AddConfirmedTxs([]*core.Tx{tx}).
+			AddMintEvents([]*core.MintEvent{{
+				PolicyID: testPolicyID, Name: "a", Amount: 5, BlockSlot: 10, TxHash: core.Hash{11},
+			}}).
+			AddGovVotes(votes).
+			Execute())
+
+		report, err := core.VerifyDatabase(dbs, false)
+		require.NoError(t, err)
+
+		issueTypes := make([]core.VerifyIssueType, len(report.Issues))
+		for i, issue := range report.Issues {
+			issueTypes[i] = issue.Type
+		}
+
+		require.Equal(t, []core.VerifyIssueType{
+			core.VerifyIssueMintEventIndex,
+			core.VerifyIssueGovProposalIndex,
+		}, issueTypes)
+
+		report, err = core.VerifyDatabase(dbs, true)
+		require.NoError(t, err)
+		require.Len(t, report.Issues, 2)
+
+		report, err = core.VerifyDatabase(dbs, false)
+		require.NoError(t, err)
+		require.True(t, report.IsValid(), report.Issues)
+
+		events, err := dbs.GetMintEvents(testPolicyID)
+		require.NoError(t, err)
+		require.Equal(t, mintEvents, events)
+
+		proposal, err := dbs.GetGovProposal(actionID)
+		require.NoError(t, err)
+		require.Equal(t, tx.Proposals[0], proposal.Proposal)
+	})
+}
+
+func TestNewDatabaseInit_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range testDatabases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filePath := filepath.Join(t.TempDir(), "test.db")
+			blockPoint := &core.BlockPoint{BlockSlot: 10, BlockNumber: 1}
+
+			_, err := NewDatabaseInit(name, filePath, ReadOnly())
+			require.Error(t, err)
+
+			dbs, err := NewDatabaseInit(name, filePath)
+			require.NoError(t, err)
+			require.NoError(t, dbs.OpenTx().
+				SetLatestBlockPoint(blockPoint).
+				AddConfirmedBlock(&core.CardanoBlock{Slot: 10, Number: 1}).
+				Execute())
+			require.NoError(t, dbs.Close())
+
+			reader, err := NewDatabaseInit(name, filePath, ReadOnly())
+			require.NoError(t, err)
+
+			defer reader.Close()
+
+			point, err := reader.GetLatestBlockPoint()
+			require.NoError(t, err)
+			require.Equal(t, blockPoint, point)
+
+			blocks, err := reader.GetLatestConfirmedBlocks(0)
+			require.NoError(t, err)
+			require.Len(t, blocks, 1)
+
+			var buf bytes.Buffer
+
+			require.NoError(t, reader.Backup(&buf))
+
+			require.ErrorIs(t, reader.OpenTx().SetLatestBlockPoint(nil).Execute(), core.ErrReadOnlyDatabase)
+			require.ErrorIs(t, reader.MarkConfirmedTxsProcessed(nil), core.ErrReadOnlyDatabase)
+
+			_, err = reader.PruneProcessedTxs(100, 0)
+			require.ErrorIs(t, err, core.ErrReadOnlyDatabase)
+		})
+	}
+}
+
+// TestReadOnlyHelperProcess opens the database read-only when it is started by TestNewDatabaseInit_OtherProcess
+func TestReadOnlyHelperProcess(t *testing.T) {
+	name, filePath := os.Getenv("TEST_READ_ONLY_DB_NAME"), os.Getenv("TEST_READ_ONLY_DB_PATH")
+	if filePath == "" {
+		t.Skip("started only as a separate process")
+	}
+
+	reader, err := NewDatabaseInit(name, filePath, ReadOnly())
+	require.NoError(t, err)
+
+	defer reader.Close()
+
+	point, err := reader.GetLatestBlockPoint()
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), point.BlockSlot)
+}
+
+func TestNewDatabaseInit_OtherProcess(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range testDatabases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filePath := filepath.Join(t.TempDir(), "test.db")
+			readInOtherProcess := func() error {
+				cmd := exec.Command(os.Args[0], "-test.run=^TestReadOnlyHelperProcess$") //nolint:gosec
+				cmd.Env = append(os.Environ(), "TEST_READ_ONLY_DB_NAME="+name, "TEST_READ_ONLY_DB_PATH="+filePath)
+
+				output, err := cmd.CombinedOutput()
+				if err != nil {
+					return fmt.Errorf("%w: %s", err, output)
+				}
+
+				return nil
+			}
+
+			dbs, err := NewDatabaseInit(name, filePath)
+			require.NoError(t, err)
+			require.NoError(t, dbs.OpenTx().SetLatestBlockPoint(&core.BlockPoint{BlockSlot: 10}).Execute())
+
+			// the writer holds the file lock, so the database can not be opened by another process
+			require.ErrorContains(t, readInOtherProcess(), "could not open db")
+
+			require.NoError(t, dbs.Close())
+			require.NoError(t, readInOtherProcess())
+		})
+	}
+}
+
+func TestNewDatabaseSnapshotReader(t *testing.T) {
+	forEachDB(t, func(t *testing.T, dbs core.Database) {
+		blockPoint := &core.BlockPoint{BlockSlot: 10, BlockNumber: 1}
+
+		require.NoError(t, dbs.OpenTx().
+			SetLatestBlockPoint(blockPoint).
+			AddConfirmedBlock(&core.CardanoBlock{Slot: 10, Number: 1}).
+			Execute())
+
+		// writer stays opened while the reader is created and used
+		reader, err := NewDatabaseSnapshotReader(dbs, filepath.Join(t.TempDir(), "reader.db"))
+		require.NoError(t, err)
+
+		defer reader.Close()
+
+		require.NoError(t, dbs.OpenTx().
+			SetLatestBlockPoint(&core.BlockPoint{BlockSlot: 20, BlockNumber: 2}).
+			AddConfirmedBlock(&core.CardanoBlock{Slot: 20, Number: 2}).
+			Execute())
+
+		point, err := reader.GetLatestBlockPoint()
+		require.NoError(t, err)
+		require.Equal(t, blockPoint, point)
+
+		blocks, err := reader.GetLatestConfirmedBlocks(0)
+		require.NoError(t, err)
+		require.Len(t, blocks, 1)
+
+		blocks, err = dbs.GetLatestConfirmedBlocks(0)
+		require.NoError(t, err)
+		require.Len(t, blocks, 2)
+	})
+}
@@ -5,17 +5,30 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"sort"
+	"sync"
 
 	"github.com/igorcrevar/cardano-go-indexer/core"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
+// dbReader is implemented by both leveldb.DB and leveldb.Snapshot
+type dbReader interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
 type LevelDBDatabase struct {
-	db *leveldb.DB
+	db       *leveldb.DB
+	reader   dbReader
+	snapshot *leveldb.Snapshot
+	// leveldb has no read-write transactions, so subscriber updates are serialized
+	subscribersLock sync.Mutex
 }
 
 var (
@@ -24,8 +37,15 @@ var (
 	processedTxsBucket     = []byte("P3_")
 	unprocessedTxsBucket   = []byte("P4_")
 	confirmedBlocks        = []byte("P5_")
+	mintEventsBucket       = []byte("P6_")
+	govProposalsBucket     = []byte("P7_")
+	govVotesBucket         = []byte("P8_")
+	subscribersBucket      = []byte("P9_")
+	deadLetterTxsBucket    = []byte("P10_")
 )
 
+const bucketKeySeparator = "_#_"
+
 var _ core.Database = (*LevelDBDatabase)(nil)
 
 func (lvldb *LevelDBDatabase) Init(filePath string) error {
@@ -35,18 +55,52 @@ func (lvldb *LevelDBDatabase) Init(filePath string) error {
 	}
 
 	lvldb.db = db
+	lvldb.reader = db
+
+	return nil
+}
+
+// InitReadOnly opens existing database in read-only mode and serves all reads from a single snapshot.
+// Read-only mode still acquires the LOCK file, so it fails if the database is opened by another process
+func (lvldb *LevelDBDatabase) InitReadOnly(filePath string) error {
+	db, err := leveldb.OpenFile(filePath, &opt.Options{
+		ReadOnly:       true,
+		ErrorIfMissing: true,
+	})
+	if err != nil {
+		return fmt.Errorf("could not open db: %w", err)
+	}
+
+	snapshot, err := db.GetSnapshot()
+	if err != nil {
+		db.Close() //nolint:errcheck
+
+		return fmt.Errorf("could not create snapshot: %w", err)
+	}
+
+	lvldb.db = db
+	lvldb.reader = snapshot
+	lvldb.snapshot = snapshot
 
 	return nil
 }
 
 func (lvldb *LevelDBDatabase) Close() error {
+	if lvldb.snapshot != nil {
+		lvldb.snapshot.Release()
+	}
+
 	return lvldb.db.Close()
 }
 
+func (lvldb *LevelDBDatabase) isReadOnly() bool {
+	return lvldb.snapshot != nil
+}
+
 func (lvldb *LevelDBDatabase) GetLatestBlockPoint() (*core.BlockPoint, error) {
 	var result *core.BlockPoint
 
-	bytes, err := lvldb.db.Get(latestBlockPointBucket, nil)
+	bytes, err := lvldb.reader.Get(latestBlockPointBucket, nil)
 	if err != nil {
 		return nil, processNotFoundErr(err)
 	}
@@ -59,7 +113,7 @@ func (lvldb *LevelDBDatabase) GetLatestBlockPoint() (*core.BlockPoint, error) {
 }
 
 func (lvldb *LevelDBDatabase) GetTxOutput(txInput core.TxInput) (result core.TxOutput, err error) {
-	bytes, err := lvldb.db.Get(bucketKey(txOutputsBucket, txInput.Key()), nil)
+	bytes, err := lvldb.reader.Get(bucketKey(txOutputsBucket, txInput.Key()), nil)
 	if err != nil {
 		return result, processNotFoundErr(err)
 	}
@@ -70,6 +124,10 @@ func (lvldb *LevelDBDatabase) GetTxOutput(txInput core.TxInput) (result core.TxO
 }
 
 func (lvldb *LevelDBDatabase) MarkConfirmedTxsProcessed(txs []*core.Tx) error {
+	if lvldb.isReadOnly() {
+		return core.ErrReadOnlyDatabase
+	}
+
 	batch := new(leveldb.Batch)
 
 	for _, tx := range txs {
@@ -91,7 +149,7 @@ func (lvldb *LevelDBDatabase) MarkConfirmedTxsProcessed(txs []*core.Tx) error {
 func (lvldb *LevelDBDatabase) GetUnprocessedConfirmedTxs(maxCnt int) ([]*core.Tx, error) {
 	var result []*core.Tx
 
-	iter := lvldb.db.NewIterator(util.BytesPrefix(unprocessedTxsBucket), nil)
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(unprocessedTxsBucket), nil)
 	defer iter.Release()
 
 	for iter.Next() {
@@ -113,7 +171,7 @@ func (lvldb *LevelDBDatabase) GetUnprocessedConfirmedTxs(maxCnt int) ([]*core.Tx
 func (lvldb *LevelDBDatabase) GetLatestConfirmedBlocks(maxCnt int) ([]*core.CardanoBlock, error) {
 	var result []*core.CardanoBlock
 
-	iter := lvldb.db.NewIterator(util.BytesPrefix(confirmedBlocks), nil)
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(confirmedBlocks), nil)
 	defer iter.Release()
 
 	for ok := iter.Last(); ok; ok = iter.Prev() {
@@ -135,7 +193,7 @@ func (lvldb *LevelDBDatabase) GetLatestConfirmedBlocks(maxCnt int) ([]*core.Card
 func (lvldb *LevelDBDatabase) GetConfirmedBlocksFrom(slotNumber uint64, maxCnt int) ([]*core.CardanoBlock, error) {
 	var result []*core.CardanoBlock
 
-	iter := lvldb.db.NewIterator(util.BytesPrefix(confirmedBlocks), nil)
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(confirmedBlocks), nil)
 	defer iter.Release()
 
 	for ok := iter.Seek(bucketKey(confirmedBlocks, core.SlotNumberToKey(slotNumber))); ok; ok = iter.Next() {
@@ -154,10 +212,73 @@ func (lvldb *LevelDBDatabase) GetConfirmedBlocksFrom(slotNumber uint64, maxCnt i
 	return result, iter.Error()
 }
 
+func (lvldb *LevelDBDatabase) GetConfirmedBlocksInSlotRange(
+	fromSlot uint64, toSlot uint64,
+) ([]*core.CardanoBlock, error) {
+	var result []*core.CardanoBlock
+
+	iter := lvldb.reader.NewIterator(&util.Range{
+		Start: bucketKey(confirmedBlocks, core.SlotNumberToKey(fromSlot)),
+		Limit: bucketKey(confirmedBlocks, slotRangeLimitKey(toSlot)),
+	}, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var block *core.CardanoBlock
+
+		if err := json.Unmarshal(iter.Value(), &block); err != nil {
+			return nil, err
+		}
+
+		result = append(result, block)
+	}
+
+	return result, iter.Error()
+}
+
+func (lvldb *LevelDBDatabase) GetConfirmedTxsInSlotRange(fromSlot uint64, toSlot uint64) ([]*core.Tx, error) {
+	var result []*core.Tx
+
+	// both buckets are read from the same snapshot, so tx marked as processed in between is not missed
+	reader, release, err := lvldb.getSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	for _, bucketName := range [][]byte{unprocessedTxsBucket, processedTxsBucket} {
+		err := func() error {
+			iter := reader.NewIterator(&util.Range{
+				Start: bucketKey(bucketName, core.SlotNumberToKey(fromSlot)),
+				Limit: bucketKey(bucketName, slotRangeLimitKey(toSlot)),
+			}, nil)
+			defer iter.Release()
+
+			for iter.Next() {
+				var tx *core.Tx
+
+				if err := json.Unmarshal(iter.Value(), &tx); err != nil {
+					return err
+				}
+
+				result = append(result, tx)
+			}
+
+			return iter.Error()
+		}()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return core.SortTxs(result), nil
+}
+
 func (lvldb *LevelDBDatabase) GetAllTxOutputs(address string, onlyNotUsed bool) ([]*core.TxInputOutput, error) {
 	var result []*core.TxInputOutput
 
-	iter := lvldb.db.NewIterator(util.BytesPrefix(txOutputsBucket), nil)
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(txOutputsBucket), nil)
 	defer iter.Release()
 
 	for iter.Next() {
@@ -171,7 +292,10 @@ func (lvldb *LevelDBDatabase) GetAllTxOutputs(address string, onlyNotUsed bool)
 			continue
 		}
 
-		input, _ := core.NewTxInputFromBytes(iter.Key())
+		input, err := core.NewTxInputFromBytes(bucketKeyToKey(txOutputsBucket, iter.Key()))
+		if err != nil {
+			return nil, err
+		}
 
 		result = append(result, &core.TxInputOutput{
 			Input:  input,
@@ -188,21 +312,496 @@ func (lvldb *LevelDBDatabase) GetAllTxOutputs(address string, onlyNotUsed bool)
 	return result, nil
 }
 
+func (lvldb *LevelDBDatabase) GetMintEvents(policyID string) ([]*core.MintEvent, error) {
+	var result []*core.MintEvent
+
+	prefix, err := core.PolicyIDToKey(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(bucketKey(mintEventsBucket, prefix)), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var event *core.MintEvent
+
+		if err := json.Unmarshal(iter.Value(), &event); err != nil {
+			return nil, err
+		}
+
+		result = append(result, event)
+	}
+
+	return result, iter.Error()
+}
+
+func (lvldb *LevelDBDatabase) GetGovProposal(actionID core.GovActionID) (result *core.GovProposalEvent, err error) {
+	bytes, err := lvldb.reader.Get(bucketKey(govProposalsBucket, actionID.Key()), nil)
+	if err != nil {
+		return nil, processNotFoundErr(err)
+	}
+
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (lvldb *LevelDBDatabase) GetGovVotes(voter core.Voter) ([]*core.GovVoteEvent, error) {
+	var result []*core.GovVoteEvent
+
+	prefix, err := core.VoterToKey(voter)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(bucketKey(govVotesBucket, prefix)), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var event *core.GovVoteEvent
+
+		if err := json.Unmarshal(iter.Value(), &event); err != nil {
+			return nil, err
+		}
+
+		result = append(result, event)
+	}
+
+	return result, iter.Error()
+}
+
+func (lvldb *LevelDBDatabase) RegisterSubscriber(name string, fromSlot uint64) (*core.Subscriber, error) {
+	if lvldb.isReadOnly() {
+		return nil, core.ErrReadOnlyDatabase
+	}
+
+	lvldb.subscribersLock.Lock()
+	defer lvldb.subscribersLock.Unlock()
+
+	subscriber, err := lvldb.getSubscriber(lvldb.reader, name)
+	if err == nil {
+		return subscriber, nil
+	} else if !errors.Is(err, core.ErrSubscriberNotFound) {
+		return nil, err
+	}
+
+	subscriber = core.NewSubscriber(name, fromSlot)
+
+	if err := lvldb.putSubscriber(subscriber); err != nil {
+		return nil, err
+	}
+
+	return subscriber, nil
+}
+
+func (lvldb *LevelDBDatabase) UnregisterSubscriber(name string) error {
+	if lvldb.isReadOnly() {
+		return core.ErrReadOnlyDatabase
+	}
+
+	lvldb.subscribersLock.Lock()
+	defer lvldb.subscribersLock.Unlock()
+
+	return lvldb.db.Delete(bucketKey(subscribersBucket, []byte(name)), &opt.WriteOptions{
+		Sync: true,
+	})
+}
+
+func (lvldb *LevelDBDatabase) GetSubscribers() ([]*core.Subscriber, error) {
+	var result []*core.Subscriber
+
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(subscribersBucket), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var subscriber *core.Subscriber
+
+		if err := json.Unmarshal(iter.Value(), &subscriber); err != nil {
+			return nil, err
+		}
+
+		result = append(result, subscriber)
+	}
+
+	return result, iter.Error()
+}
+
+func (lvldb *LevelDBDatabase) GetSubscriberUnprocessedConfirmedTxs(name string, maxCnt int) ([]*core.Tx, error) {
+	var result []*core.Tx
+
+	// subscriber and both buckets are read from the same snapshot, so tx marked as processed
+	// in between is neither missed nor returned twice
+	reader, release, err := lvldb.getSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	subscriber, err := lvldb.getSubscriber(reader, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// tx is either processed or unprocessed, so at most maxCnt txs are needed from each bucket
+	for _, bucketName := range [][]byte{unprocessedTxsBucket, processedTxsBucket} {
+		err := func() error {
+			iter := reader.NewIterator(util.BytesPrefix(bucketName), nil)
+			defer iter.Release()
+
+			cnt := 0
+
+			for ok := iter.Seek(bucketKey(bucketName, subscriber.Cursor)); ok; ok = iter.Next() {
+				if !subscriber.IsAfterCursor(bucketKeyToKey(bucketName, iter.Key())) {
+					continue
+				}
+
+				var tx *core.Tx
+
+				if err := json.Unmarshal(iter.Value(), &tx); err != nil {
+					return err
+				}
+
+				result = append(result, tx)
+				if cnt++; maxCnt > 0 && cnt == maxCnt {
+					break
+				}
+			}
+
+			return iter.Error()
+		}()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return core.SortAndLimitTxs(result, maxCnt), nil
+}
+
+func (lvldb *LevelDBDatabase) MarkSubscriberConfirmedTxsProcessed(name string, txs []*core.Tx) error {
+	if lvldb.isReadOnly() {
+		return core.ErrReadOnlyDatabase
+	}
+
+	lvldb.subscribersLock.Lock()
+	defer lvldb.subscribersLock.Unlock()
+
+	subscriber, err := lvldb.getSubscriber(lvldb.reader, name)
+	if err != nil {
+		return err
+	}
+
+	subscriber.MoveCursor(txs)
+
+	return lvldb.putSubscriber(subscriber)
+}
+
+func (lvldb *LevelDBDatabase) GetDeadLetterTxs(maxCnt int) ([]*core.DeadLetterTx, error) {
+	var result []*core.DeadLetterTx
+
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(deadLetterTxsBucket), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var deadLetterTx *core.DeadLetterTx
+
+		if err := json.Unmarshal(iter.Value(), &deadLetterTx); err != nil {
+			return nil, err
+		}
+
+		result = append(result, deadLetterTx)
+		if maxCnt > 0 && len(result) == maxCnt {
+			break
+		}
+	}
+
+	return result, iter.Error()
+}
+
+func (lvldb *LevelDBDatabase) PruneConfirmedBlocks(
+	isExpired func(block *core.CardanoBlock) bool, maxCnt int,
+) (int, error) {
+	if lvldb.isReadOnly() {
+		return 0, core.ErrReadOnlyDatabase
+	}
+
+	batch := new(leveldb.Batch)
+
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(confirmedBlocks), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var block *core.CardanoBlock
+
+		if err := json.Unmarshal(iter.Value(), &block); err != nil {
+			return 0, err
+		}
+
+		if !isExpired(block) {
+			break
+		}
+
+		batch.Delete(bytes.Clone(iter.Key()))
+
+		if maxCnt > 0 && batch.Len() == maxCnt {
+			break
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+
+	return lvldb.writeDeleteBatch(batch)
+}
+
+func (lvldb *LevelDBDatabase) PruneProcessedTxs(slotNumber uint64, maxCnt int) (int, error) {
+	if lvldb.isReadOnly() {
+		return 0, core.ErrReadOnlyDatabase
+	}
+
+	batch := new(leveldb.Batch)
+
+	// tx key starts with the slot number, so keys are ordered by slot
+	iter := lvldb.reader.NewIterator(&util.Range{
+		Start: bucketKey(processedTxsBucket, nil),
+		Limit: bucketKey(processedTxsBucket, core.SlotNumberToKey(slotNumber)),
+	}, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		batch.Delete(bytes.Clone(iter.Key()))
+
+		if maxCnt > 0 && batch.Len() == maxCnt {
+			break
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+
+	return lvldb.writeDeleteBatch(batch)
+}
+
+func (lvldb *LevelDBDatabase) IterateTxOutputs(handler func(txInputOutput *core.TxInputOutput) error) error {
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(txOutputsBucket), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var output core.TxOutput
+
+		if err := json.Unmarshal(iter.Value(), &output); err != nil {
+			return err
+		}
+
+		input, err := core.NewTxInputFromBytes(bucketKeyToKey(txOutputsBucket, iter.Key()))
+		if err != nil {
+			return err
+		}
+
+		if err := handler(&core.TxInputOutput{
+			Input:  input,
+			Output: output,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
+func (lvldb *LevelDBDatabase) IterateConfirmedBlocks(handler func(block *core.CardanoBlock) error) error {
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(confirmedBlocks), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var block *core.CardanoBlock
+
+		if err := json.Unmarshal(iter.Value(), &block); err != nil {
+			return err
+		}
+
+		if err := handler(block); err != nil {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
+func (lvldb *LevelDBDatabase) IterateConfirmedTxs(processed bool, handler func(tx *core.Tx) error) error {
+	bucketName := unprocessedTxsBucket
+	if processed {
+		bucketName = processedTxsBucket
+	}
+
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(bucketName), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var tx *core.Tx
+
+		if err := json.Unmarshal(iter.Value(), &tx); err != nil {
+			return err
+		}
+
+		if err := handler(tx); err != nil {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
+func (lvldb *LevelDBDatabase) IterateMintEvents(handler func(event *core.MintEvent) error) error {
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(mintEventsBucket), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var event *core.MintEvent
+
+		if err := json.Unmarshal(iter.Value(), &event); err != nil {
+			return err
+		}
+
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
+func (lvldb *LevelDBDatabase) IterateGovProposals(handler func(event *core.GovProposalEvent) error) error {
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(govProposalsBucket), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var event *core.GovProposalEvent
+
+		if err := json.Unmarshal(iter.Value(), &event); err != nil {
+			return err
+		}
+
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
+func (lvldb *LevelDBDatabase) IterateGovVotes(handler func(event *core.GovVoteEvent) error) error {
+	iter := lvldb.reader.NewIterator(util.BytesPrefix(govVotesBucket), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var event *core.GovVoteEvent
+
+		if err := json.Unmarshal(iter.Value(), &event); err != nil {
+			return err
+		}
+
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
 func (lvldb *LevelDBDatabase) OpenTx() core.DBTransactionWriter {
-	return NewLevelDBTransactionWriter(lvldb.db)
+	tw := NewLevelDBTransactionWriter(lvldb.db)
+	tw.readOnly = lvldb.isReadOnly()
+
+	return tw
 }
 
-func bucketKey(bucket []byte, key []byte) []byte {
-	const separator = "_#_"
+// getSnapshot returns consistent view of the database. Read-only database already reads from the snapshot
+func (lvldb *LevelDBDatabase) getSnapshot() (dbReader, func(), error) {
+	if lvldb.isReadOnly() {
+		return lvldb.snapshot, func() {}, nil
+	}
+
+	snapshot, err := lvldb.db.GetSnapshot()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create snapshot: %w", err)
+	}
+
+	return snapshot, snapshot.Release, nil
+}
+
+func (lvldb *LevelDBDatabase) getSubscriber(reader dbReader, name string) (*core.Subscriber, error) {
+	var subscriber *core.Subscriber
+
+	bytes, err := reader.Get(bucketKey(subscribersBucket, []byte(name)), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", core.ErrSubscriberNotFound, name)
+		}
+
+		return nil, err
+	}
+
+	if err := json.Unmarshal(bytes, &subscriber); err != nil {
+		return nil, err
+	}
+
+	return subscriber, nil
+}
 
-	outputKey := make([]byte, len(bucket)+len(separator)+len(key))
+func (lvldb *LevelDBDatabase) putSubscriber(subscriber *core.Subscriber) error {
+	bytes, err := json.Marshal(subscriber)
+	if err != nil {
+		return fmt.Errorf("could not marshal subscriber: %w", err)
+	}
+
+	return lvldb.db.Put(bucketKey(subscribersBucket, subscriber.Key()), bytes, &opt.WriteOptions{
+		Sync: true,
+	})
+}
+
+func (lvldb *LevelDBDatabase) writeDeleteBatch(batch *leveldb.Batch) (int, error) {
+	if batch.Len() == 0 {
+		return 0, nil
+	}
+
+	if err := lvldb.db.Write(batch, &opt.WriteOptions{
+		NoWriteMerge: false,
+		Sync:         true,
+	}); err != nil {
+		return 0, err
+	}
+
+	return batch.Len(), nil
+}
+
+func bucketKey(bucket []byte, key []byte) []byte {
+	outputKey := make([]byte, len(bucket)+len(bucketKeySeparator)+len(key))
 	copy(outputKey, bucket)
-	copy(outputKey[len(bucket):], []byte(separator))
-	copy(outputKey[len(bucket)+len(separator):], key)
+	copy(outputKey[len(bucket):], []byte(bucketKeySeparator))
+	copy(outputKey[len(bucket)+len(bucketKeySeparator):], key)
 
 	return outputKey
 }
 
+// slotRangeLimitKey returns the first key (exclusive limit) after all keys which start with the slot
+func slotRangeLimitKey(slot uint64) []byte {
+	if slot == math.MaxUint64 {
+		return bytes.Repeat([]byte{0xff}, 9)
+	}
+
+	return core.SlotNumberToKey(slot + 1)
+}
+
+func bucketKeyToKey(bucket []byte, key []byte) []byte {
+	return key[len(bucket)+len(bucketKeySeparator):]
+}
+
 func processNotFoundErr(err error) error {
 	if errors.Is(err, leveldb.ErrNotFound) {
 		return nil
@@ -0,0 +1,235 @@
+package leveldb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"github.com/igorcrevar/cardano-go-indexer/core"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+const (
+	DatabaseName = "leveldb"
+
+	restoreBatchSize = 1000
+	// corrupted backup must not force huge allocation. Stored values are much smaller
+	maxBackupItemSize = 64 << 20
+	// written instead of the key length after the last pair. It is never a valid item size
+	backupEndMarker = maxBackupItemSize + 1
+)
+
+// Backup writes the snapshot header followed by all key/value pairs of a leveldb snapshot.
+// Every pair is encoded as uvarint key length, key, uvarint value length, value.
+// Pairs are followed by the end marker, uvarint number of pairs and big endian CRC-32 (Castagnoli)
+// of the pairs and the marker, so truncated or corrupted backup is not restored
+func (lvldb *LevelDBDatabase) Backup(w io.Writer) error {
+	snapshot := lvldb.snapshot
+	if snapshot == nil {
+		newSnapshot, err := lvldb.db.GetSnapshot()
+		if err != nil {
+			return fmt.Errorf("could not create snapshot: %w", err)
+		}
+
+		defer newSnapshot.Release()
+
+		snapshot = newSnapshot
+	}
+
+	var point *core.BlockPoint
+
+	if bytes, err := snapshot.Get(latestBlockPointBucket, nil); err != nil {
+		if err := processNotFoundErr(err); err != nil {
+			return err
+		}
+	} else if err := json.Unmarshal(bytes, &point); err != nil {
+		return err
+	}
+
+	if err := core.WriteSnapshotHeader(w, core.SnapshotHeader{
+		Version:    core.SnapshotVersion,
+		Database:   DatabaseName,
+		BlockPoint: point,
+	}); err != nil {
+		return err
+	}
+
+	iter := snapshot.NewIterator(nil, &opt.ReadOptions{
+		DontFillCache: true,
+	})
+	defer iter.Release()
+
+	bw := bufio.NewWriter(w)
+	checksum := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	pw := io.MultiWriter(bw, checksum)
+	buf := make([]byte, 0, binary.MaxVarintLen64)
+	cnt := uint64(0)
+
+	for iter.Next() {
+		for _, data := range [][]byte{iter.Key(), iter.Value()} {
+			if _, err := pw.Write(binary.AppendUvarint(buf[:0], uint64(len(data)))); err != nil {
+				return fmt.Errorf("could not write backup: %w", err)
+			}
+
+			if _, err := pw.Write(data); err != nil {
+				return fmt.Errorf("could not write backup: %w", err)
+			}
+		}
+
+		cnt++
+	}
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	if _, err := pw.Write(binary.AppendUvarint(buf[:0], backupEndMarker)); err != nil {
+		return fmt.Errorf("could not write backup: %w", err)
+	}
+
+	trailer := binary.AppendUvarint(buf[:0], cnt)
+	trailer = binary.BigEndian.AppendUint32(trailer, checksum.Sum32())
+
+	if _, err := bw.Write(trailer); err != nil {
+		return fmt.Errorf("could not write backup: %w", err)
+	}
+
+	return bw.Flush()
+}
+
+// Restore creates a new database from the snapshot payload (snapshot header must be already read).
+// Payload without the trailer, or with wrong item count or checksum, is rejected and the database is removed
+func Restore(r io.Reader, filePath string) (err error) {
+	if _, err := os.Stat(filePath); err == nil {
+		return fmt.Errorf("database already exists: %s", filePath)
+	}
+
+	db, err := leveldb.OpenFile(filePath, nil)
+	if err != nil {
+		return fmt.Errorf("could not open db: %w", err)
+	}
+
+	defer func() {
+		err = errors.Join(err, db.Close())
+		if err != nil {
+			os.RemoveAll(filePath) //nolint:errcheck
+		}
+	}()
+
+	br := bufio.NewReader(r)
+	cr := &checksumReader{reader: br, checksum: crc32.New(crc32.MakeTable(crc32.Castagnoli))}
+	batch := new(leveldb.Batch)
+	cnt := uint64(0)
+
+	for {
+		keySize, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return fmt.Errorf("backup is truncated, end marker is missing: %w", err)
+		}
+
+		if keySize == backupEndMarker {
+			break
+		}
+
+		key, err := readBackupItem(cr, keySize)
+		if err != nil {
+			return fmt.Errorf("could not read backup key: %w", err)
+		}
+
+		valueSize, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return fmt.Errorf("could not read backup value: %w", err)
+		}
+
+		value, err := readBackupItem(cr, valueSize)
+		if err != nil {
+			return fmt.Errorf("could not read backup value: %w", err)
+		}
+
+		batch.Put(key, value)
+		cnt++
+
+		if batch.Len() >= restoreBatchSize {
+			if err := db.Write(batch, nil); err != nil {
+				return err
+			}
+
+			batch.Reset()
+		}
+	}
+
+	// checksum covers the pairs and the end marker, so the rest of the trailer is read directly
+	if err := verifyBackupTrailer(br, cnt, cr.checksum.Sum32()); err != nil {
+		return err
+	}
+
+	return db.Write(batch, &opt.WriteOptions{
+		Sync: true,
+	})
+}
+
+func verifyBackupTrailer(br *bufio.Reader, cnt uint64, checksum uint32) error {
+	trailerCnt, err := binary.ReadUvarint(br)
+	if err != nil {
+		return fmt.Errorf("could not read backup trailer: %w", err)
+	}
+
+	var trailerChecksum [4]byte
+
+	if _, err := io.ReadFull(br, trailerChecksum[:]); err != nil {
+		return fmt.Errorf("could not read backup trailer: %w", err)
+	}
+
+	if trailerCnt != cnt {
+		return fmt.Errorf("backup contains %d items instead of %d", cnt, trailerCnt)
+	}
+
+	if binary.BigEndian.Uint32(trailerChecksum[:]) != checksum {
+		return errors.New("backup checksum mismatch")
+	}
+
+	return nil
+}
+
+// checksumReader calculates checksum of all bytes read
+type checksumReader struct {
+	reader   *bufio.Reader
+	checksum hash.Hash32
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.checksum.Write(p[:n]) //nolint:errcheck
+
+	return n, err
+}
+
+func (r *checksumReader) ReadByte() (byte, error) {
+	b, err := r.reader.ReadByte()
+	if err == nil {
+		r.checksum.Write([]byte{b}) //nolint:errcheck
+	}
+
+	return b, err
+}
+
+func readBackupItem(r io.Reader, size uint64) ([]byte, error) {
+	if size > maxBackupItemSize {
+		return nil, fmt.Errorf("invalid backup item size: %d", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("could not read backup: %w", err)
+	}
+
+	return data, nil
+}
@@ -17,6 +17,7 @@ type txOperation func(*leveldb.DB, *leveldb.Batch) error
 type LevelDBTransactionWriter struct {
 	db         *leveldb.DB
 	operations []txOperation
+	readOnly   bool
 }
 
 var _ core.DBTransactionWriter = (*LevelDBTransactionWriter)(nil)
@@ -91,6 +92,137 @@ func (tw *LevelDBTransactionWriter) AddConfirmedTxs(txs []*core.Tx) core.DBTrans
 	return tw
 }
 
+func (tw *LevelDBTransactionWriter) AddMintEvents(events []*core.MintEvent) core.DBTransactionWriter {
+	tw.operations = append(tw.operations, func(db *leveldb.DB, batch *leveldb.Batch) error {
+		for _, event := range events {
+			bytes, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("could not marshal mint event: %w", err)
+			}
+
+			batch.Put(bucketKey(mintEventsBucket, event.Key()), bytes)
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *LevelDBTransactionWriter) AddGovProposals(events []*core.GovProposalEvent) core.DBTransactionWriter {
+	tw.operations = append(tw.operations, func(db *leveldb.DB, batch *leveldb.Batch) error {
+		for _, event := range events {
+			bytes, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("could not marshal governance proposal: %w", err)
+			}
+
+			batch.Put(bucketKey(govProposalsBucket, event.Key()), bytes)
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *LevelDBTransactionWriter) AddGovVotes(events []*core.GovVoteEvent) core.DBTransactionWriter {
+	tw.operations = append(tw.operations, func(db *leveldb.DB, batch *leveldb.Batch) error {
+		for _, event := range events {
+			bytes, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("could not marshal governance vote: %w", err)
+			}
+
+			key, err := event.Key()
+			if err != nil {
+				return fmt.Errorf("invalid governance vote: %w", err)
+			}
+
+			batch.Put(bucketKey(govVotesBucket, key), bytes)
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *LevelDBTransactionWriter) SetSubscriber(subscriber *core.Subscriber) core.DBTransactionWriter {
+	tw.operations = append(tw.operations, func(db *leveldb.DB, batch *leveldb.Batch) error {
+		bytes, err := json.Marshal(subscriber)
+		if err != nil {
+			return fmt.Errorf("could not marshal subscriber: %w", err)
+		}
+
+		batch.Put(bucketKey(subscribersBucket, subscriber.Key()), bytes)
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *LevelDBTransactionWriter) AddDeadLetterTxs(txs []*core.DeadLetterTx) core.DBTransactionWriter {
+	tw.operations = append(tw.operations, func(db *leveldb.DB, batch *leveldb.Batch) error {
+		for _, deadLetterTx := range txs {
+			bytes, err := json.Marshal(deadLetterTx)
+			if err != nil {
+				return fmt.Errorf("could not marshal dead-letter tx: %w", err)
+			}
+
+			batch.Put(bucketKey(deadLetterTxsBucket, deadLetterTx.Key()), bytes)
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *LevelDBTransactionWriter) DeleteDeadLetterTxs(txs []*core.DeadLetterTx) core.DBTransactionWriter {
+	tw.operations = append(tw.operations, func(db *leveldb.DB, batch *leveldb.Batch) error {
+		for _, deadLetterTx := range txs {
+			batch.Delete(bucketKey(deadLetterTxsBucket, deadLetterTx.Key()))
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *LevelDBTransactionWriter) DeleteConfirmedTxs(txs []*core.Tx) core.DBTransactionWriter {
+	tw.operations = append(tw.operations, func(db *leveldb.DB, batch *leveldb.Batch) error {
+		for _, tx := range txs {
+			batch.Delete(bucketKey(unprocessedTxsBucket, tx.Key()))
+			batch.Delete(bucketKey(processedTxsBucket, tx.Key()))
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
+func (tw *LevelDBTransactionWriter) MarkConfirmedTxsProcessed(txs []*core.Tx) core.DBTransactionWriter {
+	tw.operations = append(tw.operations, func(db *leveldb.DB, batch *leveldb.Batch) error {
+		for _, tx := range txs {
+			bytes, err := json.Marshal(tx)
+			if err != nil {
+				return fmt.Errorf("could not marshal tx: %w", err)
+			}
+
+			batch.Put(bucketKey(processedTxsBucket, tx.Key()), bytes)
+			batch.Delete(bucketKey(unprocessedTxsBucket, tx.Key()))
+		}
+
+		return nil
+	})
+
+	return tw
+}
+
 func (tw *LevelDBTransactionWriter) RemoveTxOutputs(
 	txInputs []*core.TxInput, softDelete bool,
 ) core.DBTransactionWriter {
@@ -157,6 +289,10 @@ func (tw *LevelDBTransactionWriter) Execute() error {
 		tw.operations = nil
 	}()
 
+	if tw.readOnly {
+		return core.ErrReadOnlyDatabase
+	}
+
 	batch := new(leveldb.Batch)
 
 	for _, op := range tw.operations {
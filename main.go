@@ -74,6 +74,13 @@ func main() {
 		return dbs.MarkConfirmedTxsProcessed(unprocessedTxs)
 	}
 
+	slotTimeConfig, err := core.GetSlotTimeConfig(networkMagic)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		logger.Error("Slot time config failed", "err", err)
+		os.Exit(1)
+	}
+
 	indexerConfig := &core.BlockIndexerConfig{
 		StartingBlockPoint: &core.BlockPoint{
 			BlockSlot: startSlot,
@@ -85,6 +92,7 @@ func main() {
 		SoftDeleteUtxo:          false,
 		KeepAllTxOutputsInDB:    false,
 		KeepAllTxsHashesInBlock: false,
+		SlotTime:                slotTimeConfig,
 	}
 	syncerConfig := &core.BlockSyncerConfig{
 		NetworkMagic:   networkMagic,
@@ -95,10 +103,19 @@ func main() {
 	}
 
 	indexer := core.NewBlockIndexer(indexerConfig, confirmedBlockHandler, dbs, logger.Named("block_indexer"))
+	defer indexer.Close()
 
 	syncer := core.NewBlockSyncer(syncerConfig, indexer, logger.Named("block_syncer"))
 	defer syncer.Close()
 
+	// pruning is disabled. Set KeepBlocksCount, KeepBlocksSlots or KeepProcessedTxsSlots to delete old data
+	pruner := core.NewPruner(&core.RetentionConfig{
+		PruneInterval: time.Minute * 10,
+	}, dbs, logger.Named("pruner"))
+	defer pruner.Close()
+
+	pruner.Start()
+
 	err = syncer.Sync()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)